@@ -1,8 +1,14 @@
 package main
 
 import (
+    "context"
     "log"
+    "net"
     "net/http"
+    "os"
+    "os/signal"
+    "syscall"
+    "time"
 
     "github.com/gin-gonic/gin"
 
@@ -10,6 +16,9 @@ import (
     "garp/api-gateway-go/internal/routes"
 )
 
+// shutdownTimeout is how long in-flight requests get to finish after SIGTERM/SIGINT.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
     // Load configuration from environment
     config := cfg.LoadFromEnv()
@@ -22,9 +31,40 @@ func main() {
     // Build router with health/readiness and proxy routes
     routes.Register(r, config)
 
+    // Every request context derives from rootCtx. Cancelling it closes upgraded (WebSocket)
+    // connections, which srv.Shutdown does not track.
+    rootCtx, cancelRoot := context.WithCancel(context.Background())
+    defer cancelRoot()
+
     addr := ":" + config.PortString()
-    log.Printf("Starting API Gateway on %s", addr)
-    if err := r.Run(addr); err != nil && err != http.ErrServerClosed {
-        log.Fatalf("gateway server error: %v", err)
+    srv := &http.Server{
+        Addr:        addr,
+        Handler:     r,
+        BaseContext: func(net.Listener) context.Context { return rootCtx },
     }
+
+    go func() {
+        log.Printf("Starting API Gateway on %s", addr)
+        if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+            log.Fatalf("gateway server error: %v", err)
+        }
+    }()
+
+    quit := make(chan os.Signal, 1)
+    signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+    sig := <-quit
+    log.Printf("Received %s, shutting down gateway", sig)
+
+    ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+    defer cancel()
+    // Stop accepting connections and drain in-flight requests
+    if err := srv.Shutdown(ctx); err != nil {
+        log.Printf("Gateway forced to shut down: %v", err)
+    } else {
+        log.Printf("In-flight requests drained")
+    }
+    log.Printf("Closing upgraded connections")
+    cancelRoot()
+
+    log.Printf("Gateway exiting")
 }
\ No newline at end of file
@@ -0,0 +1,185 @@
+package auth
+
+import (
+    "context"
+    "crypto/ecdsa"
+    "crypto/elliptic"
+    "crypto/rsa"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "math/big"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "golang.org/x/sync/singleflight"
+)
+
+const (
+    jwksTTL = 5 * time.Minute
+    // jwksMinRefresh stops tokens with unknown key IDs, and retries while the identity
+    // provider is down, from hammering it.
+    jwksMinRefresh = 30 * time.Second
+)
+
+var errUnknownKey = errors.New("no matching key in JWKS")
+
+// jwksCache discovers an issuer's JWKS endpoint and caches its public keys by key ID.
+// Fetches happen outside mu, and concurrent callers share one in-flight fetch.
+type jwksCache struct {
+    issuer string
+    client *http.Client
+    flight singleflight.Group
+
+    mu        sync.Mutex
+    jwksURI   string
+    keys      map[string]any
+    fetched   time.Time // last successful load
+    failed    time.Time // last failed load
+}
+
+func newJWKSCache(issuer string) *jwksCache {
+    return &jwksCache{issuer: strings.TrimSuffix(issuer, "/"), client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// key returns the public key for kid. A stale cache is refreshed first; refresh forces a
+// reload (subject to jwksMinRefresh) so rotated keys are picked up. After a failed load no
+// new attempt is made for jwksMinRefresh, so an identity provider outage costs one timeout
+// per interval rather than one per request.
+func (j *jwksCache) key(ctx context.Context, kid string, refresh bool) (any, error) {
+    j.mu.Lock()
+    age := time.Since(j.fetched)
+    stale := j.keys == nil || age > jwksTTL || (refresh && age > jwksMinRefresh)
+    backoff := time.Since(j.failed) < jwksMinRefresh
+    j.mu.Unlock()
+
+    var loadErr error
+    if stale && !backoff {
+        // Detached from ctx: the result is shared with every caller waiting on the flight
+        _, loadErr, _ = j.flight.Do("jwks", func() (any, error) {
+            err := j.load(context.WithoutCancel(ctx))
+            if err != nil {
+                j.mu.Lock()
+                j.failed = time.Now()
+                j.mu.Unlock()
+            }
+            return nil, err
+        })
+    }
+
+    // A failed load leaves the previous key set, which keeps being served while the
+    // provider is unreachable
+    j.mu.Lock()
+    defer j.mu.Unlock()
+    if j.keys == nil {
+        if loadErr != nil { return nil, loadErr }
+        return nil, errors.New("JWKS unavailable, retrying after backoff")
+    }
+    if k, ok := j.keys[kid]; ok { return k, nil }
+    if kid == "" && len(j.keys) == 1 {
+        for _, k := range j.keys { return k, nil }
+    }
+    return nil, errUnknownKey
+}
+
+// load fetches the discovery document (once) and the key set without holding j.mu.
+func (j *jwksCache) load(ctx context.Context) error {
+    j.mu.Lock()
+    jwksURI := j.jwksURI
+    j.mu.Unlock()
+
+    if jwksURI == "" {
+        var doc struct {
+            JWKSURI string `json:"jwks_uri"`
+        }
+        if err := j.getJSON(ctx, j.issuer+"/.well-known/openid-configuration", &doc); err != nil {
+            return fmt.Errorf("failed to fetch OIDC discovery document: %w", err)
+        }
+        if doc.JWKSURI == "" { return errors.New("OIDC discovery document has no jwks_uri") }
+        jwksURI = doc.JWKSURI
+        j.mu.Lock()
+        j.jwksURI = jwksURI
+        j.mu.Unlock()
+    }
+    var set struct {
+        Keys []jsonWebKey `json:"keys"`
+    }
+    if err := j.getJSON(ctx, jwksURI, &set); err != nil {
+        return fmt.Errorf("failed to fetch JWKS: %w", err)
+    }
+    keys := make(map[string]any, len(set.Keys))
+    for _, k := range set.Keys {
+        if k.Use != "" && k.Use != "sig" { continue }
+        pub, err := k.publicKey()
+        if err != nil { continue } // unsupported key types are skipped, not fatal
+        keys[k.Kid] = pub
+    }
+    j.mu.Lock()
+    j.keys = keys
+    j.fetched = time.Now()
+    j.mu.Unlock()
+    return nil
+}
+
+func (j *jwksCache) getJSON(ctx context.Context, url string, out any) error {
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil { return err }
+    resp, err := j.client.Do(req)
+    if err != nil { return err }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return fmt.Errorf("%s returned %d", url, resp.StatusCode)
+    }
+    return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// jsonWebKey holds the RFC 7517 fields needed for RSA and EC signature keys.
+type jsonWebKey struct {
+    Kty string `json:"kty"`
+    Kid string `json:"kid"`
+    Use string `json:"use"`
+    N   string `json:"n"`
+    E   string `json:"e"`
+    Crv string `json:"crv"`
+    X   string `json:"x"`
+    Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+    switch k.Kty {
+    case "RSA":
+        n, err := b64BigInt(k.N)
+        if err != nil { return nil, err }
+        e, err := b64BigInt(k.E)
+        if err != nil { return nil, err }
+        return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+    case "EC":
+        var curve elliptic.Curve
+        switch k.Crv {
+        case "P-256":
+            curve = elliptic.P256()
+        case "P-384":
+            curve = elliptic.P384()
+        case "P-521":
+            curve = elliptic.P521()
+        default:
+            return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+        }
+        x, err := b64BigInt(k.X)
+        if err != nil { return nil, err }
+        y, err := b64BigInt(k.Y)
+        if err != nil { return nil, err }
+        return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+    default:
+        return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+    }
+}
+
+func b64BigInt(s string) (*big.Int, error) {
+    b, err := base64.RawURLEncoding.DecodeString(s)
+    if err != nil { return nil, err }
+    return new(big.Int).SetBytes(b), nil
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+package auth
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "encoding/base64"
+    "encoding/json"
+    "errors"
+    "math/big"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+// jwksServer serves an OIDC discovery document and a JWKS holding one RSA key.
+type jwksServer struct {
+    *httptest.Server
+    pub     *rsa.PublicKey
+    fetches atomic.Int32
+    fail    atomic.Bool
+    gate    chan struct{} // when non-nil, JWKS responses wait for it to close
+}
+
+func newJWKSServer(t *testing.T) *jwksServer {
+    t.Helper()
+    priv, err := rsa.GenerateKey(rand.Reader, 2048)
+    if err != nil {
+        t.Fatal(err)
+    }
+    s := &jwksServer{pub: &priv.PublicKey}
+    mux := http.NewServeMux()
+    mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+        json.NewEncoder(w).Encode(map[string]string{"jwks_uri": s.URL + "/jwks"})
+    })
+    mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+        s.fetches.Add(1)
+        if s.gate != nil {
+            <-s.gate
+        }
+        if s.fail.Load() {
+            w.WriteHeader(http.StatusServiceUnavailable)
+            return
+        }
+        json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+            "kty": "RSA",
+            "kid": "k1",
+            "use": "sig",
+            "n":   base64.RawURLEncoding.EncodeToString(s.pub.N.Bytes()),
+            "e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(s.pub.E)).Bytes()),
+        }}})
+    })
+    s.Server = httptest.NewServer(mux)
+    t.Cleanup(s.Close)
+    return s
+}
+
+func TestJWKSCacheKey(t *testing.T) {
+    srv := newJWKSServer(t)
+    j := newJWKSCache(srv.URL)
+    ctx := context.Background()
+
+    k, err := j.key(ctx, "k1", false)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if pub, ok := k.(*rsa.PublicKey); !ok || !pub.Equal(srv.pub) {
+        t.Fatalf("key = %v, want the served RSA key", k)
+    }
+    if _, err := j.key(ctx, "k1", false); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := j.key(ctx, "other", true); !errors.Is(err, errUnknownKey) {
+        t.Fatalf("unknown kid error = %v, want errUnknownKey", err)
+    }
+    // Served from the cache, and the refresh is throttled by jwksMinRefresh
+    if n := srv.fetches.Load(); n != 1 {
+        t.Fatalf("JWKS fetched %d times, want 1", n)
+    }
+}
+
+func TestJWKSCacheSharesInFlightFetch(t *testing.T) {
+    srv := newJWKSServer(t)
+    srv.gate = make(chan struct{})
+    j := newJWKSCache(srv.URL)
+
+    var wg sync.WaitGroup
+    errs := make(chan error, 20)
+    for i := 0; i < cap(errs); i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            _, err := j.key(context.Background(), "k1", false)
+            errs <- err
+        }()
+    }
+    // Let every caller reach the fetch before the server answers
+    time.Sleep(100 * time.Millisecond)
+    close(srv.gate)
+    wg.Wait()
+    close(errs)
+    for err := range errs {
+        if err != nil {
+            t.Fatal(err)
+        }
+    }
+    if n := srv.fetches.Load(); n != 1 {
+        t.Fatalf("JWKS fetched %d times by concurrent callers, want 1", n)
+    }
+}
+
+func TestJWKSCacheBacksOffAfterFailure(t *testing.T) {
+    srv := newJWKSServer(t)
+    srv.fail.Store(true)
+    j := newJWKSCache(srv.URL)
+    ctx := context.Background()
+
+    if _, err := j.key(ctx, "k1", false); err == nil {
+        t.Fatal("expected an error while the provider is down")
+    }
+    start := time.Now()
+    if _, err := j.key(ctx, "k1", true); err == nil {
+        t.Fatal("expected an error during the backoff")
+    }
+    if d := time.Since(start); d > 50*time.Millisecond {
+        t.Fatalf("call during the backoff took %v", d)
+    }
+    if n := srv.fetches.Load(); n != 1 {
+        t.Fatalf("JWKS fetched %d times, want 1 until the backoff ends", n)
+    }
+
+    // Once the backoff has passed the provider is tried again
+    srv.fail.Store(false)
+    j.mu.Lock()
+    j.failed = time.Now().Add(-2 * jwksMinRefresh)
+    j.mu.Unlock()
+    if _, err := j.key(ctx, "k1", false); err != nil {
+        t.Fatal(err)
+    }
+}
+
+func TestJWKSCacheServesStaleKeysWhenProviderDown(t *testing.T) {
+    srv := newJWKSServer(t)
+    j := newJWKSCache(srv.URL)
+    ctx := context.Background()
+    if _, err := j.key(ctx, "k1", false); err != nil {
+        t.Fatal(err)
+    }
+
+    srv.fail.Store(true)
+    j.mu.Lock()
+    j.fetched = time.Now().Add(-2 * jwksTTL)
+    j.mu.Unlock()
+    if _, err := j.key(ctx, "k1", false); err != nil {
+        t.Fatalf("stale key not served while the provider is down: %v", err)
+    }
+    if n := srv.fetches.Load(); n != 2 {
+        t.Fatalf("JWKS fetched %d times, want 2", n)
+    }
+}
\ No newline at end of file
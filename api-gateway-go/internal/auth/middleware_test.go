@@ -0,0 +1,117 @@
+package auth
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v5"
+    "go.opentelemetry.io/otel/baggage"
+)
+
+func TestHMACEqual(t *testing.T) {
+    tests := []struct {
+        name            string
+        input, expected string
+        want            bool
+    }{
+        {name: "equal", input: "s3cret-api-key", expected: "s3cret-api-key", want: true},
+        {name: "unequal", input: "s3cret-api-kez", expected: "s3cret-api-key", want: false},
+        {name: "different lengths", input: "s3cret", expected: "s3cret-api-key", want: false},
+        {name: "prefix of expected", input: "s3cret-api-key", expected: "s3cret-api-key-2", want: false},
+        {name: "empty input", input: "", expected: "s3cret-api-key", want: false},
+        {name: "both empty", input: "", expected: "", want: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := hmacEqual(tt.input, tt.expected); got != tt.want {
+                t.Fatalf("hmacEqual(%q, %q) = %v, want %v", tt.input, tt.expected, got, tt.want)
+            }
+        })
+    }
+}
+
+// TestHMACEqualTiming checks that a mismatch in the first byte takes as long as one in the
+// last. The key is long so a short-circuiting comparison would differ by orders of magnitude;
+// the bound is loose enough for noisy CI machines.
+func TestHMACEqualTiming(t *testing.T) {
+    if testing.Short() {
+        t.Skip("timing test")
+    }
+    expected := strings.Repeat("k", 4096)
+    early := "x" + expected[1:]              // differs in the first byte
+    late := expected[:len(expected)-1] + "x" // differs in the last byte
+
+    // Best of several rounds, alternating inputs, to filter out scheduling noise
+    measure := func(input string) time.Duration {
+        start := time.Now()
+        for i := 0; i < 200; i++ {
+            hmacEqual(input, expected)
+        }
+        return time.Since(start)
+    }
+    bestEarly, bestLate := time.Duration(1<<63-1), time.Duration(1<<63-1)
+    for round := 0; round < 30; round++ {
+        bestEarly = min(bestEarly, measure(early))
+        bestLate = min(bestLate, measure(late))
+    }
+    if ratio := float64(bestLate) / float64(bestEarly); ratio > 2 || ratio < 0.5 {
+        t.Errorf("late mismatch took %v, early mismatch %v (ratio %.2f), want them about equal", bestLate, bestEarly, ratio)
+    }
+}
+
+func TestAuthMiddlewareBaggage(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    const secret = "test-secret"
+    tests := []struct {
+        name   string
+        claims jwt.MapClaims
+        want   string
+    }{
+        {name: "tenant and region", claims: jwt.MapClaims{"sub": "alice", "tenant": "acme", "region": "eu-west-1"}, want: "region=eu-west-1,tenant=acme"},
+        {name: "no business claims", claims: jwt.MapClaims{"sub": "alice"}},
+        {name: "non-string claim ignored", claims: jwt.MapClaims{"tenant": 42, "region": "us-east-1"}, want: "region=us-east-1"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, tt.claims).SignedString([]byte(secret))
+            if err != nil {
+                t.Fatal(err)
+            }
+            var got baggage.Baggage
+            r := gin.New()
+            r.Use(AuthMiddleware(secret, true))
+            r.GET("/api/v1/blocks", func(c *gin.Context) { got = baggage.FromContext(c.Request.Context()) })
+
+            req := httptest.NewRequest(http.MethodGet, "/api/v1/blocks", nil)
+            req.Header.Set("Authorization", "Bearer "+token)
+            req = req.WithContext(baggage.ContextWithBaggage(req.Context(), mustBaggage(t, "tenant=globex")))
+            w := httptest.NewRecorder()
+            r.ServeHTTP(w, req)
+            if w.Code != http.StatusOK {
+                t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+            }
+            want := mustBaggage(t, tt.want)
+            if got.Len() != want.Len() {
+                t.Fatalf("baggage = %q, want %q", got.String(), want.String())
+            }
+            for _, m := range want.Members() {
+                if v := got.Member(m.Key()).Value(); v != m.Value() {
+                    t.Errorf("baggage %s = %q, want %q", m.Key(), v, m.Value())
+                }
+            }
+        })
+    }
+}
+
+func mustBaggage(t *testing.T, s string) baggage.Baggage {
+    t.Helper()
+    b, err := baggage.Parse(s)
+    if err != nil {
+        t.Fatalf("parse baggage %q: %v", s, err)
+    }
+    return b
+}
\ No newline at end of file
@@ -1,23 +1,46 @@
 package config
 
 import (
+    "encoding/json"
+    "log"
     "os"
     "strings"
     "strconv"
+    "time"
 )
 
 // Config defines API gateway configuration.
 type Config struct {
     Port            int    // Gateway listen port (default 8080)
     BackendURL      string // Base URL for backend-go service
+    BackendURLs     []string // Optional backend-go replicas for health-checked failover; overrides BackendURL when set
+    UpstreamHealthInterval time.Duration // How often replicas in BackendURLs are health-checked (default 10s)
+    ProxyMaxIdleConnsPerHost   int           // Idle keep-alive connections kept per upstream (default 50)
+    ProxyIdleConnTimeout       time.Duration // How long an idle upstream connection is kept (default 90s)
+    ProxyTLSHandshakeTimeout   time.Duration // default 10s
+    ProxyResponseHeaderTimeout time.Duration // Max wait for upstream response headers; 0 means no limit beyond RequestTimeout
+    ProxyDisableKeepAlives     bool
     ParticipantURL  string // Base URL for participant-node service
     GlobalSyncURL   string // Optional base URL for global-synchronizer service
+    BackendClientCert string // Client certificate presented to backend-go; mTLS is used when cert, key and CA are all set
+    BackendClientKey  string
+    BackendCACert     string // CA that signed backend-go's server certificate
+    BackendGRPCAddr   string // host:port of backend-go's gRPC server, served to browsers as gRPC-web under /grpc; empty disables it
+    SSRFAllowedHosts  []string // Internal hosts/IPs the proxies may reach; defaults to the configured upstream hosts
     JWTSecret       string // Simple bearer token secret
-    AllowedOrigins  string // Comma-separated list of allowed origins for CORS; empty means "*"
+    OIDCIssuerURL   string // External OpenID Connect issuer; when set, bearer tokens are verified against its JWKS instead of JWTSecret
+    OIDCAudience    string // Required "aud" claim for OIDC tokens; empty skips the audience check
+    AllowedOrigins  string // Comma-separated CORS origins, "*.domain" wildcards or "regexp:" patterns; empty means "*"
     AllowCredentials bool  // Whether to allow credentials in CORS responses (only with specific origins)
     AuthRequired    bool   // Require authentication for non-health endpoints
     RedisURL        string // Redis URL for distributed rate limiting
     RateLimitRPM    int    // Requests per minute per IP
+    RequestTimeout  time.Duration // Per-request processing deadline (default 30s)
+    RouteRateLimitConfig map[string]int // Per-route RPM keyed by path prefix; 0 disables limiting for that prefix
+    CanaryBackendURL  string // Base URL of the canary backend-go deployment; empty disables canary routing
+    CanaryPercentage  int    // Share of /backend traffic (0-100) sent to the canary
+    CanaryHeader      string // Header that forces canary routing when set to CanaryHeaderValue
+    CanaryHeaderValue string
 }
 
 // LoadFromEnv constructs Config using environment variables with sensible defaults.
@@ -27,14 +50,34 @@ func LoadFromEnv() Config {
     return Config{
         Port:             port,
         BackendURL:       getenv("BACKEND_URL", "http://garp-backend:8081"),
+        BackendURLs:      getenvList("BACKEND_URLS"),
+        UpstreamHealthInterval: getenvDuration("UPSTREAM_HEALTH_INTERVAL", 10*time.Second),
+        ProxyMaxIdleConnsPerHost:   getenvInt("PROXY_MAX_IDLE_CONNS", 50),
+        ProxyIdleConnTimeout:       getenvDuration("PROXY_IDLE_CONN_TIMEOUT", 90*time.Second),
+        ProxyTLSHandshakeTimeout:   getenvDuration("PROXY_TLS_HANDSHAKE_TIMEOUT", 10*time.Second),
+        ProxyResponseHeaderTimeout: getenvDuration("PROXY_RESPONSE_HEADER_TIMEOUT", 0),
+        ProxyDisableKeepAlives:     getenvBool("PROXY_DISABLE_KEEPALIVES", false),
         ParticipantURL:   getenv("PARTICIPANT_URL", "http://garp-participant:8090"),
         GlobalSyncURL:    getenv("GLOBAL_SYNC_URL", ""),
+        BackendClientCert: getenv("BACKEND_CLIENT_CERT", ""),
+        BackendClientKey:  getenv("BACKEND_CLIENT_KEY", ""),
+        BackendCACert:     getenv("BACKEND_CA_CERT", ""),
+        BackendGRPCAddr:   getenv("BACKEND_GRPC_ADDR", ""),
+        SSRFAllowedHosts:  getenvList("SSRF_ALLOWED_HOSTS"),
         JWTSecret:        getenv("JWT_SECRET", ""),
+        OIDCIssuerURL:    getenv("OIDC_ISSUER_URL", ""),
+        OIDCAudience:     getenv("OIDC_AUDIENCE", ""),
         AllowedOrigins:   getenv("ALLOWED_ORIGINS", ""),
         AllowCredentials: getenvBool("CORS_ALLOW_CREDENTIALS", false),
         AuthRequired:     getenvBool("AUTH_REQUIRED", true),
         RedisURL:         getenv("REDIS_URL", "redis://redis:6379"),
         RateLimitRPM:     getenvInt("RATE_LIMIT_RPM", 100),
+        RequestTimeout:   getenvDuration("GATEWAY_REQUEST_TIMEOUT", 30*time.Second),
+        RouteRateLimitConfig: getenvIntMap("ROUTE_RATE_LIMITS"),
+        CanaryBackendURL:  getenv("CANARY_BACKEND_URL", ""),
+        CanaryPercentage:  clamp(getenvInt("CANARY_PERCENTAGE", 0), 0, 100),
+        CanaryHeader:      getenv("CANARY_HEADER", "X-Canary"),
+        CanaryHeaderValue: getenv("CANARY_HEADER_VALUE", "true"),
     }
 }
 
@@ -58,6 +101,28 @@ func getenvInt(key string, def int) int {
     return def
 }
 
+func getenvDuration(key string, def time.Duration) time.Duration {
+    if v := os.Getenv(key); v != "" {
+        if d, err := time.ParseDuration(v); err == nil && d > 0 {
+            return d
+        }
+    }
+    return def
+}
+
+// getenvIntMap parses a JSON object such as {"/backend/api/v1/transactions": 10, "/health": 0}.
+// Invalid JSON is logged and ignored.
+func getenvIntMap(key string) map[string]int {
+    v := strings.TrimSpace(os.Getenv(key))
+    if v == "" { return nil }
+    var m map[string]int
+    if err := json.Unmarshal([]byte(v), &m); err != nil {
+        log.Printf("config: ignoring invalid %s: %v", key, err)
+        return nil
+    }
+    return m
+}
+
 func getenvBool(key string, def bool) bool {
     v := strings.TrimSpace(os.Getenv(key))
     if v == "" { return def }
@@ -71,6 +136,21 @@ func getenvBool(key string, def bool) bool {
     }
 }
 
+// getenvList splits a comma-separated value, dropping empty entries.
+func getenvList(key string) []string {
+    var out []string
+    for _, v := range strings.Split(os.Getenv(key), ",") {
+        if v = strings.TrimSpace(v); v != "" { out = append(out, v) }
+    }
+    return out
+}
+
+func clamp(n, lo, hi int) int {
+    if n < lo { return lo }
+    if n > hi { return hi }
+    return n
+}
+
 // Minimal helpers to avoid external deps for trivial conversions
 func itoa(n int) string {
     return strconv.Itoa(n)
@@ -0,0 +1,75 @@
+package middleware
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+    "net/netip"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// ErrForbiddenDestination is returned by CheckDestination when an upstream resolves to an
+// internal address that is not on the allow list.
+var ErrForbiddenDestination = errors.New("destination address not allowed")
+
+// cgnat (RFC 6598) is not covered by netip's IsPrivate but is just as internal.
+var cgnat = netip.MustParsePrefix("100.64.0.0/10")
+
+type ssrfPolicyKey struct{}
+
+type ssrfPolicy struct {
+    allowed map[string]bool
+}
+
+// SSRFGuard attaches a destination policy to each request. Proxies built by the services
+// package enforce it on every outgoing call: the target host is resolved and the call is
+// refused with 403 if any address is private, loopback, link-local or unspecified, unless the
+// host (or the literal IP) appears in allowedHosts.
+func SSRFGuard(allowedHosts []string) gin.HandlerFunc {
+    p := &ssrfPolicy{allowed: make(map[string]bool, len(allowedHosts))}
+    for _, h := range allowedHosts {
+        if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+            p.allowed[h] = true
+        }
+    }
+    return func(c *gin.Context) {
+        c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), ssrfPolicyKey{}, p))
+        c.Next()
+    }
+}
+
+// CheckDestination applies the policy installed by SSRFGuard to host. Requests that did not
+// pass through SSRFGuard are not checked.
+func CheckDestination(ctx context.Context, host string) error {
+    p, ok := ctx.Value(ssrfPolicyKey{}).(*ssrfPolicy)
+    if !ok {
+        return nil
+    }
+    host = strings.ToLower(strings.Trim(host, "[]"))
+    if p.allowed[host] {
+        return nil
+    }
+    var addrs []netip.Addr
+    if ip, err := netip.ParseAddr(host); err == nil {
+        addrs = []netip.Addr{ip}
+    } else {
+        resolved, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+        if err != nil {
+            return fmt.Errorf("failed to resolve %s: %w", host, err)
+        }
+        addrs = resolved
+    }
+    for _, ip := range addrs {
+        ip = ip.Unmap()
+        if p.allowed[ip.String()] {
+            continue
+        }
+        if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() || cgnat.Contains(ip) {
+            return fmt.Errorf("%w: %s resolves to %s", ErrForbiddenDestination, host, ip)
+        }
+    }
+    return nil
+}
\ No newline at end of file
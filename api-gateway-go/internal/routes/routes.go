@@ -2,9 +2,16 @@ package routes
 
 import (
     "context"
+    "errors"
+    "fmt"
     "log"
     "net/http"
+    "net/url"
+    "regexp"
+    "sort"
     "strings"
+    "sync"
+    "sync/atomic"
     "time"
     "strconv"
 
@@ -13,6 +20,7 @@ import (
 
     "garp/api-gateway-go/internal/auth"
     "garp/api-gateway-go/internal/config"
+    "garp/api-gateway-go/internal/middleware"
     "garp/api-gateway-go/internal/services"
 )
 
@@ -28,15 +36,46 @@ func SecurityHeadersMiddleware() gin.HandlerFunc {
     }
 }
 
-// CORSMiddleware adds CORS headers to responses, honoring configured allowed origins and credentials
+// CORSMiddleware adds CORS headers to responses, honoring configured allowed origins and credentials.
+// AllowedOrigins entries may be exact origins, "*.example.com" to allow any subdomain of
+// example.com (but not example.com itself), or "regexp:<pattern>" matched against the Origin
+// header (anchor it with ^ and $). Patterns are compiled once here; preflights from other origins get 403.
 func CORSMiddleware(cfg config.Config) gin.HandlerFunc {
     // Pre-process allowed origins list
     var allowed []string
+    var suffixes []string
+    var patterns []*regexp.Regexp
     for _, p := range strings.Split(cfg.AllowedOrigins, ",") {
         t := strings.TrimSpace(p)
-        if t != "" { allowed = append(allowed, t) }
+        switch {
+        case t == "":
+        case strings.HasPrefix(t, "regexp:"):
+            patterns = append(patterns, regexp.MustCompile(strings.TrimPrefix(t, "regexp:")))
+        case strings.HasPrefix(t, "*."):
+            suffixes = append(suffixes, t[1:]) // keep the dot: "*.example.com" -> ".example.com"
+        default:
+            allowed = append(allowed, t)
+        }
+    }
+    allowAll := len(allowed) == 0 && len(suffixes) == 0 && len(patterns) == 0
+    originAllowed := func(origin string) bool {
+        if origin == "" { return false }
+        for _, o := range allowed {
+            if origin == o { return true }
+        }
+        // Compare suffixes on the host so "https://a.example.com:8443" matches "*.example.com";
+        // the label before the suffix must be non-empty, so the bare domain does not match.
+        host := origin
+        if i := strings.Index(host, "://"); i >= 0 { host = host[i+3:] }
+        host, _, _ = strings.Cut(host, ":")
+        for _, sfx := range suffixes {
+            if strings.HasSuffix(host, sfx) && len(host) > len(sfx) { return true }
+        }
+        for _, re := range patterns {
+            if re.MatchString(origin) { return true }
+        }
+        return false
     }
-    allowAll := len(allowed) == 0
     return func(c *gin.Context) {
         origin := c.GetHeader("Origin")
         var allowOrigin string
@@ -44,14 +83,9 @@ func CORSMiddleware(cfg config.Config) gin.HandlerFunc {
         if allowAll {
             allowOrigin = "*"
             matched = origin != ""
-        } else {
-            for _, o := range allowed {
-                if origin == o {
-                    allowOrigin = origin
-                    matched = true
-                    break
-                }
-            }
+        } else if originAllowed(origin) {
+            allowOrigin = origin
+            matched = true
         }
         if allowOrigin != "" {
             c.Header("Access-Control-Allow-Origin", allowOrigin)
@@ -66,6 +100,10 @@ func CORSMiddleware(cfg config.Config) gin.HandlerFunc {
         }
 
         if c.Request.Method == http.MethodOptions {
+            if origin != "" && !matched {
+                c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"success": false, "error": "origin not allowed"})
+                return
+            }
             c.AbortWithStatus(http.StatusNoContent)
             return
         }
@@ -74,6 +112,18 @@ func CORSMiddleware(cfg config.Config) gin.HandlerFunc {
     }
 }
 
+// RequestIDMiddleware assigns each request a UUID (reusing a valid incoming X-Request-ID),
+// stores it in the gin context under services.RequestIDKey and echoes it in the response.
+func RequestIDMiddleware() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := services.RequestID(c.GetHeader(services.RequestIDHeader))
+        c.Set(services.RequestIDKey, id)
+        c.Request.Header.Set(services.RequestIDHeader, id)
+        c.Header(services.RequestIDHeader, id)
+        c.Next()
+    }
+}
+
 // LoggingMiddleware logs request details
 func LoggingMiddleware() gin.HandlerFunc {
     return func(c *gin.Context) {
@@ -96,139 +146,311 @@ func LoggingMiddleware() gin.HandlerFunc {
             path = path + "?" + raw
         }
 
-        log.Printf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+        log.Printf("[GIN] %v | %3d | %13v | %15s | %-7s %#v | %s\n%s",
             end.Format("2006/01/02 - 15:04:05"),
             statusCode,
             latency,
             clientIP,
             method,
             path,
+            c.GetString(services.RequestIDKey),
             errorMessage,
         )
     }
 }
 
-// RateLimitMiddleware limits requests per IP
-func RateLimitMiddleware(cfg config.Config) gin.HandlerFunc {
-    // Distributed rate limit using Redis per IP per minute
-    rpm := cfg.RateLimitRPM
-    if rpm <= 0 { rpm = 100 }
-
-    var rdb *redis.Client
-    if cfg.RedisURL != "" {
-        if opt, err := redis.ParseURL(cfg.RedisURL); err == nil {
-            rdb = redis.NewClient(opt)
-        } else {
-            log.Printf("rate limit: invalid Redis URL: %v", err)
+// RequestTimeoutMiddleware attaches a deadline of d to each request's context so proxied
+// calls are cancelled once it passes, and answers 503 if nothing was written by then.
+func RequestTimeoutMiddleware(d time.Duration) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if d <= 0 {
+            c.Next()
+            return
+        }
+        ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+        defer cancel()
+        c.Request = c.Request.WithContext(ctx)
+        c.Next()
+        if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+            c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": "request timed out"})
         }
     }
+}
 
-    // Fallback in-memory limiter if Redis unavailable
-    if rdb == nil {
-        log.Printf("rate limit: Redis unavailable, using local in-memory limiter")
-        var visitors = make(map[string][]time.Time)
-        return func(c *gin.Context) {
-            ip := c.ClientIP()
-            route := c.FullPath()
-            now := time.Now()
-            key := route + "|" + ip
-            if times, exists := visitors[key]; exists {
-                var validTimes []time.Time
-                for _, t := range times {
-                    if now.Sub(t) < time.Minute { validTimes = append(validTimes, t) }
-                }
-                visitors[key] = validTimes
-            }
-            if len(visitors[key]) >= rpm {
-                c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
-                return
-            }
-            visitors[key] = append(visitors[key], now)
-            c.Next()
-        }
+// rateLimiter counts requests per key in one-minute windows, in Redis when available so
+// limits hold across gateway replicas, otherwise in local memory.
+type rateLimiter struct {
+    rdb    *redis.Client
+    script *redis.Script
+
+    mu       sync.Mutex
+    visitors map[string][]time.Time
+}
+
+// newRedisClient returns nil when redisURL is empty or invalid so callers fall back to local state.
+func newRedisClient(redisURL string) *redis.Client {
+    if redisURL == "" { return nil }
+    opt, err := redis.ParseURL(redisURL)
+    if err != nil {
+        log.Printf("invalid Redis URL: %v", err)
+        return nil
     }
+    return redis.NewClient(opt)
+}
 
+func newRateLimiter(redisURL string) *rateLimiter {
+    l := &rateLimiter{rdb: newRedisClient(redisURL)}
+    if l.rdb == nil {
+        // Fallback in-memory limiter if Redis unavailable
+        log.Printf("rate limit: Redis unavailable, using local in-memory limiter")
+        l.visitors = make(map[string][]time.Time)
+        return l
+    }
     // Lua script ensures atomic increment with TTL
-    script := redis.NewScript(`
+    l.script = redis.NewScript(`
         local count = redis.call('INCR', KEYS[1])
         if count == 1 then
             redis.call('EXPIRE', KEYS[1], ARGV[1])
         end
         return count
     `)
+    return l
+}
+
+// allow records a request for scope and ip and reports whether it is within rpm.
+func (l *rateLimiter) allow(scope, ip string, rpm int) bool {
+    now := time.Now()
+    if l.rdb == nil {
+        key := scope + "|" + ip
+        l.mu.Lock()
+        defer l.mu.Unlock()
+        var validTimes []time.Time
+        for _, t := range l.visitors[key] {
+            if now.Sub(t) < time.Minute { validTimes = append(validTimes, t) }
+        }
+        if len(validTimes) >= rpm {
+            l.visitors[key] = validTimes
+            return false
+        }
+        l.visitors[key] = append(validTimes, now)
+        return true
+    }
+
+    // Key per minute bucket
+    bucket := now.Unix() / 60
+    key := "rl:" + scope + ":" + ip + ":" + strconv.FormatInt(bucket, 10)
 
+    // Execute script: TTL 60 seconds
+    res, err := l.script.Run(context.Background(), l.rdb, []string{key}, 60).Result()
+    if err != nil {
+        // Soft-fail: allow request but log
+        log.Printf("rate limit: redis error: %v", err)
+        return true
+    }
+    count, _ := res.(int64)
+    return int(count) <= rpm
+}
+
+// RateLimitMiddleware limits requests per IP
+func RateLimitMiddleware(cfg config.Config) gin.HandlerFunc {
+    // Distributed rate limit using Redis per IP per minute
+    return globalRateLimit(newRateLimiter(cfg.RedisURL), cfg.RateLimitRPM)
+}
+
+func globalRateLimit(limiter *rateLimiter, rpm int) gin.HandlerFunc {
+    if rpm <= 0 { rpm = 100 }
     return func(c *gin.Context) {
-        ip := c.ClientIP()
-        route := c.FullPath()
-        // Key per minute bucket
-        now := time.Now()
-        bucket := now.Unix() / 60
-        key := "rl:" + route + ":" + ip + ":" + strconv.FormatInt(bucket, 10)
-
-        // Execute script: TTL 60 seconds
-        ctx := context.Background()
-        res, err := script.Run(ctx, rdb, []string{key}, 60).Result()
-        if err != nil {
-            // Soft-fail: allow request but log
-            log.Printf("rate limit: redis error: %v", err)
+        if !limiter.allow(c.FullPath(), c.ClientIP(), rpm) {
+            c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+            return
+        }
+        c.Next()
+    }
+}
+
+// RouteRateLimitMiddleware applies the RPM of the longest prefix in cfg.RouteRateLimitConfig
+// matching the request path, and the global RateLimitMiddleware limit when none matches.
+// A configured limit of 0 or less turns rate limiting off for that prefix.
+func RouteRateLimitMiddleware(cfg config.Config) gin.HandlerFunc {
+    limiter := newRateLimiter(cfg.RedisURL)
+    global := globalRateLimit(limiter, cfg.RateLimitRPM)
+    prefixes := make([]string, 0, len(cfg.RouteRateLimitConfig))
+    for p := range cfg.RouteRateLimitConfig { prefixes = append(prefixes, p) }
+    // Longest first so the most specific prefix wins
+    sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+    return func(c *gin.Context) {
+        path := c.Request.URL.Path
+        for _, p := range prefixes {
+            if !matchesRoutePrefix(path, p) { continue }
+            rpm := cfg.RouteRateLimitConfig[p]
+            if rpm > 0 && !limiter.allow("route:"+p, c.ClientIP(), rpm) {
+                c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+                return
+            }
             c.Next()
             return
         }
-        count, _ := res.(int64)
-        if int(count) > rpm {
-            c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+        global(c)
+    }
+}
+
+// matchesRoutePrefix reports whether path is prefix or lies below it ("/a" matches "/a/b" but not "/ab").
+func matchesRoutePrefix(path, prefix string) bool {
+    if !strings.HasPrefix(path, prefix) { return false }
+    return len(path) == len(prefix) || strings.HasSuffix(prefix, "/") || path[len(prefix)] == '/'
+}
+
+// CanaryMiddleware proxies to canaryProxy when the request carries headerName: headerValue, and
+// otherwise sends percentage% of requests there and the rest to primaryProxy. The split uses a
+// per-minute request counter in Redis so all gateway replicas share it (a local counter when
+// rdb is nil). percentage 0 routes only header-tagged requests to the canary. The upstream path
+// is taken from the route's *path parameter, as in the other proxy routes.
+func CanaryMiddleware(primaryProxy, canaryProxy http.Handler, headerName, headerValue string, percentage int, rdb *redis.Client) gin.HandlerFunc {
+    var local atomic.Int64
+    next := func(ctx context.Context) int64 {
+        if rdb != nil {
+            key := "canary:" + strconv.FormatInt(time.Now().Unix()/60, 10)
+            pipe := rdb.TxPipeline()
+            incr := pipe.Incr(ctx, key)
+            pipe.Expire(ctx, key, 2*time.Minute)
+            if _, err := pipe.Exec(ctx); err == nil {
+                return incr.Val()
+            }
+            log.Printf("canary: redis error, using local counter")
+        }
+        return local.Add(1)
+    }
+    return func(c *gin.Context) {
+        target := primaryProxy
+        if headerName != "" && c.GetHeader(headerName) == headerValue {
+            target = canaryProxy
+        } else if percentage > 0 && (next(c.Request.Context())-1)%100 < int64(percentage) {
+            target = canaryProxy
+        }
+        if target == nil {
+            c.JSON(http.StatusBadGateway, gin.H{"success": false, "error": "backend not configured"})
             return
         }
-        c.Next()
+        if target == canaryProxy {
+            c.Header("X-Canary", "true")
+        }
+        c.Request.URL.Path = ensureLeadingSlash(c.Param("path"))
+        target.ServeHTTP(c.Writer, c.Request)
     }
 }
 
 // Register wires up health/readiness and proxy routes.
 func Register(r *gin.Engine, cfg config.Config) {
     // Add global middleware
+    r.Use(RequestIDMiddleware())
     r.Use(LoggingMiddleware())
     r.Use(SecurityHeadersMiddleware())
     r.Use(CORSMiddleware(cfg))
-    r.Use(RateLimitMiddleware(cfg))
+    if len(cfg.RouteRateLimitConfig) > 0 {
+        r.Use(RouteRateLimitMiddleware(cfg))
+    } else {
+        r.Use(RateLimitMiddleware(cfg))
+    }
+    r.Use(RequestTimeoutMiddleware(cfg.RequestTimeout))
+    r.Use(middleware.SSRFGuard(ssrfAllowedHosts(cfg)))
     r.Use(gin.Recovery())
 
     // Health and readiness
     r.GET("/health", func(c *gin.Context) {
         c.JSON(http.StatusOK, gin.H{"status": "ok"})
     })
+    checks := readinessChecks(cfg)
     r.GET("/ready", func(c *gin.Context) {
-        c.JSON(http.StatusOK, gin.H{"status": "ready"})
+        report, ok := services.HealthReport(c.Request.Context(), checks)
+        if !ok {
+            c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "status": "unavailable", "checks": report})
+            return
+        }
+        c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": report})
     })
 
     // Global middleware: bearer auth (skips health/ready)
-    r.Use(auth.AuthMiddleware(cfg.JWTSecret, cfg.AuthRequired))
+    if cfg.OIDCIssuerURL != "" {
+        r.Use(auth.OIDCAuthMiddleware(cfg.OIDCIssuerURL, cfg.OIDCAudience, cfg.AuthRequired))
+    } else {
+        r.Use(auth.AuthMiddleware(cfg.JWTSecret, cfg.AuthRequired))
+    }
 
     // Prepare reverse proxies
-    backendProxy, err := services.NewReverseProxy(cfg.BackendURL, "backend")
+    proxyCfg := services.ProxyConfig{
+        MaxIdleConnsPerHost:   cfg.ProxyMaxIdleConnsPerHost,
+        IdleConnTimeout:       cfg.ProxyIdleConnTimeout,
+        TLSHandshakeTimeout:   cfg.ProxyTLSHandshakeTimeout,
+        ResponseHeaderTimeout: cfg.ProxyResponseHeaderTimeout,
+        DisableKeepAlives:     cfg.ProxyDisableKeepAlives,
+    }
+    backendProxy, err := services.NewReverseProxyWithMTLS(cfg.BackendURL, "backend", proxyCfg, cfg.BackendClientCert, cfg.BackendClientKey, cfg.BackendCACert)
     if err != nil {
         log.Printf("Failed to create backend proxy: %v", err)
     }
-    participantProxy, err := services.NewReverseProxy(cfg.ParticipantURL, "participant")
+    participantProxy, err := services.NewReverseProxy(cfg.ParticipantURL, "participant", proxyCfg)
     if err != nil {
         log.Printf("Failed to create participant proxy: %v", err)
     }
-    syncProxy, err := services.NewReverseProxy(cfg.GlobalSyncURL, "sync")
+    syncProxy, err := services.NewReverseProxy(cfg.GlobalSyncURL, "sync", proxyCfg)
     if err != nil {
         log.Printf("Failed to create sync proxy: %v", err)
     }
 
-    // Backend proxy: /backend/*path -> BACKEND_URL/*path
-    r.Any("/backend/*path", func(c *gin.Context) {
-        if backendProxy == nil {
-            c.JSON(http.StatusBadGateway, gin.H{"success": false, "error": "backend not configured"})
-            return
+    // Backend replicas: BACKEND_URLS fails over between health-checked targets
+    var backend http.Handler
+    if backendProxy != nil {
+        backend = backendProxy
+    }
+    circuits := map[string]*services.MultiTargetProxy{}
+    if len(cfg.BackendURLs) > 0 {
+        var targets []*services.ProxyService
+        for i, u := range cfg.BackendURLs {
+            p, err := services.NewReverseProxyWithMTLS(u, fmt.Sprintf("backend-%d", i), proxyCfg, cfg.BackendClientCert, cfg.BackendClientKey, cfg.BackendCACert)
+            if err != nil {
+                log.Printf("Failed to create backend proxy for %s: %v", u, err)
+                continue
+            }
+            targets = append(targets, p)
         }
-        // Rewrite URL path to upstream
-        upstreamPath := ensureLeadingSlash(c.Param("path"))
-        c.Request.URL.Path = upstreamPath
-        backendProxy.ServeHTTP(c.Writer, c.Request)
+        if len(targets) > 0 {
+            multi := services.NewMultiTargetProxy(targets, cfg.UpstreamHealthInterval)
+            go multi.Run(context.Background()) // checks run for the life of the process
+            backend = multi
+            circuits["backend"] = multi
+        }
+    }
+    // Upstream health as seen by the failover checks
+    r.GET("/health/circuits", func(c *gin.Context) {
+        out := make(map[string]map[string]bool, len(circuits))
+        for name, m := range circuits {
+            out[name] = m.HealthStatus()
+        }
+        c.JSON(http.StatusOK, out)
     })
 
+    // Backend proxy: /backend/*path -> BACKEND_URL/*path, or CANARY_BACKEND_URL for canary traffic
+    canaryProxy, err := services.NewReverseProxyWithMTLS(cfg.CanaryBackendURL, "backend-canary", proxyCfg, cfg.BackendClientCert, cfg.BackendClientKey, cfg.BackendCACert)
+    if err != nil {
+        log.Printf("Failed to create canary backend proxy: %v", err)
+    }
+    if canaryProxy != nil {
+        log.Printf("Canary routing enabled: %d%% of /backend traffic, or header %s: %s", cfg.CanaryPercentage, cfg.CanaryHeader, cfg.CanaryHeaderValue)
+        r.Any("/backend/*path", CanaryMiddleware(backend, canaryProxy, cfg.CanaryHeader, cfg.CanaryHeaderValue, cfg.CanaryPercentage, newRedisClient(cfg.RedisURL)))
+    } else {
+        r.Any("/backend/*path", func(c *gin.Context) {
+            if backend == nil {
+                c.JSON(http.StatusBadGateway, gin.H{"success": false, "error": "backend not configured"})
+                return
+            }
+            // Rewrite URL path to upstream
+            upstreamPath := ensureLeadingSlash(c.Param("path"))
+            c.Request.URL.Path = upstreamPath
+            backend.ServeHTTP(c.Writer, c.Request)
+        })
+    }
+
     // Participant proxy: /participant/*path -> PARTICIPANT_URL/*path
     r.Any("/participant/*path", func(c *gin.Context) {
         if participantProxy == nil {
@@ -250,6 +472,65 @@ func Register(r *gin.Engine, cfg config.Config) {
         c.Request.URL.Path = upstreamPath
         syncProxy.ServeHTTP(c.Writer, c.Request)
     })
+
+    // gRPC-web (optional): /grpc/<service>/<method> -> BACKEND_GRPC_ADDR as plain gRPC.
+    // The gateway sets no body size limit, so streaming calls are bounded only by gRPC flow control.
+    if cfg.BackendGRPCAddr != "" {
+        grpcWeb, err := services.NewGRPCWebProxy(cfg.BackendGRPCAddr)
+        if err != nil {
+            log.Printf("Failed to create gRPC-web proxy: %v", err)
+        } else {
+            r.POST("/grpc/*path", func(c *gin.Context) {
+                // application/grpc-web and application/grpc-web+proto (and -text) only
+                if !strings.HasPrefix(c.ContentType(), "application/grpc-web") {
+                    c.JSON(http.StatusUnsupportedMediaType, gin.H{"success": false, "error": "expected a gRPC-web request"})
+                    return
+                }
+                grpcWeb.ServeHTTP(c.Writer, c.Request)
+            })
+        }
+    }
+}
+
+// readinessChecks probes Redis (when configured) and the /health endpoint of each configured upstream.
+func readinessChecks(cfg config.Config) map[string]services.HealthCheck {
+    hc := &http.Client{Timeout: 5 * time.Second}
+    checks := make(map[string]services.HealthCheck)
+    if rdb := newRedisClient(cfg.RedisURL); rdb != nil {
+        checks["redis"] = func(ctx context.Context) error { return rdb.Ping(ctx).Err() }
+    }
+    if cfg.BackendURL != "" {
+        backendHC := hc
+        if cfg.BackendClientCert != "" && cfg.BackendClientKey != "" && cfg.BackendCACert != "" {
+            if tlsCfg, err := services.ClientTLSConfig(cfg.BackendClientCert, cfg.BackendClientKey, cfg.BackendCACert); err == nil {
+                backendHC = &http.Client{Timeout: hc.Timeout, Transport: &http.Transport{TLSClientConfig: tlsCfg}}
+            }
+        }
+        checks["backend"] = services.HTTPHealthCheck(backendHC, cfg.BackendURL, "/health")
+    }
+    if cfg.ParticipantURL != "" {
+        checks["participant"] = services.HTTPHealthCheck(hc, cfg.ParticipantURL, "/health")
+    }
+    if cfg.GlobalSyncURL != "" {
+        checks["synchronizer"] = services.HTTPHealthCheck(hc, cfg.GlobalSyncURL, "/api/v1/status")
+    }
+    return checks
+}
+
+// ssrfAllowedHosts returns SSRF_ALLOWED_HOSTS, or the hosts of the configured upstreams,
+// every BACKEND_URLS replica included, when it is unset (they normally live on a private network).
+func ssrfAllowedHosts(cfg config.Config) []string {
+    if len(cfg.SSRFAllowedHosts) > 0 {
+        return cfg.SSRFAllowedHosts
+    }
+    upstreams := append([]string{cfg.BackendURL, cfg.ParticipantURL, cfg.GlobalSyncURL, cfg.CanaryBackendURL}, cfg.BackendURLs...)
+    var hosts []string
+    for _, raw := range upstreams {
+        if u, err := url.Parse(raw); err == nil && u.Hostname() != "" {
+            hosts = append(hosts, u.Hostname())
+        }
+    }
+    return hosts
 }
 
 func ensureLeadingSlash(p string) string {
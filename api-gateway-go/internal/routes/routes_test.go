@@ -0,0 +1,42 @@
+package routes
+
+import (
+    "reflect"
+    "testing"
+
+    "garp/api-gateway-go/internal/config"
+)
+
+func TestSSRFAllowedHosts(t *testing.T) {
+    tests := []struct {
+        name string
+        cfg  config.Config
+        want []string
+    }{
+        {
+            name: "explicit list wins",
+            cfg:  config.Config{SSRFAllowedHosts: []string{"internal.example"}, BackendURL: "http://backend:8080"},
+            want: []string{"internal.example"},
+        },
+        {
+            name: "single backend",
+            cfg:  config.Config{BackendURL: "http://backend:8080", ParticipantURL: "http://participant:7575"},
+            want: []string{"backend", "participant"},
+        },
+        {
+            name: "every backend replica",
+            cfg: config.Config{
+                BackendURL:  "http://backend:8080",
+                BackendURLs: []string{"http://backend-a:8080", "https://backend-b.internal:8443", "not a url"},
+            },
+            want: []string{"backend", "backend-a", "backend-b.internal"},
+        },
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            if got := ssrfAllowedHosts(tt.cfg); !reflect.DeepEqual(got, tt.want) {
+                t.Errorf("ssrfAllowedHosts = %v, want %v", got, tt.want)
+            }
+        })
+    }
+}
\ No newline at end of file
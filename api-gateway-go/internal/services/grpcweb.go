@@ -0,0 +1,121 @@
+package services
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "io"
+    "net/http"
+
+    "github.com/improbable-eng/grpc-web/go/grpcweb"
+    "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/credentials/insecure"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+)
+
+// NewGRPCWebProxy returns a handler that accepts gRPC-web calls (application/grpc-web,
+// application/grpc-web+proto) and forwards them as standard gRPC to the backend gRPC
+// server at backendGRPCAddr. Messages are relayed without being decoded, so backend
+// services added later need no gateway change. The request path may carry a prefix such
+// as /grpc; only the trailing /<service>/<method> is used.
+func NewGRPCWebProxy(backendGRPCAddr string) (http.Handler, error) {
+    if backendGRPCAddr == "" {
+        return nil, errors.New("backend gRPC address is required")
+    }
+    conn, err := grpc.NewClient(backendGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+    if err != nil {
+        return nil, fmt.Errorf("failed to create gRPC client for %s: %w", backendGRPCAddr, err)
+    }
+    srv := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.UnknownServiceHandler(forwardStream(conn)))
+    return grpcweb.WrapServer(srv, grpcweb.WithAllowNonRootResource(true)), nil
+}
+
+// frame is one undecoded gRPC message.
+type frame struct{ data []byte }
+
+// rawCodec relays message bytes untouched. It reports itself as "proto" so the backend
+// sees an ordinary protobuf call.
+type rawCodec struct{}
+
+func (rawCodec) Name() string { return "proto" }
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+    f, ok := v.(*frame)
+    if !ok {
+        return nil, fmt.Errorf("raw codec: unexpected message type %T", v)
+    }
+    return f.data, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+    f, ok := v.(*frame)
+    if !ok {
+        return fmt.Errorf("raw codec: unexpected message type %T", v)
+    }
+    f.data = append(f.data[:0], data...)
+    return nil
+}
+
+// forwardStream relays any call, unary or streaming, to the same method on conn, with
+// the caller's metadata. Headers, trailers and the final status come back unchanged.
+func forwardStream(conn *grpc.ClientConn) grpc.StreamHandler {
+    return func(_ any, in grpc.ServerStream) error {
+        method, ok := grpc.MethodFromServerStream(in)
+        if !ok {
+            return status.Error(codes.Internal, "gRPC method not found in stream context")
+        }
+        ctx, cancel := context.WithCancel(in.Context())
+        defer cancel()
+        if md, ok := metadata.FromIncomingContext(ctx); ok {
+            ctx = metadata.NewOutgoingContext(ctx, md.Copy())
+        }
+        out, err := conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true, ClientStreams: true}, method, grpc.ForceCodec(rawCodec{}))
+        if err != nil {
+            return err
+        }
+
+        // Client -> backend; CloseSend once the caller is done sending. On any other error
+        // the backend call is cancelled, which ends the receive loop below.
+        go func() {
+            for {
+                f := &frame{}
+                if err := in.RecvMsg(f); err != nil {
+                    if errors.Is(err, io.EOF) {
+                        _ = out.CloseSend()
+                    } else {
+                        cancel()
+                    }
+                    return
+                }
+                if err := out.SendMsg(f); err != nil {
+                    // io.EOF here means the backend ended the call; RecvMsg reports its status
+                    return
+                }
+            }
+        }()
+
+        // Backend -> client, headers first
+        header, err := out.Header()
+        if err != nil {
+            return err
+        }
+        if err := in.SendHeader(header); err != nil {
+            return err
+        }
+        for {
+            f := &frame{}
+            if err := out.RecvMsg(f); err != nil {
+                in.SetTrailer(out.Trailer())
+                if errors.Is(err, io.EOF) {
+                    return nil
+                }
+                return err // the backend's status, passed through as is
+            }
+            if err := in.SendMsg(f); err != nil {
+                return err
+            }
+        }
+    }
+}
\ No newline at end of file
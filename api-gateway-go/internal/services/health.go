@@ -0,0 +1,79 @@
+package services
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "net/http"
+    "sync"
+    "time"
+)
+
+// healthCheckTimeout bounds each dependency probe in HealthReport.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck probes one dependency and returns nil when it is usable.
+type HealthCheck func(ctx context.Context) error
+
+// HealthStatus is the outcome of a single dependency probe.
+type HealthStatus struct {
+    OK      bool
+    Latency time.Duration
+    Error   string
+}
+
+// MarshalJSON reports latency in milliseconds, matching the backend's /ready payload.
+func (h HealthStatus) MarshalJSON() ([]byte, error) {
+    return json.Marshal(struct {
+        OK        bool    `json:"ok"`
+        LatencyMS float64 `json:"latency_ms"`
+        Error     string  `json:"error,omitempty"`
+    }{h.OK, float64(h.Latency.Microseconds()) / 1000, h.Error})
+}
+
+// HTTPHealthCheck returns a check that GETs base+path and expects a 2xx response.
+func HTTPHealthCheck(client *http.Client, base, path string) HealthCheck {
+    url := singleJoiningSlash(base, path)
+    return func(ctx context.Context) error {
+        req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+        if err != nil { return err }
+        resp, err := client.Do(req)
+        if err != nil { return err }
+        resp.Body.Close()
+        if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+            return fmt.Errorf("%s returned %d", path, resp.StatusCode)
+        }
+        return nil
+    }
+}
+
+// HealthReport runs every check concurrently, each with its own timeout.
+// The boolean result is true only when all checks passed.
+func HealthReport(ctx context.Context, checks map[string]HealthCheck) (map[string]HealthStatus, bool) {
+    var (
+        mu     sync.Mutex
+        wg     sync.WaitGroup
+        report = make(map[string]HealthStatus, len(checks))
+    )
+    for name, check := range checks {
+        wg.Add(1)
+        go func(name string, check HealthCheck) {
+            defer wg.Done()
+            cctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+            defer cancel()
+            start := time.Now()
+            err := check(cctx)
+            st := HealthStatus{OK: err == nil, Latency: time.Since(start)}
+            if err != nil { st.Error = err.Error() }
+            mu.Lock()
+            report[name] = st
+            mu.Unlock()
+        }(name, check)
+    }
+    wg.Wait()
+    ok := true
+    for _, st := range report {
+        if !st.OK { ok = false }
+    }
+    return report, ok
+}
\ No newline at end of file
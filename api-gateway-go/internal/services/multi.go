@@ -0,0 +1,96 @@
+package services
+
+import (
+    "context"
+    "log"
+    "net/http"
+    "sync/atomic"
+    "time"
+)
+
+// DefaultHealthCheckInterval is used when MultiTargetProxy.HealthCheckInterval is zero.
+const DefaultHealthCheckInterval = 10 * time.Second
+
+// MultiTargetProxy spreads requests round-robin over several upstream replicas, skipping
+// any whose /health endpoint failed the last check. Targets start out healthy so traffic
+// flows before the first check completes.
+type MultiTargetProxy struct {
+    HealthCheckInterval time.Duration
+
+    targets []*ProxyService
+    healthy []atomic.Bool
+    next    atomic.Uint64
+}
+
+// NewMultiTargetProxy wraps targets; nil entries are dropped. Call Run to start health checks.
+func NewMultiTargetProxy(targets []*ProxyService, interval time.Duration) *MultiTargetProxy {
+    m := &MultiTargetProxy{HealthCheckInterval: interval}
+    for _, t := range targets {
+        if t != nil {
+            m.targets = append(m.targets, t)
+        }
+    }
+    m.healthy = make([]atomic.Bool, len(m.targets))
+    for i := range m.healthy {
+        m.healthy[i].Store(true)
+    }
+    return m
+}
+
+// Run checks every target immediately and then on each interval until ctx is cancelled.
+func (m *MultiTargetProxy) Run(ctx context.Context) {
+    interval := m.HealthCheckInterval
+    if interval <= 0 {
+        interval = DefaultHealthCheckInterval
+    }
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        m.checkAll(ctx, interval)
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+    }
+}
+
+func (m *MultiTargetProxy) checkAll(ctx context.Context, timeout time.Duration) {
+    for i, t := range m.targets {
+        client := &http.Client{Transport: t.transport, Timeout: min(timeout, 5*time.Second)}
+        err := HTTPHealthCheck(client, t.target.String(), "/health")(ctx)
+        if was := m.healthy[i].Swap(err == nil); was != (err == nil) {
+            if err != nil {
+                log.Printf("Upstream %s marked unhealthy: %v", t.name, err)
+            } else {
+                log.Printf("Upstream %s healthy again", t.name)
+            }
+        }
+    }
+}
+
+// ServeHTTP proxies to the next healthy target in round-robin order, or answers 503 when
+// none is healthy.
+func (m *MultiTargetProxy) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    n := uint64(len(m.targets))
+    start := m.next.Add(1) - 1
+    for i := uint64(0); i < n; i++ {
+        idx := (start + i) % n
+        if m.healthy[idx].Load() {
+            m.targets[idx].ServeHTTP(w, r)
+            return
+        }
+    }
+    w.Header().Set("Content-Type", "application/json")
+    w.WriteHeader(http.StatusServiceUnavailable)
+    w.Write([]byte(`{"success": false, "error": "no healthy upstream"}`))
+}
+
+// HealthStatus reports the last health check result for each target, keyed by target URL.
+func (m *MultiTargetProxy) HealthStatus() map[string]bool {
+    out := make(map[string]bool, len(m.targets))
+    for i, t := range m.targets {
+        out[t.target.String()] = m.healthy[i].Load()
+    }
+    return out
+}
\ No newline at end of file
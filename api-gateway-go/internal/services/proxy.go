@@ -1,27 +1,120 @@
 package services
 
 import (
+    "context"
+    "crypto/tls"
+    "crypto/x509"
+    "errors"
     "fmt"
     "log"
     "net/http"
     "net/http/httputil"
     "net/url"
+    "os"
     "strings"
     "time"
+
+    "github.com/google/uuid"
+    "go.opentelemetry.io/otel/propagation"
+
+    "garp/api-gateway-go/internal/middleware"
 )
 
+// RequestIDHeader carries the request ID to upstreams and back to the client.
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDKey is the gin context key holding the request ID.
+const RequestIDKey = "request_id"
+
 // ProxyService wraps httputil.ReverseProxy with additional functionality
 type ProxyService struct {
-    proxy  *httputil.ReverseProxy
-    target *url.URL
-    name   string
+    proxy     *httputil.ReverseProxy
+    target    *url.URL
+    name      string
+    transport http.RoundTripper
+}
+
+// DefaultMaxIdleConnsPerHost replaces net/http's default of 2, which causes connection
+// churn when the gateway carries sustained load to a single upstream.
+const DefaultMaxIdleConnsPerHost = 50
+
+// ProxyConfig tunes the HTTP transport used for an upstream. Zero values keep the
+// http.DefaultTransport settings, except MaxIdleConnsPerHost which defaults to
+// DefaultMaxIdleConnsPerHost.
+type ProxyConfig struct {
+    MaxIdleConnsPerHost   int
+    IdleConnTimeout       time.Duration
+    TLSHandshakeTimeout   time.Duration
+    ResponseHeaderTimeout time.Duration
+    DisableKeepAlives     bool
+}
+
+func (pc ProxyConfig) transport() *http.Transport {
+    tr := http.DefaultTransport.(*http.Transport).Clone()
+    tr.MaxIdleConnsPerHost = DefaultMaxIdleConnsPerHost
+    if pc.MaxIdleConnsPerHost > 0 {
+        tr.MaxIdleConnsPerHost = pc.MaxIdleConnsPerHost
+    }
+    // The total pool must be able to hold every host's idle connections
+    tr.MaxIdleConns = max(tr.MaxIdleConns, tr.MaxIdleConnsPerHost)
+    if pc.IdleConnTimeout > 0 {
+        tr.IdleConnTimeout = pc.IdleConnTimeout
+    }
+    if pc.TLSHandshakeTimeout > 0 {
+        tr.TLSHandshakeTimeout = pc.TLSHandshakeTimeout
+    }
+    if pc.ResponseHeaderTimeout > 0 {
+        tr.ResponseHeaderTimeout = pc.ResponseHeaderTimeout
+    }
+    tr.DisableKeepAlives = pc.DisableKeepAlives
+    return tr
 }
 
 // NewReverseProxy creates a reverse proxy to the given base URL.
-func NewReverseProxy(base, name string) (*ProxyService, error) {
+func NewReverseProxy(base, name string, pc ProxyConfig) (*ProxyService, error) {
+    return newReverseProxy(base, name, pc.transport())
+}
+
+// NewReverseProxyWithMTLS creates a reverse proxy that presents a client certificate to the
+// upstream and verifies the upstream against caFile. If any of the files is empty it falls back
+// to a plain proxy, so mTLS stays opt-in per deployment.
+func NewReverseProxyWithMTLS(base, name string, pc ProxyConfig, certFile, keyFile, caFile string) (*ProxyService, error) {
+    if base == "" || certFile == "" || keyFile == "" || caFile == "" {
+        return newReverseProxy(base, name, pc.transport())
+    }
+    tlsCfg, err := ClientTLSConfig(certFile, keyFile, caFile)
+    if err != nil {
+        return nil, err
+    }
+    tr := pc.transport()
+    tr.TLSClientConfig = tlsCfg
+    return newReverseProxy(base, name, tr)
+}
+
+// ClientTLSConfig loads a client certificate/key pair and the CA used to verify the upstream.
+func ClientTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+    cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+    if err != nil {
+        return nil, err
+    }
+    caCert, err := os.ReadFile(caFile)
+    if err != nil {
+        return nil, err
+    }
+    caPool := x509.NewCertPool()
+    if !caPool.AppendCertsFromPEM(caCert) {
+        return nil, fmt.Errorf("failed to append CA cert")
+    }
+    return &tls.Config{Certificates: []tls.Certificate{cert}, RootCAs: caPool, MinVersion: tls.VersionTLS12}, nil
+}
+
+// newReverseProxy builds the proxy. Outgoing requests are checked against the
+// middleware.SSRFGuard policy once the director has pointed them at the upstream.
+func newReverseProxy(base, name string, tr *http.Transport) (*ProxyService, error) {
     if base == "" {
         return nil, nil
     }
+    var transport http.RoundTripper = guardedTransport{tr}
     target, err := url.Parse(base)
     if err != nil {
         return nil, err
@@ -40,13 +133,30 @@ func NewReverseProxy(base, name string) (*ProxyService, error) {
         // Add identification header
         req.Header.Set("X-Forwarded-By", "GARP-API-Gateway")
         req.Header.Set("X-Forwarded-For", req.RemoteAddr)
+        // Forward W3C baggage (tenant, region, ...) that the auth middleware built from the
+        // validated token. The client's own header is dropped so it cannot assert its own.
+        req.Header.Del("baggage")
+        propagation.Baggage{}.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
     }
 
     // Create reverse proxy with custom error handler
     proxy := &httputil.ReverseProxy{
-        Director: director,
+        Director:  director,
+        Transport: transport,
         ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
             log.Printf("Proxy error for %s: %v", name, err)
+            if errors.Is(err, middleware.ErrForbiddenDestination) {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusForbidden)
+                w.Write([]byte(`{"success": false, "error": "upstream destination not allowed"}`))
+                return
+            }
+            if errors.Is(r.Context().Err(), context.DeadlineExceeded) {
+                w.Header().Set("Content-Type", "application/json")
+                w.WriteHeader(http.StatusServiceUnavailable)
+                w.Write([]byte(`{"success": false, "error": "request timed out"}`))
+                return
+            }
             w.WriteHeader(http.StatusBadGateway)
             w.Write([]byte(fmt.Sprintf("{\"error\": \"upstream service %s error: %v\"}", name, err)))
         },
@@ -58,24 +168,37 @@ func NewReverseProxy(base, name string) (*ProxyService, error) {
     }
 
     return &ProxyService{
-        proxy:  proxy,
-        target: target,
-        name:   name,
+        proxy:     proxy,
+        target:    target,
+        name:      name,
+        transport: transport,
     }, nil
 }
 
 // ServeHTTP implements the http.Handler interface
 func (p *ProxyService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
     start := time.Now()
-    log.Printf("Proxying request to %s: %s %s", p.name, r.Method, r.URL.Path)
-    
-    // Add request ID for tracing
-    r.Header.Set("X-Request-ID", generateRequestID())
-    
+    // Add request ID for tracing, keeping one the client or gateway already assigned
+    id := RequestID(r.Header.Get(RequestIDHeader))
+    r.Header.Set(RequestIDHeader, id)
+    log.Printf("Proxying request to %s: %s %s request_id=%s", p.name, r.Method, r.URL.Path, id)
+
     p.proxy.ServeHTTP(w, r)
-    
+
     duration := time.Since(start)
-    log.Printf("Completed proxy request to %s in %v", p.name, duration)
+    log.Printf("Completed proxy request to %s in %v request_id=%s", p.name, duration, id)
+}
+
+// guardedTransport refuses requests whose destination fails middleware.CheckDestination.
+type guardedTransport struct {
+    next http.RoundTripper
+}
+
+func (t guardedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+    if err := middleware.CheckDestination(req.Context(), req.URL.Hostname()); err != nil {
+        return nil, err
+    }
+    return t.next.RoundTrip(req)
 }
 
 func singleJoiningSlash(a, b string) string {
@@ -90,7 +213,13 @@ func singleJoiningSlash(a, b string) string {
     return a + b
 }
 
-// generateRequestID creates a simple request ID (in production, use a proper UUID library)
-func generateRequestID() string {
-    return fmt.Sprintf("%d", time.Now().UnixNano())
+// RequestID returns incoming in canonical form when it is a valid UUID, so clients can
+// correlate gateway logs with their own; otherwise it returns a new random (v4) UUID.
+func RequestID(incoming string) string {
+    if incoming != "" && len(incoming) <= 64 {
+        if u, err := uuid.Parse(incoming); err == nil {
+            return u.String()
+        }
+    }
+    return uuid.NewString()
 }
\ No newline at end of file
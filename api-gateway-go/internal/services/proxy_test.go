@@ -0,0 +1,71 @@
+package services
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "go.opentelemetry.io/otel/baggage"
+)
+
+func TestProxyForwardsBaggage(t *testing.T) {
+    ctxBaggage, err := baggage.Parse("tenant=acme,region=eu-west-1")
+    if err != nil {
+        t.Fatal(err)
+    }
+    tests := []struct {
+        name    string
+        baggage *baggage.Baggage // attached to the request context
+        header  string           // sent by the client
+        want    baggage.Baggage
+    }{
+        {name: "from context", baggage: &ctxBaggage, want: ctxBaggage},
+        {name: "client header is dropped", header: "tenant=globex"},
+        {name: "context replaces client header", baggage: &ctxBaggage, header: "tenant=globex,role=admin", want: ctxBaggage},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            received := make(chan string, 1)
+            upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+                received <- r.Header.Get("baggage")
+            }))
+            defer upstream.Close()
+            p, err := NewReverseProxy(upstream.URL, "test", ProxyConfig{})
+            if err != nil {
+                t.Fatal(err)
+            }
+
+            req := httptest.NewRequest(http.MethodGet, "/api/v1/blocks", nil)
+            if tt.header != "" {
+                req.Header.Set("baggage", tt.header)
+            }
+            if tt.baggage != nil {
+                req = req.WithContext(baggage.ContextWithBaggage(req.Context(), *tt.baggage))
+            }
+            w := httptest.NewRecorder()
+            p.ServeHTTP(w, req)
+            if w.Code != http.StatusOK {
+                t.Fatalf("proxy returned %d: %s", w.Code, w.Body.String())
+            }
+
+            got := mustParseBaggage(t, <-received)
+            for _, m := range tt.want.Members() {
+                if v := got.Member(m.Key()).Value(); v != m.Value() {
+                    t.Errorf("upstream baggage %s = %q, want %q", m.Key(), v, m.Value())
+                }
+            }
+            if got.Len() != tt.want.Len() {
+                t.Errorf("upstream baggage = %q, want %q", got.String(), tt.want.String())
+            }
+        })
+    }
+}
+
+func mustParseBaggage(t *testing.T, s string) baggage.Baggage {
+    t.Helper()
+    b, err := baggage.Parse(s)
+    if err != nil {
+        t.Fatalf("parse baggage %q: %v", s, err)
+    }
+    return b
+}
\ No newline at end of file
@@ -0,0 +1,329 @@
+// Command admin is an operator CLI for the backend's /admin API.
+//
+//	ADMIN_TOKEN=... admin state get-tx 0xabc --output table
+//
+// The backend URL comes from --backend-url or BACKEND_URL (default http://localhost:8081).
+// circuit-breaker status reads the API gateway's /health/circuits, since the breakers live
+// in the gateway; it uses --gateway-url or GATEWAY_URL and sends GATEWAY_TOKEN if set.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+type options struct {
+	backendURL string
+	gatewayURL string
+	output     string
+	timeout    time.Duration
+}
+
+func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	opts := &options{}
+	root := &cobra.Command{
+		Use:          "admin",
+		Short:        "Operate a GARP backend through its admin API",
+		SilenceUsage: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			if opts.output != "json" && opts.output != "table" {
+				return fmt.Errorf("--output must be json or table, got %q", opts.output)
+			}
+			return nil
+		},
+	}
+	root.PersistentFlags().StringVar(&opts.backendURL, "backend-url", getenv("BACKEND_URL", "http://localhost:8081"), "backend base URL")
+	root.PersistentFlags().StringVar(&opts.gatewayURL, "gateway-url", getenv("GATEWAY_URL", "http://localhost:8080"), "API gateway base URL (circuit-breaker status)")
+	root.PersistentFlags().StringVarP(&opts.output, "output", "o", "json", "output format: json or table")
+	root.PersistentFlags().DurationVar(&opts.timeout, "timeout", 30*time.Second, "request timeout")
+
+	state := &cobra.Command{Use: "state", Short: "Inspect in-memory transaction state"}
+	state.AddCommand(&cobra.Command{
+		Use:   "get-tx <hash>",
+		Short: "Show a transaction held in memory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return opts.run(cmd, http.MethodGet, opts.backendURL, "/admin/state/transactions/"+url.PathEscape(args[0]), nil)
+		},
+	})
+	state.AddCommand(&cobra.Command{
+		Use:   "evict-tx <hash>",
+		Short: "Evict a transaction from memory",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if _, err := opts.call(cmd.Context(), http.MethodDelete, opts.backendURL, "/admin/state/transactions/"+url.PathEscape(args[0]), nil, os.Getenv("ADMIN_TOKEN")); err != nil {
+				return err
+			}
+			return opts.print(cmd.OutOrStdout(), map[string]any{"hash": args[0], "evicted": true})
+		},
+	})
+
+	var olderThan string
+	archive := &cobra.Command{
+		Use:   "archive",
+		Short: "Move old indexed transactions to the archive database",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			d, err := parseAge(olderThan)
+			if err != nil {
+				return err
+			}
+			return opts.run(cmd, http.MethodDelete, opts.backendURL, "/admin/db/archive?older_than="+url.QueryEscape(d.String()), nil)
+		},
+	}
+	archive.Flags().StringVar(&olderThan, "older-than", "365d", "archive transactions older than this age, e.g. 90d or 2160h")
+	storageCmd := &cobra.Command{Use: "storage", Short: "Manage indexed transaction storage"}
+	storageCmd.AddCommand(archive)
+
+	rateLimit := &cobra.Command{Use: "rate-limit", Short: "Manage the runtime rate limit override"}
+	rateLimit.AddCommand(&cobra.Command{
+		Use:   "set <rpm>",
+		Short: "Override the per-client requests per minute; 0 reverts to the configured limit",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			rpm, err := strconv.Atoi(args[0])
+			if err != nil || rpm < 0 {
+				return fmt.Errorf("rpm must be a non-negative integer, got %q", args[0])
+			}
+			return opts.run(cmd, http.MethodPut, opts.backendURL, "/admin/rate-limit", map[string]int{"rpm": rpm})
+		},
+	})
+
+	circuitBreaker := &cobra.Command{Use: "circuit-breaker", Short: "Inspect upstream circuit breakers"}
+	circuitBreaker.AddCommand(&cobra.Command{
+		Use:   "status",
+		Short: "Show upstream health as seen by the gateway's failover checks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			body, err := opts.call(cmd.Context(), http.MethodGet, opts.gatewayURL, "/health/circuits", nil, os.Getenv("GATEWAY_TOKEN"))
+			if err != nil {
+				return err
+			}
+			return opts.printRaw(cmd.OutOrStdout(), body)
+		},
+	})
+
+	migrate := &cobra.Command{Use: "migrate", Short: "Manage database migrations"}
+	migrate.AddCommand(&cobra.Command{
+		Use:   "run",
+		Short: "Apply database migrations",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return opts.run(cmd, http.MethodPost, opts.backendURL, "/admin/migrations/run", nil)
+		},
+	})
+
+	root.AddCommand(state, storageCmd, rateLimit, circuitBreaker, migrate)
+	return root
+}
+
+// run calls an admin endpoint with ADMIN_TOKEN and prints the response.
+func (o *options) run(cmd *cobra.Command, method, base, path string, in any) error {
+	body, err := o.call(cmd.Context(), method, base, path, in, os.Getenv("ADMIN_TOKEN"))
+	if err != nil {
+		return err
+	}
+	return o.printRaw(cmd.OutOrStdout(), body)
+}
+
+// call sends one request and returns the response body. Non-2xx responses become errors
+// carrying the server's "error" message when there is one.
+func (o *options) call(ctx context.Context, method, base, path string, in any, token string) ([]byte, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(ctx, o.timeout)
+	defer cancel()
+	var reqBody io.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(base, "/")+path, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 16<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		var e struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(body, &e) == nil && e.Error != "" {
+			return nil, fmt.Errorf("%s %s: %s: %s", method, path, resp.Status, e.Error)
+		}
+		return nil, fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	return body, nil
+}
+
+func (o *options) printRaw(w io.Writer, body []byte) error {
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return fmt.Errorf("invalid JSON response: %w", err)
+	}
+	return o.print(w, v)
+}
+
+func (o *options) print(w io.Writer, v any) error {
+	if o.output == "table" {
+		return printTable(w, v)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+// printTable renders a list of objects as rows with one column per key, and any other
+// object as KEY/VALUE rows. An object holding a list of objects (e.g. "transactions")
+// prints its other fields first, then the list as rows.
+func printTable(w io.Writer, v any) error {
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	switch v := v.(type) {
+	case []any:
+		writeRows(tw, v)
+	case map[string]any:
+		var listKey string
+		for _, k := range sortedKeys(v) {
+			if rows, ok := v[k].([]any); ok && isObjectList(rows) && listKey == "" {
+				listKey = k
+				continue
+			}
+			fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(k), cell(v[k]))
+		}
+		if listKey != "" {
+			fmt.Fprintln(tw)
+			writeRows(tw, v[listKey].([]any))
+		}
+	default:
+		fmt.Fprintln(tw, cell(v))
+	}
+	return tw.Flush()
+}
+
+func writeRows(tw *tabwriter.Writer, rows []any) {
+	seen := map[string]any{}
+	for _, r := range rows {
+		if m, ok := r.(map[string]any); ok {
+			for k := range m {
+				seen[k] = nil
+			}
+		}
+	}
+	cols := sortedKeys(seen)
+	if len(cols) == 0 {
+		for _, r := range rows {
+			fmt.Fprintln(tw, cell(r))
+		}
+		return
+	}
+	header := make([]string, len(cols))
+	for i, c := range cols {
+		header[i] = strings.ToUpper(c)
+	}
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, r := range rows {
+		m, _ := r.(map[string]any)
+		vals := make([]string, len(cols))
+		for i, c := range cols {
+			vals[i] = cell(m[c])
+		}
+		fmt.Fprintln(tw, strings.Join(vals, "\t"))
+	}
+}
+
+func isObjectList(rows []any) bool {
+	for _, r := range rows {
+		if _, ok := r.(map[string]any); !ok {
+			return false
+		}
+	}
+	return len(rows) > 0
+}
+
+// cell formats a JSON value for one table cell; nested values stay compact JSON.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "-"
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// parseAge accepts Go durations plus a whole-day suffix, e.g. "90d".
+func parseAge(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err == nil && n > 0 {
+			return time.Duration(n) * 24 * time.Hour, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, errors.New("--older-than must be a positive age, e.g. 90d or 2160h")
+	}
+	return d, nil
+}
+
+func getenv(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
\ No newline at end of file
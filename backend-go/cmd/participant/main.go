@@ -1,125 +1,1555 @@
 package main
 
 import (
+    "bytes"
+    "compress/gzip"
     "context"
+    "crypto/sha256"
+    "crypto/tls"
+    "crypto/x509"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
     "fmt"
-    "log"
+    "io"
+    "log/slog"
+    "math"
     "net/http"
+    "net/http/httptest"
+    "net/http/httputil"
+    "net/url"
     "os"
     "os/signal"
+    "reflect"
+    "slices"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
     "syscall"
     "time"
 
     "github.com/gin-gonic/gin"
+    "github.com/redis/go-redis/v9"
+    "golang.org/x/crypto/acme/autocert"
+    "golang.org/x/sync/errgroup"
 
+    "garp-backend/docs"
+    "garp-backend/internal/anchor"
+    apimodel "garp-backend/internal/api"
     "garp-backend/internal/client"
     "garp-backend/internal/config"
+    "garp-backend/internal/governance"
+    grpcserver "garp-backend/internal/grpc"
+    "garp-backend/internal/integration"
+    "garp-backend/internal/logger"
     "garp-backend/internal/middleware"
     "garp-backend/internal/otel"
     "garp-backend/internal/state"
     "garp-backend/internal/storage"
+    "garp-backend/internal/tenant"
+    "garp-backend/internal/txmonitor"
+    "garp-backend/internal/txsubmit"
+    "garp-backend/schema"
 )
 
+// @title GARP Backend API
+// @version 1.0
+// @description REST API of the GARP participant backend.
+// @BasePath /
 func main() {
-    // Load configuration (defaults + env overrides)
+    slog.SetDefault(logger.New(logger.ParseLevel(os.Getenv("LOG_LEVEL")), os.Getenv("LOG_FORMAT")))
+
+    // Load configuration (defaults + Vault or Secret Manager secrets + CONFIG_FILE + env overrides)
     cfg := config.Default()
-    config.ApplyEnv(&cfg)
+    switch {
+    case os.Getenv("VAULT_ADDR") != "":
+        vcfg, err := config.LoadVault(config.VaultConfigFromEnv())
+        if err != nil {
+            fatal("Failed to load secrets from Vault", err)
+        }
+        cfg = *vcfg
+    case config.SecretManagerEnabled():
+        scfg, err := loadSecretManagerConfig()
+        if err != nil {
+            fatal("Failed to load secrets from GCP Secret Manager", err)
+        }
+        cfg = *scfg
+    }
+    if err := config.ApplyEnv(&cfg); err != nil {
+        fatal("Failed to load configuration", err)
+    }
+    if errs := config.Validate(cfg); len(errs) > 0 {
+        fatal("Invalid configuration", errors.Join(errs...))
+    }
 
     // Initialize tracing
-    shutdown := otel.Init(cfg.OTEL.Endpoint, cfg.OTEL.ServiceName)
-    defer shutdown(context.Background())
+    // Held atomically because a config reload swaps it while the deferred call may run
+    var shutdown atomic.Pointer[func(context.Context) error]
+    initTracing := otel.Init(cfg.OTEL.Endpoint, cfg.OTEL.ServiceName)
+    shutdown.Store(&initTracing)
+    defer func() { (*shutdown.Load())(context.Background()) }()
 
     // Connect to storage services (Postgres + Redis)
-    store, err := storage.Init(context.Background(), storage.Config{PostgresURL: cfg.Database.PostgresURL, RedisURL: cfg.Database.RedisURL})
+    lifetime, _ := time.ParseDuration(cfg.Database.PGMaxConnLifetime)
+    idle, _ := time.ParseDuration(cfg.Database.PGMaxConnIdleTime)
+    store, err := storage.Init(context.Background(), storage.Config{
+        PostgresURL: cfg.Database.PostgresURL,
+        RedisURL:    cfg.Database.RedisURL,
+        Pool: storage.PoolConfig{
+            MaxConns:        int32(cfg.Database.PGMaxConns),
+            MinConns:        int32(cfg.Database.PGMinConns),
+            MaxConnLifetime: lifetime,
+            MaxConnIdleTime: idle,
+        },
+        RedisPoolSize: cfg.Database.RedisPoolSize,
+        PushgatewayURL: cfg.Metrics.PushgatewayURL,
+    })
     if err != nil {
-        log.Fatalf("Failed to initialize storage: %v", err)
+        fatal("Failed to initialize storage", err)
     }
     defer store.Close()
 
     // Run migrations
     if err := store.RunMigrations(context.Background()); err != nil {
-        log.Fatalf("Failed to run migrations: %v", err)
+        fatal("Failed to run migrations", err)
+    }
+
+    // Encrypt chat message content at rest with per-tenant keys (optional)
+    var encryptedStore *storage.EncryptedStorage
+    if kek, closeKEK, err := messageKEK(cfg); err != nil {
+        fatal("Failed to load message encryption key", err)
+    } else if kek != nil {
+        defer closeKEK()
+        encryptedStore = storage.NewEncryptedStorage(store, kek)
+    }
+    // POST /messages writes through a buffer that coalesces concurrent messages into batches
+    messageBuffer := storage.NewMessageBuffer(store, 0, 0)
+    if encryptedStore != nil {
+        messageBuffer = encryptedStore.NewMessageBuffer(0, 0)
+    }
+    defer messageBuffer.Close()
+
+    // SQL access to indexed chain data (contract events)
+    chainDB, err := integration.NewDBIntegration(integration.Config{Driver: "postgres", DSN: cfg.Database.PostgresURL, MaxConns: 5})
+    if err != nil {
+        fatal("Failed to connect chain database", err)
+    }
+    defer chainDB.Close()
+    // The blockchain_* tables behind /blocks, the archive and the exports are not in the
+    // migrations; InitializeSchema creates them (and is a no-op for existing tables)
+    if err := chainDB.InitializeSchema(context.Background()); err != nil {
+        fatal("Failed to initialize chain database schema", err)
+    }
+    if cfg.Search.ElasticsearchURL != "" {
+        es, err := integration.NewElasticsearchIntegration(integration.ElasticsearchConfig{URL: cfg.Search.ElasticsearchURL})
+        if err != nil {
+            fatal("Failed to connect to Elasticsearch", err)
+        }
+        chainDB.EnableSearchIndexing(es, 1024)
     }
 
 	// Initialize clients
     participantClient := client.New(cfg.Participant.BaseURL)
     if err := participantClient.WithTLS(cfg.TLS.ClientCert, cfg.TLS.ClientKey, cfg.TLS.CACert); err != nil {
-        log.Fatalf("Failed to configure mTLS: %v", err)
+        fatal("Failed to configure mTLS", err)
+    }
+    syncClient := client.NewSynchronizer(cfg.Synchronizer.BaseURL)
+
+    // Enterprise systems authenticate with client certificates signed by this CA (optional)
+    var enterpriseCAs *x509.CertPool
+    if cfg.Security.EnterpriseClientCA != "" {
+        pem, err := os.ReadFile(cfg.Security.EnterpriseClientCA)
+        if err != nil {
+            fatal("Failed to read enterprise client CA", err)
+        }
+        enterpriseCAs = x509.NewCertPool()
+        if !enterpriseCAs.AppendCertsFromPEM(pem) {
+            fatal("Failed to parse enterprise client CA", errors.New("no certificates found"))
+        }
+    }
+
+    // Upstream services reported by /ready alongside Postgres and Redis
+    store.RegisterHealthCheck("participant", participantClient.Health)
+    store.RegisterHealthCheck("synchronizer", func(ctx context.Context) error {
+        var status json.RawMessage
+        return syncClient.Status(ctx, &status)
+    })
+
+    // Background work started below stops when appCtx is cancelled at shutdown
+    appCtx, stopApp := context.WithCancel(context.Background())
+    defer stopApp()
+
+    // Push metrics periodically as well as serving /metrics (optional)
+    if cfg.Metrics.PushgatewayURL != "" {
+        interval, _ := time.ParseDuration(cfg.Metrics.PushInterval) // validated above
+        go pushMetricsPeriodically(appCtx, cfg.Metrics.PushgatewayURL, cfg.OTEL.ServiceName, interval)
+    }
+
+    // Anchor chat messages to the blocks that follow them
+    go func() {
+        if err := (&anchor.AnchorWorker{}).Start(appCtx, store, syncClient, 5*time.Second); err != nil {
+            slog.Error("anchor worker stopped", "error", err)
+        }
+    }()
+
+    // Close governance proposals whose voting period has ended
+    go func() {
+        if err := (&governance.Finalizer{Participant: participantClient}).Start(appCtx, store, syncClient, 5*time.Second); err != nil {
+            slog.Error("governance finalizer stopped", "error", err)
+        }
+    }()
+
+    // Internal eventing: announce finalized blocks over NATS (optional)
+    if cfg.NATS.URL != "" {
+        bus, err := integration.NewNATSIntegration(cfg.NATS.URL)
+        if err != nil {
+            fatal("Failed to connect to NATS", err)
+        }
+        defer bus.Close()
+        go publishFinalizedBlocks(appCtx, syncClient, bus, 5*time.Second)
     }
 
 	// Initialize state manager
     // Initialize in-memory state store
     stateManager := state.NewStore()
-    _ = stateManager
+    if path := cfg.State.SnapshotPath; path != "" {
+        if err := restoreStateSnapshot(context.Background(), stateManager, path); err != nil {
+            slog.Warn("failed to restore state snapshot", "path", path, "error", err)
+        }
+        go snapshotStatePeriodically(appCtx, stateManager, path, 30*time.Second)
+    }
+
+    // Accepted transactions wait in the pool and are moved into the store in CreatedAt order
+    txPool := state.NewTxPool()
+    go drainTxPool(appCtx, txPool, stateManager, txPoolBatchSize, 500*time.Millisecond)
+    // REST and gRPC submit transactions the same way: validated, saved for the monitor below, and pooled
+    submitter := txsubmit.New(participantClient, store, txPool)
+
+    // Follow queued transactions on the participant; status changes reach the
+    // transaction's event stream and the OnTxStatus hooks registered below
+    go func() {
+        if err := (&txmonitor.Monitor{Participant: participantClient}).Start(appCtx, store, 5*time.Second); err != nil {
+            slog.Error("transaction status monitor stopped", "error", err)
+        }
+    }()
+
+    // Enterprise systems (optional)
+    enterpriseClient := integration.NewEnterpriseIntegration(integration.EnterpriseConfig{HTTPTimeout: 10 * time.Second})
+    var crm *integration.CRMSystem
+    if cfg.CRM.BaseURL != "" {
+        crm = enterpriseClient.NewCRMSystem(cfg.CRM.BaseURL, cfg.CRM.APIKey)
+    }
+
+    // Email failed-transaction alerts (optional)
+    if cfg.SMTP.Host != "" && len(cfg.SMTP.NotifyTo) > 0 {
+        notifier := integration.NewEmailNotifier(integration.EmailConfig{
+            Host: cfg.SMTP.Host, Port: cfg.SMTP.Port, Username: cfg.SMTP.User, Password: cfg.SMTP.Pass, From: cfg.SMTP.From,
+        })
+        store.OnTxStatus(func(ctx context.Context, hash, status string) {
+            if status != "failed" { return }
+            // Hooks run inline with the status update, so send in the background
+            go func() {
+                ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+                defer cancel()
+                if err := notifier.NotifyTransactionFailed(ctx, hash, "", cfg.SMTP.NotifyTo); err != nil {
+                    logger.FromContext(ctx).Warn("failed to send transaction failure email", "hash", hash, "error", err)
+                }
+            }()
+        })
+    }
+
+    // Send transaction status events to a webhook (optional); failed deliveries are
+    // retried with backoff from a Redis queue by the delivery worker
+    if cfg.Cloud.WebhookURL != "" {
+        webhooks := integration.NewWebhookSender(10 * time.Second).WithDelivery(integration.WebhookDeliveryConfig{Redis: store.Redis})
+        webhooks.WebhookSecret = cfg.Cloud.WebhookSecret
+        go func() {
+            if err := webhooks.RunDeliveryWorker(appCtx); err != nil {
+                slog.Error("webhook delivery worker stopped", "error", err)
+            }
+        }()
+        store.OnTxStatus(func(ctx context.Context, hash, status string) {
+            go func() {
+                ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 15*time.Second)
+                defer cancel()
+                data := map[string]string{"hash": hash, "status": status}
+                err := webhooks.SendBlockchainEvent(ctx, cfg.Cloud.WebhookURL, "transaction."+status, data)
+                if errors.Is(err, integration.ErrRetryQueued) {
+                    logger.FromContext(ctx).Warn("transaction webhook failed, queued for retry", "hash", hash, "error", err)
+                } else if err != nil {
+                    logger.FromContext(ctx).Error("transaction webhook failed and could not be queued for retry", "hash", hash, "error", err)
+                }
+            }()
+        })
+    }
+
+    // Alert Slack when a dependency health check starts failing (optional)
+    if cfg.Slack.WebhookURL != "" {
+        go alertFailingHealthChecks(appCtx, store, enterpriseClient.NewSlackNotifier(cfg.Slack.WebhookURL), 30*time.Second)
+    }
+
+    // Mirror transaction status changes into the ERP (optional)
+    if cfg.ERP.BaseURL != "" {
+        erp := enterpriseClient.NewERPSystem(cfg.ERP.BaseURL, cfg.ERP.APIKey)
+        store.OnTxStatus(func(ctx context.Context, hash, status string) {
+            var blockNumber uint64
+            if tx, ok := stateManager.GetTx(hash); ok && tx.BlockNumber != nil {
+                blockNumber = *tx.BlockNumber
+            }
+            // Hooks run inline with the status update, so a slow ERP must not hold it up
+            go func() {
+                ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 15*time.Second)
+                defer cancel()
+                if err := erp.UpdateERPTransaction(ctx, hash, status, blockNumber, ""); err != nil {
+                    logger.FromContext(ctx).Warn("failed to update ERP transaction", "hash", hash, "status", status, "error", err)
+                }
+            }()
+        })
+    }
+
+    // Initialize cloud storage for direct client uploads (optional)
+    var s3Storage *integration.S3Storage
+    if cfg.Cloud.AWSRegion != "" {
+        cloud, err := integration.NewCloudIntegration(integration.CloudConfig{AWSRegion: cfg.Cloud.AWSRegion, HTTPTimeout: 15 * time.Second})
+        if err != nil {
+            fatal("Failed to initialize cloud integration", err)
+        }
+        if s3Storage, err = cloud.NewS3Storage(); err != nil {
+            fatal("Failed to initialize S3 storage", err)
+        }
+    }
+
+    // Stream transactions from each new block into BigQuery for analytics (optional)
+    if cfg.Cloud.BigQueryDataset != "" {
+        cloud, err := integration.NewCloudIntegration(integration.CloudConfig{GCPProjectID: cfg.Cloud.GCPProjectID, HTTPTimeout: 15 * time.Second})
+        if err != nil {
+            fatal("Failed to initialize cloud integration", err)
+        }
+        bq, err := cloud.NewBigQueryStreamer(appCtx)
+        if err != nil {
+            fatal("Failed to initialize BigQuery streaming", err)
+        }
+        defer bq.Close()
+        if err := bq.EnsureTable(appCtx, cfg.Cloud.BigQueryDataset, cfg.Cloud.BigQueryTable); err != nil {
+            fatal("Failed to prepare BigQuery table", err)
+        }
+        go streamBlocksToBigQuery(appCtx, syncClient, bq, cfg.Cloud.BigQueryDataset, cfg.Cloud.BigQueryTable, 5*time.Second)
+    }
 
 	// Create Gin engine
     gin.SetMode(gin.ReleaseMode)
     r := gin.New()
+    // ClientIP (rate limits, IP filters, audit) only honours X-Forwarded-For from these proxies
+    if err := r.SetTrustedProxies(cfg.Security.TrustedProxies); err != nil {
+        fatal("Invalid trusted proxies", err)
+    }
     r.Use(gin.Logger())
-    r.Use(gin.Recovery())
+    r.Use(middleware.SentryRecovery(cfg.Sentry.DSN))
+    defer middleware.FlushSentry(2 * time.Second)
     r.Use(middleware.SecurityHeaders())
     r.Use(otel.Middleware(cfg.OTEL.ServiceName))
-    r.Use(middleware.RateLimitRedis(120, store.Redis))
+    r.Use(otel.TraceLogMiddleware(slog.Default()))
+    r.Use(middleware.RequestID())
+    if cfg.Server.TenantHeader != "" {
+        // Messages, transactions and their Redis channels are isolated per tenant named in this header
+        r.Use(middleware.TenantExtractor(cfg.Server.TenantHeader))
+    }
+    requestTimeout, _ := time.ParseDuration(cfg.Server.RequestTimeout)
+    r.Use(middleware.RequestTimeout(requestTimeout, streamingRoutes))
+    // Fault injection for test environments; inert unless CHAOS_ENABLED=true, then driven by `chaos:config` in Redis
+    r.Use(middleware.ChaosMiddlewareDynamic(store.Redis, middleware.ChaosConfigKey, middleware.ChaosConfig{}))
+    r.Use(middleware.GzipResponse(gzip.DefaultCompression))
+    r.Use(middleware.DynamicBodyLimit(cfg.Server.RouteBodyLimitConfig, cfg.Server.MaxBodyBytes))
+    var rateLimitRPM atomic.Int64
+    rateLimitRPM.Store(int64(cfg.RateLimit.RPM))
+    // `redis-cli SET rate_limit_rpm <n>` overrides the configured limit without a restart
+    baseRPM := middleware.DynamicRPM(store.Redis, middleware.RateLimitConfigKey, func() int { return int(rateLimitRPM.Load()) })
+    if cfg.RateLimit.Adaptive {
+        // Clients under 1% 4xx responses get twice the limit, those over 10% half of it
+        r.Use(middleware.NewAdaptiveRateLimiter(store.Redis, baseRPM).Middleware())
+    } else {
+        r.Use(middleware.RateLimitRedisFunc(baseRPM, store.Redis))
+    }
+    r.Use(middleware.AuditLog(storage.NewPostgresAuditStore(store.PG)))
+    recordings := storage.NewRedisRecordingStore(store.Redis)
+    r.Use(middleware.RequestRecorder(recordings, cfg.Server.RecordRequestBodies))
+
+    // API v2 lives on a separate node version until v1 is sunset. v1 requests that
+    // send Accept: application/vnd.garp.v2+json are routed there too.
+    if cfg.Participant.V2BaseURL != "" {
+        v2Target, _ := url.Parse(cfg.Participant.V2BaseURL) // checked by config.Validate
+        v2Proxy := &httputil.ReverseProxy{Rewrite: func(pr *httputil.ProxyRequest) {
+            pr.SetURL(v2Target)
+            pr.SetXForwarded()
+        }}
+        r.Any("/api/v2/*path", func(c *gin.Context) { v2Proxy.ServeHTTP(c.Writer, c.Request) })
+        r.Use(middleware.APIVersionNegotiation(v2Proxy))
+    }
+
+    // Hot-reload the config file: rate limit, OTEL endpoint and participant URL apply live.
+    // Server settings, database URLs, TLS files, IP lists and the OTEL service name need a restart.
+    if path := os.Getenv("CONFIG_FILE"); path != "" {
+        var reloadMu sync.Mutex
+        live := cfg
+        stopWatch, err := config.Watch(path, func(next config.Config) {
+            reloadMu.Lock()
+            defer reloadMu.Unlock()
+            if !reflect.DeepEqual(next.Server, live.Server) || next.Database != live.Database || next.TLS != live.TLS || next.OTEL.ServiceName != live.OTEL.ServiceName ||
+                !slices.Equal(next.Security.IPAllowList, live.Security.IPAllowList) || !slices.Equal(next.Security.IPBlockList, live.Security.IPBlockList) {
+                slog.Info("config reload: server, database, TLS, security and otel.service_name changes are ignored until restart")
+            }
+            if next.RateLimit.RPM != live.RateLimit.RPM {
+                slog.Info("config reload: rate limit changed", "from_rpm", live.RateLimit.RPM, "to_rpm", next.RateLimit.RPM)
+                rateLimitRPM.Store(int64(next.RateLimit.RPM))
+            }
+            if next.Participant.BaseURL != live.Participant.BaseURL {
+                slog.Info("config reload: participant URL changed", "url", next.Participant.BaseURL)
+                participantClient.SetBaseURL(next.Participant.BaseURL)
+            }
+            if next.OTEL.Endpoint != live.OTEL.Endpoint {
+                slog.Info("config reload: OTEL endpoint changed", "endpoint", next.OTEL.Endpoint)
+                reinit := otel.Init(next.OTEL.Endpoint, live.OTEL.ServiceName)
+                _ = (*shutdown.Swap(&reinit))(context.Background())
+            }
+            live.RateLimit = next.RateLimit
+            live.Participant = next.Participant
+            live.OTEL.Endpoint = next.OTEL.Endpoint
+        }, 10*time.Second)
+        if err != nil {
+            fatal("Failed to watch config file", err)
+        }
+        defer stopWatch()
+    }
 
 	// Health check endpoints
+	// @Summary Liveness probe
+	// @Tags health
+	// @Produce json
+	// @Success 200 {object} map[string]string
+	// @Router /health [get]
 	r.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "ok"})
 	})
+	// @Summary Readiness probe (Postgres, Redis, participant node and synchronizer)
+	// @Tags health
+	// @Produce json
+	// @Success 200 {object} api.ReadinessReport
+	// @Failure 503 {object} api.ReadinessReport
+	// @Router /ready [get]
 	r.GET("/ready", func(c *gin.Context) {
-        // Check storage connectivity
-        if !store.Ready(c.Request.Context()) {
-            c.JSON(http.StatusServiceUnavailable, gin.H{"status": "database unavailable"})
+        report := store.HealthReport(c.Request.Context())
+        if !storage.Healthy(report) {
+            c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable", "checks": report})
             return
         }
-        c.JSON(http.StatusOK, gin.H{"status": "ready"})
+        c.JSON(http.StatusOK, gin.H{"status": "ready", "checks": report})
     })
 
+	// OpenAPI contract (regenerate with `make generate-docs`)
+	// @Summary This OpenAPI document
+	// @Tags docs
+	// @Produce json
+	// @Success 200 {object} map[string]interface{}
+	// @Router /docs/openapi.json [get]
+	r.GET("/docs/openapi.json", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json", docs.OpenAPI)
+	})
+
 	// API routes
+	// Retried POSTs with an identical body within five minutes replay the first response
+	dedup := middleware.DeduplicateMiddleware(store.Redis, middleware.BodyHashHeader)
 	api := r.Group("/api/v1")
 	{
 		// Transaction endpoints
-		api.POST("/transactions", func(c *gin.Context) {
-			// Implementation for submitting transactions
+		// @Summary Submit a transaction
+		// @Tags transactions
+		// @Accept json
+		// @Produce json
+		// @Success 200 {object} api.TransactionInfo
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 422 {object} api.ValidationErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/transactions [post]
+		api.POST("/transactions", dedup, middleware.ValidateBody(schema.Transaction), func(c *gin.Context) {
+			raw, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+				return
+			}
+			tx, err := submitter.Submit(c.Request.Context(), raw)
+			if err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "transaction submission failed"})
+				return
+			}
+			c.JSON(http.StatusOK, tx)
+		})
+
+		// @Summary Submit a batch of transactions
+		// @Description Each item is validated against the POST /api/v1/transactions schema and submitted independently; one failure does not abort the others.
+		// @Tags transactions
+		// @Accept json
+		// @Produce json
+		// @Param body body api.BatchSubmitRequest true "Transactions to submit"
+		// @Success 200 {object} api.BatchSubmitResponse
+		// @Failure 400 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/batch [post]
+		api.POST("/transactions/batch", func(c *gin.Context) {
+			var req apimodel.BatchSubmitRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if len(req.Transactions) == 0 || len(req.Transactions) > maxBatchSize {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("transactions must contain 1-%d items", maxBatchSize)})
+				return
+			}
+
+			ctx := c.Request.Context()
+			results := make([]apimodel.BatchSubmitResult, len(req.Transactions))
+			g, gctx := errgroup.WithContext(ctx)
+			g.SetLimit(cfg.Participant.BatchConcurrency)
+			for i, raw := range req.Transactions {
+				// Validate up front, as POST /transactions does, so invalid items never reach the participant
+				var ve *txsubmit.ValidationError
+				if err := submitter.Validate(raw); errors.As(err, &ve) {
+					results[i].Error = "transaction failed validation"
+					for _, f := range ve.Failures {
+						results[i].Details = append(results[i].Details, apimodel.ValidationDetail{Keyword: f.Keyword, Path: f.Path, Message: f.Message})
+					}
+					continue
+				} else if err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				g.Go(func() error {
+					tx, err := submitter.Submit(gctx, raw)
+					if err != nil {
+						results[i].Error = err.Error()
+						return nil // per-item failure; keep submitting the rest
+					}
+					results[i].ID = tx.ID
+					return nil
+				})
+			}
+			_ = g.Wait()
+			c.JSON(http.StatusOK, apimodel.BatchSubmitResponse{Results: results})
 		})
 		
+		// @Summary Estimate the fee of a transaction
+		// @Description Accepts the same body as POST /api/v1/transactions. Identical bodies are answered from a 30s cache.
+		// @Tags transactions
+		// @Accept json
+		// @Produce json
+		// @Success 200 {object} api.FeeEstimate
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/estimate [post]
+		api.POST("/transactions/estimate", func(c *gin.Context) {
+			raw, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+				return
+			}
+			// Compact so formatting differences still hit the same cache entry
+			var body bytes.Buffer
+			if err := json.Compact(&body, raw); err != nil || body.Len() == 0 || body.Bytes()[0] != '{' {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "body must be a JSON transaction object"})
+				return
+			}
+			ctx := c.Request.Context()
+			sum := sha256.Sum256(body.Bytes())
+			cacheKey := "fee_estimate:" + hex.EncodeToString(sum[:])
+
+			var est apimodel.FeeEstimate
+			if cached, err := store.Redis.Get(ctx, cacheKey).Bytes(); err == nil && json.Unmarshal(cached, &est) == nil {
+				c.JSON(http.StatusOK, est)
+				return
+			}
+			var sim struct {
+				Fee      int64 `json:"fee"`
+				GasLimit int64 `json:"gas_limit"`
+			}
+			if err := participantClient.SimulateTransaction(ctx, json.RawMessage(body.Bytes()), &sim); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "fee estimation failed"})
+				return
+			}
+			est = apimodel.FeeEstimate{Fee: sim.Fee, GasLimit: sim.GasLimit, Currency: "GARP"}
+			if b, err := json.Marshal(est); err == nil {
+				if err := store.Redis.Set(ctx, cacheKey, b, 30*time.Second).Err(); err != nil {
+					logger.FromContext(ctx).Warn("fee estimate: failed to cache", "error", err)
+				}
+			}
+			c.JSON(http.StatusOK, est)
+		})
+
+		// @Summary Create a multi-signature transaction
+		// @Description Signers are hex ed25519 public keys. Each signs the returned digest; the transaction executes once threshold signatures are collected.
+		// @Tags transactions
+		// @Accept json
+		// @Produce json
+		// @Param body body api.MultiSigCreateRequest true "Commands, threshold and signers"
+		// @Success 201 {object} api.MultiSigTransaction
+		// @Failure 400 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/multisig [post]
+		api.POST("/transactions/multisig", func(c *gin.Context) {
+			var req apimodel.MultiSigCreateRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			m, err := storage.NewMultiSigTx(req.Commands, req.Threshold, req.Signers)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if m, err = store.CreateMultiSig(c.Request.Context(), m); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store multisig transaction"})
+				return
+			}
+			c.JSON(http.StatusCreated, m)
+		})
+
+		// @Summary Add a signature to a multi-signature transaction
+		// @Tags transactions
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "Multisig transaction ID"
+		// @Param body body api.MultiSigSignRequest true "Signer public key and signature over the digest"
+		// @Success 200 {object} api.MultiSigTransaction
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 404 {object} api.ErrorResponse
+		// @Failure 409 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/multisig/{id}/sign [post]
+		api.POST("/transactions/multisig/:id/sign", func(c *gin.Context) {
+			var req apimodel.MultiSigSignRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx := c.Request.Context()
+			m, err := store.GetMultiSig(ctx, c.Param("id"))
+			if errors.Is(err, storage.ErrMultiSigNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load multisig transaction"})
+				return
+			}
+			if m.Status != storage.MultiSigPending {
+				c.JSON(http.StatusConflict, gin.H{"error": "multisig transaction is " + m.Status})
+				return
+			}
+			if err := m.Verify(req.Signer, req.Signature); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if err := store.AddMultiSigSignature(ctx, m.ID, req.Signer, req.Signature); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store signature"})
+				return
+			}
+			if m, err = store.GetMultiSig(ctx, m.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load multisig transaction"})
+				return
+			}
+
+			// Threshold reached: submit once, whichever signature request gets the claim
+			if m.Ready() {
+				claimed, err := store.ClaimMultiSigExecution(ctx, m.ID)
+				if err != nil {
+					c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to claim multisig execution"})
+					return
+				}
+				if claimed {
+					body, _ := json.Marshal(gin.H{
+						"commands": m.Commands,
+						"multisig": gin.H{"id": m.ID, "threshold": m.Threshold, "signers": m.Signers, "signatures": m.Signatures, "digest": m.Digest},
+					})
+					var out struct {
+						ID string `json:"id"`
+					}
+					if err := participantClient.SubmitTransaction(ctx, json.RawMessage(body), &out); err != nil || out.ID == "" {
+						logger.FromContext(ctx).Error("multisig: execution failed", "id", m.ID, "error", err)
+						out.ID = ""
+					} else {
+						if err := store.SaveTx(ctx, out.ID, body); err != nil {
+							logger.FromContext(ctx).Warn("multisig: failed to store transaction", "id", out.ID, "error", err)
+						}
+						if err := txPool.Add(state.Transaction{TxHash: out.ID, Status: "pending", CreatedAt: time.Now().UTC()}); err != nil {
+							logger.FromContext(ctx).Warn("multisig: failed to pool transaction", "id", out.ID, "error", err)
+						}
+					}
+					if err := store.FinishMultiSig(ctx, m.ID, out.ID); err != nil {
+						logger.FromContext(ctx).Error("multisig: failed to record outcome", "id", m.ID, "error", err)
+					}
+					if m, err = store.GetMultiSig(ctx, m.ID); err != nil {
+						c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load multisig transaction"})
+						return
+					}
+				}
+			}
+			c.JSON(http.StatusOK, m)
+		})
+
+		// @Summary Get transaction details
+		// @Tags transactions
+		// @Produce json
+		// @Param id path string true "Transaction ID"
+		// @Success 200 {object} api.TransactionInfo
+		// @Failure 404 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/{id} [get]
 		api.GET("/transactions/:id", func(c *gin.Context) {
-			// Implementation for getting transaction details
+			id := c.Param("id")
+			tx, ok := txPool.Get(id)
+			if !ok {
+				tx, ok = stateManager.GetTx(id)
+			}
+			if !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+				return
+			}
+			createdAt := tx.CreatedAt.UnixMilli()
+			c.JSON(http.StatusOK, apimodel.TransactionInfo{ID: tx.TxHash, Status: &tx.Status, CreatedAt: &createdAt})
 		})
 		
+		// @Summary Get transaction status
+		// @Tags transactions
+		// @Produce json
+		// @Param id path string true "Transaction ID"
+		// @Success 200 {object} api.TransactionInfo
+		// @Failure 404 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/{id}/status [get]
 		api.GET("/transactions/:id/status", func(c *gin.Context) {
 			// Implementation for getting transaction status
 		})
 
+		// Server-Sent Events stream of status updates published by storage on tx:<id>
+		// @Summary Stream transaction status updates (Server-Sent Events)
+		// @Tags transactions
+		// @Produce text/event-stream
+		// @Param id path string true "Transaction ID"
+		// @Success 200 {string} string "data: <TxStatusEvent JSON> frames"
+		// @Failure 503 {object} api.ErrorResponse
+		// @Router /api/v1/transactions/{id}/events [get]
+		api.GET("/transactions/:id/events", streamTxStatus(store.Redis))
+
 		// Account endpoints
+		// @Summary Get account details
+		// @Tags accounts
+		// @Produce json
+		// @Param address path string true "Account address"
+		// @Success 200 {object} api.Account
+		// @Failure 404 {object} api.ErrorResponse
+		// @Router /api/v1/accounts/{address} [get]
 		api.GET("/accounts/:address", func(c *gin.Context) {
 			// Implementation for getting account details
 		})
 		
+		// @Summary Get account balance
+		// @Tags accounts
+		// @Produce json
+		// @Param address path string true "Account address"
+		// @Success 200 {object} api.Account
+		// @Failure 404 {object} api.ErrorResponse
+		// @Router /api/v1/accounts/{address}/balance [get]
 		api.GET("/accounts/:address/balance", func(c *gin.Context) {
 			// Implementation for getting account balance
 		})
 
+		// @Summary List an account's contracts
+		// @Description Indexed contracts owned by the account, plus any active on-chain contracts not yet indexed.
+		// @Tags accounts
+		// @Produce json
+		// @Param address path string true "Account address"
+		// @Param status query string false "Filter by status, e.g. active or archived"
+		// @Param limit query int false "Max indexed contracts (default 50, max 500)"
+		// @Param offset query int false "Indexed contracts to skip"
+		// @Success 200 {object} object{contracts=[]integration.ContractRecord}
+		// @Failure 400 {object} api.ErrorResponse
+		// @Router /api/v1/accounts/{address}/contracts [get]
+		api.GET("/accounts/:address/contracts", func(c *gin.Context) {
+			ctx := c.Request.Context()
+			address, status := c.Param("address"), c.Query("status")
+			limit, offset := 50, 0
+			var err error
+			if v := c.Query("limit"); v != "" {
+				if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > 500 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+					return
+				}
+			}
+			if v := c.Query("offset"); v != "" {
+				if offset, err = strconv.Atoi(v); err != nil || offset < 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid offset"})
+					return
+				}
+			}
+			records, err := chainDB.ListContractsByOwner(ctx, address, status, limit, offset)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to list contracts", "owner", address, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list contracts"})
+				return
+			}
+			if records == nil {
+				records = []integration.ContractRecord{}
+			}
+			// Merge in on-chain contracts the indexer has not caught up with yet (first page only,
+			// since they have no stable position in the indexed ordering)
+			if offset == 0 {
+				onChain, err := participantContracts(ctx, participantClient, address)
+				if err != nil {
+					logger.FromContext(ctx).Warn("participant contract lookup failed, returning indexed contracts only", "owner", address, "error", err)
+				}
+				seen := make(map[string]bool, len(records))
+				for _, r := range records {
+					seen[r.ContractID] = true
+				}
+				for _, r := range onChain {
+					if seen[r.ContractID] || r.Owner != address || (status != "" && r.Status != status) {
+						continue
+					}
+					seen[r.ContractID] = true
+					records = append(records, r)
+				}
+			}
+			c.JSON(http.StatusOK, gin.H{"contracts": records})
+		})
+
 		// Contract endpoints
-		api.POST("/contracts", func(c *gin.Context) {
+		// @Summary Deploy a contract
+		// @Tags contracts
+		// @Accept json
+		// @Produce json
+		// @Success 200 {object} map[string]interface{}
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 422 {object} api.ValidationErrorResponse
+		// @Router /api/v1/contracts [post]
+		api.POST("/contracts", dedup, middleware.ValidateBody(schema.Contract), func(c *gin.Context) {
 			// Implementation for deploying contracts
 		})
 		
+		// @Summary Create an NFT
+		// @Description Creates a contract from the NFT template and stores its metadata. supply is 1 for a unique NFT (the default) or more for a semi-fungible asset.
+		// @Tags nfts
+		// @Accept json
+		// @Produce json
+		// @Param body body api.NFTCreateRequest true "Owner, asset and metadata URI"
+		// @Success 201 {object} storage.NFTMetadata
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/nfts [post]
+		api.POST("/nfts", dedup, func(c *gin.Context) {
+			var req apimodel.NFTCreateRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if req.Supply == 0 {
+				req.Supply = 1
+			}
+			if req.Supply < 1 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "supply must be a positive integer"})
+				return
+			}
+			if !validMetadataURI(req.MetadataURI) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "metadata_uri must be an absolute http(s), ipfs or ar URL"})
+				return
+			}
+			ctx := c.Request.Context()
+			var created struct {
+				ContractID string `json:"contract_id"`
+			}
+			err := participantClient.CreateContract(ctx, gin.H{
+				"template_id": nftTemplateID,
+				"owner":       req.Owner,
+				"arguments": gin.H{
+					"asset_id":     req.AssetID,
+					"metadata_uri": req.MetadataURI,
+					"supply":       req.Supply,
+				},
+			}, &created)
+			if err != nil || created.ContractID == "" {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "nft contract creation failed"})
+				return
+			}
+			meta, err := store.SaveNFTMetadata(ctx, storage.NFTMetadata{ContractID: created.ContractID, AssetID: req.AssetID, Owner: req.Owner, MetadataURI: req.MetadataURI, Supply: req.Supply})
+			if err != nil {
+				// The contract exists on-chain regardless; report it so the caller can retry the metadata
+				logger.FromContext(ctx).Error("failed to store nft metadata", "contract_id", created.ContractID, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "contract created but metadata was not stored", "contract_id": created.ContractID})
+				return
+			}
+			c.JSON(http.StatusCreated, meta)
+		})
+
+		// @Summary Get an NFT
+		// @Description Stored metadata merged with the on-chain contract under "contract".
+		// @Tags nfts
+		// @Produce json
+		// @Param contract_id path string true "Contract ID"
+		// @Success 200 {object} storage.NFTMetadata{contract=object}
+		// @Failure 404 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/nfts/{contract_id} [get]
+		api.GET("/nfts/:contract_id", func(c *gin.Context) {
+			ctx := c.Request.Context()
+			meta, err := store.GetNFTMetadata(ctx, c.Param("contract_id"))
+			if errors.Is(err, storage.ErrNFTNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load nft"})
+				return
+			}
+			var contract json.RawMessage
+			if err := participantClient.GetContract(ctx, meta.ContractID, &contract); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch nft contract"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{
+				"contract_id":  meta.ContractID,
+				"asset_id":     meta.AssetID,
+				"owner":        meta.Owner,
+				"metadata_uri": meta.MetadataURI,
+				"supply":       meta.Supply,
+				"created_at":   meta.CreatedAt,
+				"contract":     contract,
+			})
+		})
+
+		// @Summary Exercise a contract choice
+		// @Tags contracts
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "Contract ID"
+		// @Success 200 {object} map[string]interface{}
+		// @Failure 400 {object} api.ErrorResponse
+		// @Router /api/v1/contracts/{id}/exercise [post]
 		api.POST("/contracts/:id/exercise", func(c *gin.Context) {
 			// Implementation for exercising contracts
 		})
 
+		// @Summary List contract events
+		// @Tags contracts
+		// @Produce json
+		// @Param id path string true "Contract ID"
+		// @Param fromBlock query int false "First block (inclusive)"
+		// @Param toBlock query int false "Last block (inclusive)"
+		// @Param topic query string false "Hex event topic"
+		// @Param limit query int false "Max events (default 100, max 1000)"
+		// @Success 200 {object} object{events=[]integration.ContractEvent}
+		// @Failure 400 {object} api.ErrorResponse
+		// @Router /api/v1/contracts/{id}/events [get]
+		api.GET("/contracts/:id/events", func(c *gin.Context) {
+			var fromBlock, toBlock uint64
+			var err error
+			if v := c.Query("fromBlock"); v != "" {
+				if fromBlock, err = strconv.ParseUint(v, 10, 64); err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid fromBlock"})
+					return
+				}
+			}
+			if v := c.Query("toBlock"); v != "" {
+				if toBlock, err = strconv.ParseUint(v, 10, 64); err != nil || toBlock < fromBlock {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid toBlock"})
+					return
+				}
+			}
+			topic := strings.ToLower(strings.TrimPrefix(c.Query("topic"), "0x"))
+			if _, err := hex.DecodeString(topic); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "topic must be hex"})
+				return
+			}
+			limit := 100
+			if v := c.Query("limit"); v != "" {
+				if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > 1000 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+					return
+				}
+			}
+			events, err := chainDB.GetContractEvents(c.Request.Context(), c.Param("id"), topic, fromBlock, toBlock, limit)
+			if err != nil {
+				logger.FromContext(c.Request.Context()).Error("failed to query contract events", "contract_id", c.Param("id"), "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to query contract events"})
+				return
+			}
+			if events == nil {
+				events = []integration.ContractEvent{}
+			}
+			c.JSON(http.StatusOK, gin.H{"events": events})
+		})
+
+		// @Summary List blocks
+		// @Description Pages through indexed blocks. Pass the X-Next-Cursor response header back as from to get the next page.
+		// @Tags blocks
+		// @Produce json
+		// @Param from query int false "Exclusive cursor; omit to start at genesis (asc) or the head (desc)"
+		// @Param limit query int false "Max blocks (default 20, capped at 100)"
+		// @Param order query string false "asc or desc (default desc)"
+		// @Success 200 {array} integration.BlockRecord
+		// @Header 200 {string} X-Next-Cursor "Number of the last block returned"
+		// @Failure 400 {object} api.ErrorResponse
+		// @Router /api/v1/blocks [get]
+		api.GET("/blocks", func(c *gin.Context) {
+			order := c.DefaultQuery("order", "desc")
+			if order != "asc" && order != "desc" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "order must be asc or desc"})
+				return
+			}
+			limit := 20
+			if v := c.Query("limit"); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+					return
+				}
+				limit = min(n, 100)
+			}
+			// from is exclusive so X-Next-Cursor can be passed straight back
+			var fromSlot uint64
+			if order == "desc" {
+				fromSlot = math.MaxInt64
+			}
+			if v := c.Query("from"); v != "" {
+				from, err := strconv.ParseUint(v, 10, 63)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "invalid from"})
+					return
+				}
+				if order == "asc" {
+					fromSlot = from + 1
+				} else if from == 0 {
+					c.JSON(http.StatusOK, []integration.BlockRecord{})
+					return
+				} else {
+					fromSlot = from - 1
+				}
+			}
+			blocks, err := chainDB.ListBlocks(c.Request.Context(), fromSlot, limit, order)
+			if err != nil {
+				logger.FromContext(c.Request.Context()).Error("failed to list blocks", "from", fromSlot, "order", order, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list blocks"})
+				return
+			}
+			if len(blocks) == 0 {
+				blocks = []integration.BlockRecord{}
+			} else {
+				c.Header("X-Next-Cursor", strconv.FormatUint(blocks[len(blocks)-1].Number, 10))
+			}
+			c.JSON(http.StatusOK, blocks)
+		})
+
 		// Wallet endpoints
+		// @Summary Get wallet balance
+		// @Tags wallet
+		// @Produce json
+		// @Success 200 {object} api.Account
+		// @Router /api/v1/wallet/balance [get]
 		api.GET("/wallet/balance", func(c *gin.Context) {
 			// Implementation for getting wallet balance
 		})
 		
-		api.POST("/wallet/transfer", func(c *gin.Context) {
+		// @Summary List wallet transfer history
+		// @Description Newest first. Pass X-Next-Cursor back as before for the next page. The first page is cached for 10s.
+		// @Tags wallet
+		// @Produce json
+		// @Param address query string true "Wallet address"
+		// @Param limit query int false "Page size (default 20, max 100)"
+		// @Param before query string false "Return transfers older than this transaction ID"
+		// @Success 200 {array} api.WalletTransferRecord
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/wallet/history [get]
+		api.GET("/wallet/history", func(c *gin.Context) {
+			address := c.Query("address")
+			if address == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "address is required"})
+				return
+			}
+			limit := 20
+			if v := c.Query("limit"); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil || n < 1 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+					return
+				}
+				limit = min(n, 100)
+			}
+			before := c.Query("before")
+			ctx := c.Request.Context()
+
+			// Polling UIs only ever ask for the first page, so that is the one worth caching
+			var cacheKey string
+			var records []apimodel.WalletTransferRecord
+			if before == "" {
+				cacheKey = fmt.Sprintf("wallet_history:%s:%d", address, limit)
+				if cached, err := store.Redis.Get(ctx, cacheKey).Bytes(); err == nil && json.Unmarshal(cached, &records) == nil {
+					setWalletHistoryCursor(c, records, limit)
+					c.JSON(http.StatusOK, records)
+					return
+				}
+			}
+			if err := participantClient.WalletHistoryPaginated(ctx, address, before, limit, &records); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "failed to fetch wallet history"})
+				return
+			}
+			if records == nil {
+				records = []apimodel.WalletTransferRecord{}
+			}
+			if cacheKey != "" {
+				if b, err := json.Marshal(records); err == nil {
+					if err := store.Redis.Set(ctx, cacheKey, b, 10*time.Second).Err(); err != nil {
+						logger.FromContext(ctx).Warn("wallet history: failed to cache", "error", err)
+					}
+				}
+			}
+			setWalletHistoryCursor(c, records, limit)
+			c.JSON(http.StatusOK, records)
+		})
+
+		// Token issuance
+		// @Summary Mint tokens
+		// @Description Requires a bearer token with the minter role. The issuance is recorded for supply tracking once the participant accepts it.
+		// @Tags tokens
+		// @Accept json
+		// @Produce json
+		// @Param body body api.TokenMintRequest true "Asset, amount and recipient"
+		// @Success 200 {object} api.TransactionInfo
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 403 {object} api.ErrorResponse
+		// @Failure 422 {object} api.ValidationErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/tokens/mint [post]
+		api.POST("/tokens/mint", middleware.JWTAuth(cfg.Security.JWTSecret), middleware.RequireRole("minter"), dedup, middleware.ValidateBody(schema.TokenMint), func(c *gin.Context) {
+			var req apimodel.TokenMintRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx := c.Request.Context()
+			var tx apimodel.TransactionInfo
+			if err := participantClient.IssueTokens(ctx, req, &tx); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "token issuance failed"})
+				return
+			}
+			if tx.Status != nil && *tx.Status == "failed" {
+				c.JSON(http.StatusOK, tx)
+				return
+			}
+			if tx.ID != "" {
+				if err := store.RecordIssuance(ctx, req.AssetID, req.Amount, req.Recipient, tx.ID); err != nil {
+					logger.FromContext(ctx).Error("failed to record token issuance", "asset_id", req.AssetID, "tx_id", tx.ID, "error", err)
+				}
+			}
+			logger.FromContext(ctx).Info("tokens minted", "asset_id", req.AssetID, "amount", req.Amount, "recipient", req.Recipient, "minter", c.GetString(otel.UserIDKey), "tx_id", tx.ID)
+			c.JSON(http.StatusOK, tx)
+		})
+
+		// @Summary Get token supply
+		// @Description Total issued minus total burned, from recorded issuances.
+		// @Tags tokens
+		// @Produce json
+		// @Param asset_id path string true "Asset ID"
+		// @Success 200 {object} storage.TokenSupply
+		// @Failure 500 {object} api.ErrorResponse
+		// @Router /api/v1/tokens/{asset_id}/supply [get]
+		api.GET("/tokens/:asset_id/supply", func(c *gin.Context) {
+			supply, err := store.TokenSupply(c.Request.Context(), c.Param("asset_id"))
+			if err != nil {
+				logger.FromContext(c.Request.Context()).Error("failed to load token supply", "asset_id", c.Param("asset_id"), "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load token supply"})
+				return
+			}
+			c.JSON(http.StatusOK, supply)
+		})
+
+		// Staking: as with governance, the JWT subject is the staker's address, so a caller
+		// can only open, list and unstake their own positions.
+		stakeAuth := middleware.JWTAuth(cfg.Security.JWTSecret)
+
+		// @Summary Stake funds with a validator
+		// @Description The bearer token's subject is the staker's address.
+		// @Tags staking
+		// @Accept json
+		// @Produce json
+		// @Param body body api.StakeRequest true "Amount and validator"
+		// @Success 201 {object} storage.StakePosition
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/staking/stake [post]
+		api.POST("/staking/stake", stakeAuth, dedup, func(c *gin.Context) {
+			staker := c.GetString(otel.UserIDKey)
+			if staker == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has no subject"})
+				return
+			}
+			var req apimodel.StakeRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx := c.Request.Context()
+			var res struct {
+				ID string `json:"id"`
+			}
+			if err := participantClient.Stake(ctx, staker, req.Amount, req.Validator, &res); err != nil || res.ID == "" {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "stake failed"})
+				return
+			}
+			pos := storage.StakePosition{ID: res.ID, Address: staker, Amount: req.Amount, Validator: req.Validator, Status: storage.StakeActive, CreatedAt: time.Now().UTC()}
+			if err := store.CreateStakePosition(ctx, pos); err != nil {
+				logger.FromContext(ctx).Error("failed to record stake position", "stake_id", res.ID, "error", err)
+			}
+			c.JSON(http.StatusCreated, pos)
+		})
+
+		// @Summary Start unstaking a position
+		// @Description Only the position's staker may unstake it. The position stays listed with status unstaking until its funds are withdrawn.
+		// @Tags staking
+		// @Accept json
+		// @Produce json
+		// @Param body body api.UnstakeRequest true "Stake to withdraw"
+		// @Success 200 {object} storage.StakePosition
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 404 {object} api.ErrorResponse
+		// @Failure 409 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/staking/unstake [post]
+		api.POST("/staking/unstake", stakeAuth, dedup, func(c *gin.Context) {
+			staker := c.GetString(otel.UserIDKey)
+			if staker == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has no subject"})
+				return
+			}
+			var req apimodel.UnstakeRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx := c.Request.Context()
+			pos, err := store.GetStakePosition(ctx, req.StakeID)
+			if errors.Is(err, storage.ErrStakeNotFound) || (err == nil && pos.Address != staker) {
+				// Someone else's position is reported as missing, so stake IDs can't be probed
+				c.JSON(http.StatusNotFound, gin.H{"error": storage.ErrStakeNotFound.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load stake position"})
+				return
+			}
+			if pos.Status != storage.StakeActive {
+				c.JSON(http.StatusConflict, gin.H{"error": "stake position is " + pos.Status})
+				return
+			}
+			if err := participantClient.Unstake(ctx, req.StakeID, nil); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "unstake failed"})
+				return
+			}
+			if err := store.SetStakeStatus(ctx, req.StakeID, storage.StakeUnstaking); err != nil {
+				logger.FromContext(ctx).Error("failed to update stake position", "stake_id", req.StakeID, "error", err)
+			}
+			pos.Status = storage.StakeUnstaking
+			c.JSON(http.StatusOK, pos)
+		})
+
+		// @Summary List stake positions
+		// @Description The caller's active positions and those pending unstake, newest first.
+		// @Tags staking
+		// @Produce json
+		// @Success 200 {array} storage.StakePosition
+		// @Failure 401 {object} api.ErrorResponse
+		// @Router /api/v1/staking/positions [get]
+		api.GET("/staking/positions", stakeAuth, func(c *gin.Context) {
+			address := c.GetString(otel.UserIDKey)
+			if address == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has no subject"})
+				return
+			}
+			positions, err := store.ListStakePositions(c.Request.Context(), address)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list stake positions"})
+				return
+			}
+			c.JSON(http.StatusOK, positions)
+		})
+
+		// Governance: the JWT subject is the caller's chain address, so it is both the proposer
+		// and the voter and a caller cannot vote on anyone else's behalf.
+		govAuth := middleware.JWTAuth(cfg.Security.JWTSecret)
+
+		// @Summary Create a governance proposal
+		// @Description Voting stays open until the chain reaches voting_end_slot. If contract_id and choice are given, that choice is exercised when the proposal passes.
+		// @Tags governance
+		// @Accept json
+		// @Produce json
+		// @Param body body api.ProposalCreateRequest true "Proposal"
+		// @Success 201 {object} storage.Proposal
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/governance/proposals [post]
+		api.POST("/governance/proposals", govAuth, dedup, func(c *gin.Context) {
+			proposer := c.GetString(otel.UserIDKey)
+			if proposer == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has no subject"})
+				return
+			}
+			var req apimodel.ProposalCreateRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx := c.Request.Context()
+			var block apimodel.BlockInfo
+			if err := syncClient.LatestBlock(ctx, &block); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "failed to read current slot"})
+				return
+			}
+			if req.VotingEndSlot <= block.Slot {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("voting_end_slot must be after the current slot %d", block.Slot)})
+				return
+			}
+			p := storage.Proposal{Proposer: proposer, Description: req.Description, VotingEndSlot: req.VotingEndSlot, Arguments: req.Arguments}
+			if req.ContractID != "" {
+				p.ContractID, p.Choice = &req.ContractID, &req.Choice
+			}
+			p, err := store.CreateProposal(ctx, p)
+			if err != nil {
+				logger.FromContext(ctx).Error("failed to create proposal", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create proposal"})
+				return
+			}
+			c.JSON(http.StatusCreated, p)
+		})
+
+		// @Summary Vote on a governance proposal
+		// @Description Each address votes once per proposal, before its voting_end_slot.
+		// @Tags governance
+		// @Accept json
+		// @Produce json
+		// @Param id path string true "Proposal ID"
+		// @Param body body api.VoteRequest true "Vote"
+		// @Success 200 {object} storage.Proposal
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 404 {object} api.ErrorResponse
+		// @Failure 409 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Router /api/v1/governance/proposals/{id}/vote [post]
+		api.POST("/governance/proposals/:id/vote", govAuth, func(c *gin.Context) {
+			voter := c.GetString(otel.UserIDKey)
+			if voter == "" {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": "token has no subject"})
+				return
+			}
+			var req apimodel.VoteRequest
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ctx := c.Request.Context()
+			p, err := store.GetProposal(ctx, c.Param("id"))
+			if errors.Is(err, storage.ErrProposalNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load proposal"})
+				return
+			}
+			// The finalizer only runs every few seconds, so check the deadline here too
+			var block apimodel.BlockInfo
+			if err := syncClient.LatestBlock(ctx, &block); err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": "failed to read current slot"})
+				return
+			}
+			if block.Slot >= p.VotingEndSlot {
+				c.JSON(http.StatusConflict, gin.H{"error": storage.ErrProposalClosed.Error()})
+				return
+			}
+			switch err := store.CastVote(ctx, p.ID, voter, req.Vote); {
+			case errors.Is(err, storage.ErrProposalNotFound):
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			case errors.Is(err, storage.ErrProposalClosed), errors.Is(err, storage.ErrAlreadyVoted):
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			case err != nil:
+				logger.FromContext(ctx).Error("failed to record vote", "proposal_id", p.ID, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to record vote"})
+				return
+			}
+			if p, err = store.GetProposal(ctx, p.ID); err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load proposal"})
+				return
+			}
+			c.JSON(http.StatusOK, p)
+		})
+
+		// @Summary Get a governance proposal
+		// @Description The proposal with its current vote tally.
+		// @Tags governance
+		// @Produce json
+		// @Param id path string true "Proposal ID"
+		// @Success 200 {object} storage.Proposal
+		// @Failure 404 {object} api.ErrorResponse
+		// @Router /api/v1/governance/proposals/{id} [get]
+		api.GET("/governance/proposals/:id", func(c *gin.Context) {
+			p, err := store.GetProposal(c.Request.Context(), c.Param("id"))
+			if errors.Is(err, storage.ErrProposalNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load proposal"})
+				return
+			}
+			c.JSON(http.StatusOK, p)
+		})
+
+		// @Summary Transfer funds
+		// @Tags wallet
+		// @Accept json
+		// @Produce json
+		// @Success 200 {object} api.TransactionInfo
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 422 {object} api.ValidationErrorResponse
+		// @Router /api/v1/wallet/transfer [post]
+		api.POST("/wallet/transfer", dedup, middleware.ValidateBody(schema.WalletTransfer), func(c *gin.Context) {
 			// Implementation for transferring funds
 		})
 	}
 
+	// Chat endpoints (see docs/chat-api.md)
+	// @Summary Send a chat message
+	// @Description Stores client-encrypted content between two addresses. Sending the same content again returns the stored message.
+	// @Tags chat
+	// @Accept json
+	// @Produce json
+	// @Param body body api.MessageCreateRequest true "Message"
+	// @Success 201 {object} api.ChatMessage
+	// @Failure 400 {object} api.ErrorResponse
+	// @Failure 500 {object} api.ErrorResponse
+	// @Router /messages [post]
+	r.POST("/messages", func(c *gin.Context) {
+		var req apimodel.MessageCreateRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		m, err := messageBuffer.Add(c.Request.Context(), storage.MessageInput{
+			Sender:     req.Sender,
+			Recipient:  req.Recipient,
+			Ciphertext: []byte(req.ContentCiphertext),
+			Nonce:      []byte(req.ContentNonce),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to store message"})
+			return
+		}
+		c.JSON(http.StatusCreated, chatMessage(m))
+	})
+
+	// @Summary List chat messages
+	// @Description Messages exchanged between address and peer in either direction, oldest first.
+	// @Tags chat
+	// @Produce json
+	// @Param address query string true "One party"
+	// @Param peer query string true "The other party"
+	// @Param since query string false "RFC3339 lower bound on created_at"
+	// @Param limit query int false "Max messages (default 100, max 1000)"
+	// @Success 200 {array} api.ChatMessage
+	// @Failure 400 {object} api.ErrorResponse
+	// @Failure 500 {object} api.ErrorResponse
+	// @Router /messages [get]
+	r.GET("/messages", func(c *gin.Context) {
+		address, peer := c.Query("address"), c.Query("peer")
+		if address == "" || peer == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "address and peer are required"})
+			return
+		}
+		var since *time.Time
+		if v := c.Query("since"); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "since must be an RFC3339 timestamp"})
+				return
+			}
+			since = &t
+		}
+		limit := 100
+		if v := c.Query("limit"); v != "" {
+			var err error
+			if limit, err = strconv.Atoi(v); err != nil || limit < 1 || limit > 1000 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 1000"})
+				return
+			}
+		}
+		ctx := c.Request.Context()
+		var msgs []storage.Message
+		var err error
+		if encryptedStore != nil {
+			msgs, err = encryptedStore.ListMessages(ctx, tenant.FromContext(ctx), address, peer, since, limit)
+		} else {
+			msgs, err = store.ListMessages(ctx, address, peer, since, limit)
+		}
+		if err != nil {
+			logger.FromContext(ctx).Error("failed to list messages", "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list messages"})
+			return
+		}
+		out := make([]apimodel.ChatMessage, len(msgs))
+		for i, m := range msgs {
+			out[i] = chatMessage(m)
+		}
+		c.JSON(http.StatusOK, out)
+	})
+
+	// @Summary Unsend a chat message
+	// @Description Soft-deletes the message; only its sender, the bearer token's subject, may do so. The tombstone is kept for audit.
+	// @Tags chat
+	// @Produce json
+	// @Param id path int true "Message ID"
+	// @Success 204
+	// @Failure 400 {object} api.ErrorResponse
+	// @Failure 401 {object} api.ErrorResponse
+	// @Failure 403 {object} api.ErrorResponse
+	// @Failure 404 {object} api.ErrorResponse
+	// @Router /messages/{id} [delete]
+	r.DELETE("/messages/:id", middleware.JWTAuth(cfg.Security.JWTSecret), func(c *gin.Context) {
+		id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid message id"})
+			return
+		}
+		// The JWT subject is the caller's address, so only the sender can unsend
+		requester := c.GetString(otel.UserIDKey)
+		if requester == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token has no subject"})
+			return
+		}
+		switch err := store.DeleteMessage(c.Request.Context(), id, requester); {
+		case errors.Is(err, storage.ErrMessageNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		case errors.Is(err, storage.ErrNotMessageSender):
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete message"})
+		default:
+			c.Status(http.StatusNoContent)
+		}
+	})
+
 	// Enterprise integration endpoints
-	enterprise := r.Group("/enterprise")
+	enterpriseGuards := []gin.HandlerFunc{middleware.IPBlockList(cfg.Security.IPBlockList), middleware.IPAllowList(cfg.Security.IPAllowList)}
+	if enterpriseCAs != nil {
+		enterpriseGuards = append(enterpriseGuards, middleware.RequireClientCert(enterpriseCAs))
+	}
+	enterprise := r.Group("/enterprise", enterpriseGuards...)
 	{
 		// ERP integration
 		enterprise.POST("/erp/transaction", func(c *gin.Context) {
@@ -139,6 +1569,70 @@ func main() {
 			// Implementation for getting CRM contact details
 		})
 
+		// @Summary Search CRM contacts
+		// @Description Filters are optional; at most 100 contacts are returned.
+		// @Tags enterprise
+		// @Produce json
+		// @Param email query string false "Exact email"
+		// @Param company query string false "Company name"
+		// @Param name query string false "Contact name"
+		// @Success 200 {array} integration.CRMContact
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Failure 503 {object} api.ErrorResponse
+		// @Router /enterprise/crm/contacts [get]
+		enterprise.GET("/crm/contacts", func(c *gin.Context) {
+			if crm == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "CRM not configured"})
+				return
+			}
+			contacts, err := crm.SearchCRMContacts(c.Request.Context(), integration.CRMContactFilter{
+				Email:   c.Query("email"),
+				Company: c.Query("company"),
+				Name:    c.Query("name"),
+			})
+			if err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+				return
+			}
+			if contacts == nil {
+				contacts = []integration.CRMContact{}
+			}
+			c.JSON(http.StatusOK, contacts)
+		})
+
+		// @Summary Create CRM contacts in bulk
+		// @Description Contacts are created independently, five at a time; failures are reported per contact.
+		// @Tags enterprise
+		// @Accept json
+		// @Produce json
+		// @Param body body []integration.CRMContact true "Contacts to create"
+		// @Success 200 {object} integration.BulkCreateResult
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 503 {object} api.ErrorResponse
+		// @Router /enterprise/crm/contacts/bulk [post]
+		enterprise.POST("/crm/contacts/bulk", func(c *gin.Context) {
+			if crm == nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "CRM not configured"})
+				return
+			}
+			var contacts []integration.CRMContact
+			if err := c.ShouldBindJSON(&contacts); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			if len(contacts) == 0 {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "at least one contact is required"})
+				return
+			}
+			result, err := crm.BulkCreateCRMContacts(c.Request.Context(), contacts)
+			if err != nil {
+				logger.FromContext(c.Request.Context()).Warn("bulk CRM import interrupted", "created", result.Created, "failed", result.Failed, "error", err)
+			}
+			c.JSON(http.StatusOK, result)
+		})
+
 		// Database integration
 		enterprise.POST("/db/transaction", func(c *gin.Context) {
 			// Implementation for storing blockchain transactions in external databases
@@ -149,12 +1643,303 @@ func main() {
 		})
 
 		// Cloud integration
+		// @Summary Get a presigned S3 upload URL
+		// @Tags enterprise
+		// @Accept json
+		// @Produce json
+		// @Success 200 {object} map[string]interface{}
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 502 {object} api.ErrorResponse
+		// @Failure 503 {object} api.ErrorResponse
+		// @Router /enterprise/cloud/upload [post]
 		enterprise.POST("/cloud/upload", func(c *gin.Context) {
-			// Implementation for uploading data to cloud storage
+			// Hand out a presigned URL so the client uploads directly to S3
+			if s3Storage == nil || cfg.Cloud.S3Bucket == "" {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "cloud storage not configured"})
+				return
+			}
+			var req struct {
+				Key        string `json:"key" binding:"required"`
+				TTLSeconds int    `json:"ttl_seconds"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ttl := 15 * time.Minute
+			if req.TTLSeconds > 0 {
+				ttl = time.Duration(req.TTLSeconds) * time.Second
+			}
+			if ttl > 24*time.Hour {
+				ttl = 24 * time.Hour
+			}
+			url, err := s3Storage.PresignUpload(c.Request.Context(), cfg.Cloud.S3Bucket, req.Key, ttl)
+			if err != nil {
+				c.JSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"url": url, "method": http.MethodPut, "expires_at": time.Now().Add(ttl).UTC()})
 		})
 		
+		// @Summary Receive a signed blockchain event webhook
+		// @Tags enterprise
+		// @Accept json
+		// @Produce json
+		// @Param X-GARP-Signature header string true "sha256=<hex HMAC of the body>"
+		// @Success 202 {object} map[string]string
+		// @Failure 400 {object} api.ErrorResponse
+		// @Failure 401 {object} api.ErrorResponse
+		// @Failure 503 {object} api.ErrorResponse
+		// @Router /enterprise/cloud/webhook [post]
 		enterprise.POST("/cloud/webhook", func(c *gin.Context) {
-			// Implementation for receiving cloud webhooks
+			// Only accept webhooks signed with the shared secret
+			if cfg.Cloud.WebhookSecret == "" {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "webhook secret not configured"})
+				return
+			}
+			body, err := io.ReadAll(c.Request.Body)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+				return
+			}
+			if err := integration.VerifyWebhookSignature(cfg.Cloud.WebhookSecret, body, c.GetHeader(integration.WebhookSignatureHeader)); err != nil {
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+				return
+			}
+			var event integration.BlockchainEvent
+			if err := json.Unmarshal(body, &event); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid event payload"})
+				return
+			}
+			c.JSON(http.StatusAccepted, gin.H{"status": "accepted", "id": event.ID})
+		})
+	}
+
+	// Operational endpoints: localhost only unless ADMIN_TOKEN is set. Not part of the public API spec.
+	admin := r.Group("/admin", middleware.AdminAuth(cfg.Security.AdminToken))
+	{
+		admin.GET("/state/transactions", func(c *gin.Context) {
+			txs := stateManager.Transactions()
+			c.JSON(http.StatusOK, gin.H{"count": len(txs), "transactions": txs})
+		})
+
+		admin.GET("/state/transactions/:hash", func(c *gin.Context) {
+			tx, ok := stateManager.GetTx(c.Param("hash"))
+			if !ok {
+				c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+				return
+			}
+			c.JSON(http.StatusOK, tx)
+		})
+
+		admin.GET("/requests/:id", func(c *gin.Context) {
+			rec, err := recordings.Recording(c.Request.Context(), c.Param("id"))
+			if errors.Is(err, middleware.ErrRecordingNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, rec)
+		})
+
+		admin.POST("/requests/:id/replay", func(c *gin.Context) {
+			// Re-run a recorded request through this server. It carries the admin's
+			// X-Replay-Authorization, never the original caller's credentials.
+			ctx := c.Request.Context()
+			rec, err := recordings.Recording(ctx, c.Param("id"))
+			if errors.Is(err, middleware.ErrRecordingNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			if streamingRoutes[rec.Route] {
+				rec.Streaming = true
+			}
+			if reason := rec.Replayable(); reason != "" {
+				c.JSON(http.StatusConflict, gin.H{"error": reason})
+				return
+			}
+			target := url.URL{Path: rec.Path, RawQuery: url.Values(rec.Query).Encode()}
+			req, err := http.NewRequestWithContext(ctx, rec.Method, target.String(), bytes.NewReader(rec.Body))
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			for k, v := range rec.Headers {
+				req.Header[k] = v
+			}
+			req.Header.Del(middleware.RequestIDHeader)
+			req.Header.Set(middleware.ReplayOfHeader, rec.ID)
+			if auth := c.GetHeader(middleware.ReplayAuthorizationHeader); auth != "" {
+				req.Header.Set("Authorization", auth)
+			}
+			req.RemoteAddr = c.Request.RemoteAddr
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			result := middleware.NewReplayResult(w.Code, w.Header(), w.Body.Bytes())
+			if err := recordings.AddReplay(ctx, rec.ID, result); err != nil {
+				slog.Warn("admin: failed to save replay result", "id", rec.ID, "error", err)
+			}
+			slog.Info("admin: replayed request", "id", rec.ID, "method", rec.Method, "path", rec.Path, "original_status", rec.StatusCode, "replay_status", w.Code)
+			c.JSON(http.StatusOK, gin.H{"id": rec.ID, "original_status": rec.StatusCode, "replay": result})
+		})
+
+		admin.DELETE("/state/transactions/:hash", func(c *gin.Context) {
+			if !stateManager.DeleteTx(c.Param("hash")) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "transaction not found"})
+				return
+			}
+			slog.Info("admin: evicted transaction from state", "hash", c.Param("hash"))
+			c.Status(http.StatusNoContent)
+		})
+
+		admin.PUT("/rate-limit", func(c *gin.Context) {
+			// Same as `redis-cli SET rate_limit_rpm <n>`; 0 removes the override
+			var req struct {
+				RPM *int `json:"rpm" binding:"required,gte=0"`
+			}
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "rpm must be a non-negative integer"})
+				return
+			}
+			var err error
+			if *req.RPM == 0 {
+				err = store.Redis.Del(c.Request.Context(), middleware.RateLimitConfigKey).Err()
+			} else {
+				err = store.Redis.Set(c.Request.Context(), middleware.RateLimitConfigKey, *req.RPM, 0).Err()
+			}
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			slog.Info("admin: rate limit override set", "rpm", *req.RPM)
+			c.JSON(http.StatusOK, gin.H{"rpm": *req.RPM, "key": middleware.RateLimitConfigKey})
+		})
+
+		admin.POST("/migrations/run", func(c *gin.Context) {
+			// Migrations are idempotent; this re-applies them without a restart
+			start := time.Now()
+			if err := store.RunMigrations(c.Request.Context()); err != nil {
+				slog.Error("admin: migrations failed", "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			slog.Info("admin: migrations applied")
+			c.JSON(http.StatusOK, gin.H{"status": "ok", "duration_ms": time.Since(start).Milliseconds()})
+		})
+
+		admin.POST("/storage/lock-test", func(c *gin.Context) {
+			// Round-trip a short-lived lock to prove Redis locking works end to end
+			ctx := c.Request.Context()
+			start := time.Now()
+			release, err := store.AcquireLock(ctx, "admin:lock-test", 10*time.Second)
+			if errors.Is(err, storage.ErrLockHeld) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			if err := release(ctx); err != nil {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"status": "ok", "latency_ms": time.Since(start).Milliseconds()})
+		})
+
+		admin.GET("/messages/deleted", func(c *gin.Context) {
+			// Audit access to unsent messages of one participant
+			address := c.Query("address")
+			if address == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "address is required"})
+				return
+			}
+			var since *time.Time
+			if v := c.Query("since"); v != "" {
+				t, err := time.Parse(time.RFC3339, v)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "since must be RFC3339"})
+					return
+				}
+				since = &t
+			}
+			msgs, err := store.ListDeletedMessages(c.Request.Context(), address, since)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list deleted messages"})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"messages": msgs})
+		})
+
+		admin.DELETE("/db/archive", func(c *gin.Context) {
+			// Move old indexed transactions to cold storage; older_than defaults to 12 months
+			if cfg.Database.ArchiveDSN == "" {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "archive database not configured"})
+				return
+			}
+			olderThan := 365 * 24 * time.Hour
+			if v := c.Query("older_than"); v != "" {
+				d, err := time.ParseDuration(v)
+				if err != nil || d <= 0 {
+					c.JSON(http.StatusBadRequest, gin.H{"error": "older_than must be a positive duration, e.g. 8760h"})
+					return
+				}
+				olderThan = d
+			}
+			cutoff := time.Now().UTC().Add(-olderThan)
+			n, err := chainDB.ArchiveTransactions(c.Request.Context(), cutoff, cfg.Database.ArchiveDSN)
+			if err != nil {
+				slog.Error("admin: transaction archival failed", "cutoff", cutoff, "archived", n, "error", err)
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "archived": n})
+				return
+			}
+			slog.Info("admin: archived transactions", "cutoff", cutoff, "archived", n)
+			c.JSON(http.StatusOK, gin.H{"archived": n, "cutoff": cutoff})
+		})
+
+		admin.GET("/db/export", func(c *gin.Context) {
+			// Stream indexed transactions as CSV or JSON for offline analysis
+			format := integration.ExportFormat(c.DefaultQuery("format", string(integration.ExportCSV)))
+			contentType := "text/csv"
+			switch format {
+			case integration.ExportCSV:
+			case integration.ExportJSON:
+				contentType = "application/json"
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{"error": "format must be csv or json"})
+				return
+			}
+			filter := integration.TransactionFilter{Status: c.Query("status"), Submitter: c.Query("submitter")}
+			c.Header("Content-Type", contentType)
+			c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="transactions.%s"`, format))
+			c.Status(http.StatusOK)
+			if err := chainDB.ExportTransactions(c.Request.Context(), filter, format, c.Writer); err != nil {
+				slog.Error("admin: transaction export failed", "format", format, "error", err)
+				if !c.Writer.Written() {
+					c.Writer.Header().Del("Content-Disposition")
+					c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+					c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				}
+				// Otherwise the body is already streaming and the client sees a truncated file
+			}
+		})
+
+		admin.GET("/metrics/snapshot", func(c *gin.Context) {
+			snapshot, err := middleware.MetricsSnapshot()
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, gin.H{"metrics": snapshot})
 		})
 	}
 
@@ -163,12 +1948,62 @@ func main() {
         Addr:    fmt.Sprintf(":%d", cfg.Server.Port),
         Handler: r,
     }
+    if enterpriseCAs != nil {
+        // Certificates are verified during the handshake whenever one is sent, but only
+        // /enterprise insists on one, so probes and public routes keep working without.
+        srv.TLSConfig = &tls.Config{
+            ClientCAs:  enterpriseCAs,
+            ClientAuth: tls.VerifyClientCertIfGiven,
+            MinVersion: tls.VersionTLS12,
+        }
+    }
+    autoTLS := cfg.Server.AutoTLSDomain != ""
+    if autoTLS {
+        // Let's Encrypt validates over TLS-ALPN on this listener (port 443) or HTTP-01 on
+        // port 80; certificates and the account key are kept in Redis for every replica
+        certManager := &autocert.Manager{
+            Prompt:     autocert.AcceptTOS,
+            HostPolicy: autocert.HostWhitelist(cfg.Server.AutoTLSDomain),
+            Cache:      storage.NewRedisCache(store.Redis),
+        }
+        tlsCfg := certManager.TLSConfig()
+        tlsCfg.MinVersion = tls.VersionTLS12
+        if srv.TLSConfig != nil {
+            tlsCfg.ClientCAs, tlsCfg.ClientAuth = srv.TLSConfig.ClientCAs, srv.TLSConfig.ClientAuth
+        }
+        srv.TLSConfig = tlsCfg
+        go func() {
+            if err := http.ListenAndServe(":80", certManager.HTTPHandler(nil)); err != nil {
+                slog.Warn("ACME HTTP-01 listener unavailable; relying on TLS-ALPN", "error", err)
+            }
+        }()
+    }
 
-	// Run server in a goroutine
+    grpcServer := grpcserver.NewGRPCServer(participantClient, submitter, cfg.Security.JWTSecret)
+
+	// Run the HTTP and gRPC servers in goroutines
+    var servers sync.WaitGroup
+    servers.Add(2)
+    go func() {
+        defer servers.Done()
+        slog.Info("starting server", "port", cfg.Server.Port, "tls", cfg.Server.TLSCert != "" || autoTLS, "auto_tls_domain", cfg.Server.AutoTLSDomain)
+        var err error
+        if autoTLS {
+            err = srv.ListenAndServeTLS("", "") // certificates come from the autocert manager
+        } else if cfg.Server.TLSCert != "" {
+            err = srv.ListenAndServeTLS(cfg.Server.TLSCert, cfg.Server.TLSKey)
+        } else {
+            err = srv.ListenAndServe()
+        }
+        if err != nil && err != http.ErrServerClosed {
+            fatal("Failed to start server", err)
+        }
+    }()
     go func() {
-        log.Printf("Starting server on port %d", cfg.Server.Port)
-        if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-            log.Fatalf("Failed to start server: %v", err)
+        defer servers.Done()
+        slog.Info("starting gRPC server", "port", cfg.Server.GRPCPort)
+        if err := grpcServer.Serve(fmt.Sprintf(":%d", cfg.Server.GRPCPort)); err != nil {
+            fatal("Failed to start gRPC server", err)
         }
     }()
 
@@ -176,15 +2011,398 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	slog.Info("shutting down server")
+	stopApp()
 
 	// The context is used to inform the server it has 5 seconds to finish
 	// the request it is currently handling
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		fatal("Server forced to shutdown", err)
 	}
+	grpcServer.Stop(ctx)
+	servers.Wait()
+	if path := cfg.State.SnapshotPath; path != "" {
+		if err := writeStateSnapshot(context.Background(), stateManager, path); err != nil {
+			slog.Warn("failed to write state snapshot", "path", path, "error", err)
+		}
+	}
+
+	slog.Info("server exiting")
+}
+
+// participantContracts fetches an owner's contracts from the participant. The node may answer
+// with a bare array or wrapped as {"contracts": [...]}; entries without an owner are attributed
+// to the requested party since the query was filtered by it.
+func participantContracts(ctx context.Context, pc client.ParticipantClientInterface, owner string) ([]integration.ContractRecord, error) {
+    var raw json.RawMessage
+    if err := pc.ContractsByParty(ctx, owner, &raw); err != nil {
+        return nil, err
+    }
+    var list []integration.ContractRecord
+    if err := json.Unmarshal(raw, &list); err != nil {
+        var wrapped struct {
+            Contracts []integration.ContractRecord `json:"contracts"`
+        }
+        if err := json.Unmarshal(raw, &wrapped); err != nil {
+            return nil, fmt.Errorf("failed to decode participant contracts: %w", err)
+        }
+        list = wrapped.Contracts
+    }
+    for i := range list {
+        if list[i].Owner == "" {
+            list[i].Owner = owner
+        }
+        if list[i].Status == "" {
+            list[i].Status = "active"
+        }
+    }
+    return list, nil
+}
+
+// pushMetricsPeriodically pushes the default registry to the Pushgateway every interval
+// until ctx is cancelled. Failures are logged and retried on the next tick.
+func pushMetricsPeriodically(ctx context.Context, url, job string, interval time.Duration) {
+    host, _ := os.Hostname()
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        if err := middleware.PushMetrics(ctx, url, job, host); err != nil {
+            slog.Warn("metrics push failed", "error", err)
+        }
+    }
+}
+
+// restoreStateSnapshot loads the snapshot at path into store. A missing file is not an
+// error: there is simply nothing to restore on first start.
+func restoreStateSnapshot(ctx context.Context, store *state.Store, path string) error {
+    f, err := os.Open(path)
+    if errors.Is(err, os.ErrNotExist) { return nil }
+    if err != nil { return err }
+    defer f.Close()
+    if err := store.RestoreSnapshot(ctx, f); err != nil { return err }
+    slog.Info("restored state snapshot", "path", path)
+    return nil
+}
+
+// writeStateSnapshot snapshots store to a temporary file next to path and renames it into
+// place, so a crash mid-write never leaves a truncated snapshot behind.
+func writeStateSnapshot(ctx context.Context, store *state.Store, path string) error {
+    tmp := path + ".tmp"
+    f, err := os.Create(tmp)
+    if err != nil { return err }
+    err = store.Snapshot(ctx, f)
+    if cerr := f.Close(); err == nil { err = cerr }
+    if err != nil {
+        os.Remove(tmp)
+        return err
+    }
+    return os.Rename(tmp, path)
+}
+
+func snapshotStatePeriodically(ctx context.Context, store *state.Store, path string, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        if err := writeStateSnapshot(ctx, store, path); err != nil {
+            slog.Warn("failed to write state snapshot", "path", path, "error", err)
+        }
+    }
+}
+
+// alertFailingHealthChecks probes the store's health report every interval and posts to
+// Slack when a check goes from passing to failing. A check that keeps failing is reported
+// once, and again only after it has recovered in between.
+func alertFailingHealthChecks(ctx context.Context, store *storage.Storage, slack *integration.SlackNotifier, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    failing := map[string]bool{}
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        for name, st := range store.HealthReport(ctx) {
+            if st.OK || failing[name] {
+                failing[name] = !st.OK
+                continue
+            }
+            failing[name] = true
+            if err := slack.NotifyHealthCheckFailed(ctx, "", name, st.Error); err != nil {
+                slog.Warn("failed to post health alert to slack", "check", name, "error", err)
+            }
+        }
+    }
+}
+
+// txPoolBatchSize caps the transactions drainTxPool moves per tick.
+const txPoolBatchSize = 256
+
+// drainTxPool moves pooled transactions into the state store, oldest first, up to batch
+// per tick, until ctx is cancelled.
+func drainTxPool(ctx context.Context, pool *state.TxPool, store *state.Store, batch int, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        for _, tx := range pool.Pop(batch) { store.SubmitTx(tx) }
+    }
+}
+
+// publishFinalizedBlocks polls the synchronizer and publishes a block_finalized event
+// on integration.SubjectBlockFinalized for every new block it sees.
+func publishFinalizedBlocks(ctx context.Context, syncClient *client.SynchronizerClient, bus integration.EventBus, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    var last int64 = -1
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        var block apimodel.BlockInfo
+        if err := syncClient.LatestBlock(ctx, &block); err != nil {
+            continue // the client already logged it
+        }
+        if block.Slot <= last {
+            continue
+        }
+        last = block.Slot
+        event := integration.BlockchainToEnterpriseEvent{
+            ID:         fmt.Sprintf("block-%d", block.Slot),
+            EventType:  "block_finalized",
+            Timestamp:  time.Now().UTC(),
+            Blockchain: "garp",
+            Data:       block,
+        }
+        if err := bus.PublishEvent(ctx, integration.SubjectBlockFinalized, event); err != nil {
+            slog.Warn("failed to publish finalized block", "slot", block.Slot, "error", err)
+        }
+    }
+}
+
+// messageKEK returns the key source for chat message encryption named by the config, or
+// nil if none is configured, after checking that the key can be read. The returned func
+// releases the Secret Manager client.
+func messageKEK(cfg config.Config) (storage.KEKSource, func(), error) {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    var kek storage.KEKSource
+    closeKEK := func() {}
+    switch {
+    case cfg.Security.MessageKEKSecret != "":
+        cloud, err := integration.NewCloudIntegration(integration.CloudConfig{GCPProjectID: cfg.Cloud.GCPProjectID})
+        if err != nil { return nil, nil, err }
+        sm, err := cloud.NewGCPSecretManager(ctx)
+        if err != nil { return nil, nil, err }
+        kek, closeKEK = storage.SecretManagerKEK(sm, cfg.Security.MessageKEKSecret), func() { sm.Close() }
+    case cfg.Security.MessageKEKVaultPath != "":
+        vc := config.VaultConfigFromEnv()
+        client, err := config.NewVaultClient(vc)
+        if err != nil { return nil, nil, err }
+        var mu sync.Mutex
+        read := storage.VaultKEK(client, vc.Mount, cfg.Security.MessageKEKVaultPath, "kek")
+        kek = storage.KEKFunc(func(ctx context.Context) ([]byte, error) {
+            mu.Lock()
+            defer mu.Unlock()
+            key, err := read.KEK(ctx)
+            if err == nil { return key, nil }
+            // The login token may have expired: log in again and retry once
+            client, lerr := config.NewVaultClient(vc)
+            if lerr != nil { return nil, err }
+            read = storage.VaultKEK(client, vc.Mount, cfg.Security.MessageKEKVaultPath, "kek")
+            return read.KEK(ctx)
+        })
+    default:
+        return nil, func() {}, nil
+    }
+    if _, err := kek.KEK(ctx); err != nil {
+        closeKEK()
+        return nil, nil, err
+    }
+    return kek, closeKEK, nil
+}
+
+// chatMessage converts a stored message to its API form.
+func chatMessage(m storage.Message) apimodel.ChatMessage {
+    return apimodel.ChatMessage{
+        ID:                m.ID,
+        Sender:            m.Sender,
+        Recipient:         m.Recipient,
+        ContentCiphertext: string(m.ContentCiphertext),
+        ContentNonce:      string(m.ContentNonce),
+        Hash:              m.Hash,
+        CreatedAt:         m.CreatedAt.UTC().Format(time.RFC3339),
+        Anchored:          m.AnchoredAtBlock != nil,
+        BlockNumber:       m.AnchoredAtBlock,
+    }
+}
+
+// loadSecretManagerConfig reads the config secret from GCP Secret Manager in the project
+// named by GCP_PROJECT_ID.
+func loadSecretManagerConfig() (*config.Config, error) {
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    cloud, err := integration.NewCloudIntegration(integration.CloudConfig{GCPProjectID: os.Getenv("GCP_PROJECT_ID")})
+    if err != nil { return nil, err }
+    sm, err := cloud.NewGCPSecretManager(ctx)
+    if err != nil { return nil, err }
+    defer sm.Close()
+    return config.LoadSecretManager(ctx, sm, config.SecretManagerSecretName())
+}
+
+// bigQueryMaxCatchUp caps the blocks streamBlocksToBigQuery fetches in one tick after
+// falling behind; older blocks are skipped rather than delaying the latest.
+const bigQueryMaxCatchUp = 100
+
+// streamBlocksToBigQuery polls the synchronizer and streams the transactions of every new
+// block into dataset.table. Blocks produced between ticks are fetched with BlockRange.
+func streamBlocksToBigQuery(ctx context.Context, syncClient *client.SynchronizerClient, bq *integration.BigQueryStreamer, dataset, table string, interval time.Duration) {
+    ticker := time.NewTicker(interval)
+    defer ticker.Stop()
+    var last int64 = -1
+    for {
+        select {
+        case <-ctx.Done():
+            return
+        case <-ticker.C:
+        }
+        var latest apimodel.BlockInfo
+        if err := syncClient.LatestBlock(ctx, &latest); err != nil {
+            continue // the client already logged it
+        }
+        if latest.Slot <= last {
+            continue
+        }
+        blocks := []apimodel.BlockInfo{latest}
+        if last >= 0 && latest.Slot > last+1 {
+            from := max(last+1, latest.Slot-bigQueryMaxCatchUp+1)
+            if err := syncClient.BlockRange(ctx, from, latest.Slot, &blocks); err != nil {
+                continue // retry the whole range next tick
+            }
+        }
+        var txs []integration.TransactionRecord
+        for _, b := range blocks {
+            txs = append(txs, blockTransactionRecords(b)...)
+        }
+        if len(txs) > 0 {
+            if err := bq.StreamTransactions(ctx, dataset, table, txs); err != nil {
+                slog.Warn("failed to stream block transactions to BigQuery", "slot", latest.Slot, "count", len(txs), "error", err)
+                continue // insert IDs make the retry safe
+            }
+        }
+        last = latest.Slot
+    }
+}
+
+// blockTransactionRecords converts a block's transaction list to TransactionRecords.
+func blockTransactionRecords(b apimodel.BlockInfo) []integration.TransactionRecord {
+    if b.Transactions == nil { return nil }
+    var ts time.Time
+    if b.TimestampMs != nil { ts = time.UnixMilli(*b.TimestampMs).UTC() }
+    out := make([]integration.TransactionRecord, 0, len(*b.Transactions))
+    for _, tx := range *b.Transactions {
+        rec := integration.TransactionRecord{ID: tx.ID, Status: "confirmed", CreatedAt: ts, ConfirmedAt: ts, BlockNumber: uint64(b.Slot), BlockHash: b.Hash}
+        if tx.Submitter != nil { rec.Submitter = *tx.Submitter }
+        if tx.CommandType != nil {
+            data, _ := json.Marshal(map[string]string{"command_type": *tx.CommandType})
+            rec.Data = string(data)
+        }
+        out = append(out, rec)
+    }
+    return out
+}
+
+// setWalletHistoryCursor sets X-Next-Cursor to the last transfer's ID when the page is full.
+func setWalletHistoryCursor(c *gin.Context, records []apimodel.WalletTransferRecord, limit int) {
+    if len(records) == limit && limit > 0 {
+        c.Header("X-Next-Cursor", records[len(records)-1].TxID)
+    }
+}
+
+// nftTemplateID is the participant contract template POST /api/v1/nfts instantiates.
+const nftTemplateID = "Garp.Asset:NFT"
+
+// validMetadataURI reports whether s is an absolute URL that NFT metadata can be fetched from.
+func validMetadataURI(s string) bool {
+    u, err := url.Parse(s)
+    if err != nil { return false }
+    switch u.Scheme {
+    case "http", "https":
+        return u.Host != ""
+    case "ipfs", "ar":
+        return u.Host != "" || u.Opaque != ""
+    }
+    return false
+}
+
+// streamingRoutes are the routes whose responses stay open until the client disconnects.
+// They get no request deadline and are never replayed, even from a recording of an error
+// response.
+var streamingRoutes = map[string]bool{
+    "/api/v1/transactions/:id/events": true,
+}
+
+// streamTxStatus relays the status updates storage publishes on the request tenant's
+// tx:<id> channel as Server-Sent Events.
+func streamTxStatus(rdb *redis.Client) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        ctx := c.Request.Context()
+        sub := rdb.Subscribe(ctx, storage.TxChannel(tenant.FromContext(ctx), c.Param("id")))
+        defer sub.Close()
+        if _, err := sub.Receive(ctx); err != nil {
+            c.JSON(http.StatusServiceUnavailable, gin.H{"error": "event stream unavailable"})
+            return
+        }
+        c.Header("Content-Type", "text/event-stream")
+        c.Header("Cache-Control", "no-cache")
+        c.Header("Connection", "keep-alive")
+        c.Header("X-Accel-Buffering", "no")
+        c.Status(http.StatusOK)
+        c.Writer.Flush()
+
+        msgs := sub.Channel()
+        keepalive := time.NewTicker(15 * time.Second)
+        defer keepalive.Stop()
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case msg, ok := <-msgs:
+                if !ok {
+                    return
+                }
+                fmt.Fprintf(c.Writer, "data: %s\n\n", msg.Payload)
+            case <-keepalive.C:
+                fmt.Fprint(c.Writer, ": keepalive\n\n")
+            }
+            c.Writer.Flush()
+        }
+    }
+}
+
+// maxBatchSize caps the number of transactions accepted by POST /api/v1/transactions/batch.
+const maxBatchSize = 100
 
-	log.Println("Server exiting")
+// fatal logs err at error level and exits, like log.Fatalf.
+func fatal(msg string, err error) {
+    slog.Error(msg, "error", err)
+    os.Exit(1)
 }
\ No newline at end of file
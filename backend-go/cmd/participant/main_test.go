@@ -0,0 +1,177 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/gin-gonic/gin"
+    "github.com/redis/go-redis/v9"
+
+    "garp-backend/internal/client"
+    "garp-backend/internal/storage"
+    "garp-backend/internal/storage/storagetest"
+    "garp-backend/internal/txmonitor"
+)
+
+func TestStreamTxStatusOrder(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+    defer rdb.Close()
+
+    r := gin.New()
+    r.GET("/api/v1/transactions/:id/events", streamTxStatus(rdb))
+    srv := httptest.NewServer(r)
+    defer srv.Close()
+
+    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+    defer cancel()
+    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/transactions/tx-1/events", nil)
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer resp.Body.Close()
+    if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+        t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+    }
+
+    // Headers are only sent once the subscription is confirmed, so nothing published now is missed
+    want := []string{"pending", "confirmed", "finalized"}
+    for _, status := range want {
+        b, _ := json.Marshal(storage.TxStatusEvent{Hash: "tx-1", Status: status})
+        if err := rdb.Publish(ctx, storage.TxChannel("", "tx-1"), b).Err(); err != nil {
+            t.Fatal(err)
+        }
+    }
+
+    sc := bufio.NewScanner(resp.Body)
+    var got []string
+    for len(got) < len(want) && sc.Scan() {
+        data, ok := strings.CutPrefix(sc.Text(), "data: ")
+        if !ok {
+            continue
+        }
+        var ev storage.TxStatusEvent
+        if err := json.Unmarshal([]byte(data), &ev); err != nil {
+            t.Fatalf("bad frame %q: %v", data, err)
+        }
+        got = append(got, ev.Status)
+    }
+    if strings.Join(got, ",") != strings.Join(want, ",") {
+        t.Fatalf("statuses = %v, want %v", got, want)
+    }
+}
+
+func TestParticipantContracts(t *testing.T) {
+    tests := []struct {
+        name string
+        resp string
+    }{
+        {name: "bare list", resp: `[{"contract_id":"c1","template_id":"Garp.Asset:NFT"},{"contract_id":"c2","owner":"bob","status":"archived"}]`},
+        {name: "wrapped list", resp: `{"contracts":[{"contract_id":"c1","template_id":"Garp.Asset:NFT"},{"contract_id":"c2","owner":"bob","status":"archived"}]}`},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            mock := client.NewMock()
+            mock.SetResponse("ContractsByParty", json.RawMessage(tt.resp))
+
+            got, err := participantContracts(context.Background(), mock, "alice")
+            if err != nil {
+                t.Fatal(err)
+            }
+            if len(got) != 2 {
+                t.Fatalf("got %d contracts, want 2", len(got))
+            }
+            // Missing owner and status fall back to the queried party and "active"
+            if got[0].Owner != "alice" || got[0].Status != "active" {
+                t.Errorf("first contract = %+v, want owner alice and status active", got[0])
+            }
+            if got[1].Owner != "bob" || got[1].Status != "archived" {
+                t.Errorf("second contract = %+v, want its own owner and status", got[1])
+            }
+            if calls := mock.RecordedCalls(); len(calls) != 1 || calls[0].Method != "ContractsByParty" || calls[0].Args[0] != "alice" {
+                t.Errorf("calls = %+v, want one ContractsByParty for alice", calls)
+            }
+        })
+    }
+}
+
+// TestTxStatusMonitor follows a submitted transaction from the queue to its event stream and
+// the status hooks. It needs Docker for Postgres and Redis.
+func TestTxStatusMonitor(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+    defer cancel()
+    env, err := storagetest.Start(ctx)
+    if errors.Is(err, storagetest.ErrNoDocker) {
+        t.Skip(err)
+    }
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer env.Close()
+    store := env.Storage
+
+    hooked := make(chan string, 4)
+    store.OnTxStatus(func(_ context.Context, hash, status string) { hooked <- hash + ":" + status })
+
+    gin.SetMode(gin.TestMode)
+    r := gin.New()
+    r.GET("/api/v1/transactions/:id/events", streamTxStatus(store.Redis))
+    srv := httptest.NewServer(r)
+    defer srv.Close()
+    req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/api/v1/transactions/tx-monitored/events", nil)
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer resp.Body.Close()
+
+    if err := store.SaveTx(ctx, "tx-monitored", []byte(`{"submitter":"alice"}`)); err != nil {
+        t.Fatal(err)
+    }
+    mock := client.NewMock()
+    mock.SetResponse("GetTransaction", map[string]string{"id": "tx-monitored", "status": "confirmed"})
+    monitorCtx, stopMonitor := context.WithCancel(ctx)
+    defer stopMonitor()
+    go (&txmonitor.Monitor{Participant: mock}).Start(monitorCtx, store, 50*time.Millisecond)
+
+    want := []string{"pending", "confirmed"}
+    sc := bufio.NewScanner(resp.Body)
+    var got []string
+    for len(got) < len(want) && sc.Scan() {
+        data, ok := strings.CutPrefix(sc.Text(), "data: ")
+        if !ok {
+            continue
+        }
+        var ev storage.TxStatusEvent
+        if err := json.Unmarshal([]byte(data), &ev); err != nil {
+            t.Fatalf("bad frame %q: %v", data, err)
+        }
+        got = append(got, ev.Status)
+    }
+    if strings.Join(got, ",") != strings.Join(want, ",") {
+        t.Fatalf("streamed statuses = %v, want %v", got, want)
+    }
+    select {
+    case h := <-hooked:
+        if h != "tx-monitored:confirmed" {
+            t.Errorf("hook got %s, want tx-monitored:confirmed", h)
+        }
+    case <-ctx.Done():
+        t.Fatal("status hook never ran")
+    }
+
+    // A final status takes the transaction off the queue
+    stopMonitor()
+    if n, err := store.Redis.LLen(ctx, storage.TxQueue).Result(); err != nil || n != 0 {
+        t.Errorf("queue length = %d (%v), want 0", n, err)
+    }
+}
\ No newline at end of file
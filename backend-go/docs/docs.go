@@ -0,0 +1,8 @@
+// Package docs embeds the OpenAPI 3.0 contract of the backend HTTP API.
+// Regenerate openapi.json with `make generate-docs` after changing routes or schemas.
+package docs
+
+import _ "embed"
+
+//go:embed openapi.json
+var OpenAPI []byte
\ No newline at end of file
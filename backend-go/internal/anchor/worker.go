@@ -0,0 +1,84 @@
+package anchor
+
+import (
+    "context"
+    "errors"
+    "log/slog"
+    "time"
+
+    apimodel "garp-backend/internal/api"
+    "garp-backend/internal/client"
+    "garp-backend/internal/storage"
+)
+
+// DefaultBatchSize is used when AnchorWorker.BatchSize is zero.
+const DefaultBatchSize = 500
+
+// DefaultConfirmations is used when AnchorWorker.Confirmations is zero.
+const DefaultConfirmations = 6
+
+// AnchorWorker anchors chat messages to the first finalized block that follows them, so a
+// message's existence at a point in time can be proven against the chain.
+type AnchorWorker struct {
+    BatchSize     int // messages anchored per statement; zero uses DefaultBatchSize
+    Confirmations int // slots that must follow a block before it is final; zero uses DefaultConfirmations
+}
+
+// Start polls syncClient for the latest block every pollInterval, waits until that block is
+// final and then anchors every message created before its timestamp to it. It blocks until
+// ctx is cancelled and then returns nil. Blocks without a timestamp are skipped, as is a
+// block already anchored to.
+func (w *AnchorWorker) Start(ctx context.Context, s *storage.Storage, syncClient *client.SynchronizerClient, pollInterval time.Duration) error {
+    if pollInterval <= 0 { return errors.New("anchor worker: poll interval must be positive") }
+    batch := w.BatchSize
+    if batch <= 0 { batch = DefaultBatchSize }
+    confirmations := w.Confirmations
+    if confirmations <= 0 { confirmations = DefaultConfirmations }
+
+    ticker := time.NewTicker(pollInterval)
+    defer ticker.Stop()
+    var last int64 = -1
+    var pending *apimodel.BlockInfo // latest block seen, waiting for finality
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case <-ticker.C:
+        }
+        if pending == nil {
+            var block apimodel.BlockInfo
+            if err := syncClient.LatestBlock(ctx, &block); err != nil {
+                continue // the client already logged it
+            }
+            if block.Slot <= last || block.TimestampMs == nil {
+                continue
+            }
+            pending = &block
+        }
+        final, err := syncClient.BlockFinality(ctx, uint64(pending.Slot), confirmations)
+        if err != nil || !final {
+            continue
+        }
+        last = pending.Slot
+        w.anchorUpTo(ctx, s, pending.Slot, time.UnixMilli(*pending.TimestampMs), batch)
+        pending = nil
+    }
+}
+
+// anchorUpTo anchors messages created before cutoff to slot, batch by batch.
+func (w *AnchorWorker) anchorUpTo(ctx context.Context, s *storage.Storage, slot int64, cutoff time.Time, batch int) {
+    var total int64
+    for ctx.Err() == nil {
+        ids, err := s.UnanchoredMessageIDs(ctx, cutoff, batch)
+        if err != nil {
+            slog.Warn("anchor worker: failed to list messages", "slot", slot, "error", err)
+            return
+        }
+        if len(ids) == 0 { break }
+        n, err := s.AnchorMessages(ctx, ids, slot)
+        if err != nil { return } // AnchorMessages already logged it
+        total += n
+        if len(ids) < batch { break }
+    }
+    if total > 0 { slog.Info("anchored messages", "slot", slot, "count", total) }
+}
\ No newline at end of file
@@ -0,0 +1,207 @@
+// Package api holds the request/response schemas of the backend HTTP API.
+// The struct tags double as swag annotations for docs/openapi.json.
+package api
+
+import "encoding/json"
+
+// TransactionInfo is a submitted transaction as reported by the participant node.
+// @Description Transaction summary returned by the transaction endpoints
+type TransactionInfo struct {
+    ID        string  `json:"id" example:"9f2c4e..."`
+    Submitter *string `json:"submitter,omitempty" example:"garp1qxy..."`
+    Status    *string `json:"status,omitempty" example:"confirmed" enums:"pending,confirmed,failed"`
+    CreatedAt *int64  `json:"created_at,omitempty" example:"1717171717000"` // unix milliseconds
+    Error     *string `json:"error,omitempty"`
+}
+
+// BlockTx is the short transaction entry embedded in a block.
+type BlockTx struct {
+    ID          string  `json:"id" example:"9f2c4e..."`
+    Submitter   *string `json:"submitter,omitempty"`
+    CommandType *string `json:"command_type,omitempty" example:"transfer"`
+}
+
+// BlockInfo describes a block.
+// @Description Block header with its transaction list
+type BlockInfo struct {
+    Slot         int64      `json:"slot" example:"1024"`
+    Hash         string     `json:"hash" example:"0xab12..."`
+    ParentHash   *string    `json:"parent_hash,omitempty"`
+    TimestampMs  *int64     `json:"timestamp_ms,omitempty" example:"1717171717000"`
+    Leader       *string    `json:"leader,omitempty"`
+    Transactions *[]BlockTx `json:"transactions,omitempty"`
+}
+
+// Account is the state of an on-chain account.
+// @Description Account balance and nonce
+type Account struct {
+    Address string `json:"address" example:"garp1qxy..."`
+    Balance int64  `json:"balance" example:"250000"`
+    Nonce   uint64 `json:"nonce" example:"7"`
+}
+
+// SimulationResult is the outcome of a dry-run transaction.
+// @Description Result of simulating a transaction without committing it
+type SimulationResult struct {
+    Ok    bool     `json:"ok" example:"true"`
+    Logs  []string `json:"logs,omitempty"`
+    Error *string  `json:"error,omitempty"`
+}
+
+// ReadinessReport is returned by GET /ready, with 503 if any check failed.
+type ReadinessReport struct {
+    Status string                  `json:"status" example:"ready"`
+    Checks map[string]HealthStatus `json:"checks"`
+}
+
+// HealthStatus is one dependency probe in a ReadinessReport, as marshalled by storage.HealthStatus.
+type HealthStatus struct {
+    OK        bool    `json:"ok"`
+    LatencyMS float64 `json:"latency_ms" example:"1.25"`
+    Error     string  `json:"error,omitempty"`
+}
+
+// ErrorResponse is the body of every non-2xx JSON response.
+type ErrorResponse struct {
+    Error string `json:"error" example:"not found"`
+}
+
+// ValidationErrorResponse is returned with 422 when a body fails its JSON Schema (see backend-go/schema).
+type ValidationErrorResponse struct {
+    Error   string             `json:"error" example:"request body failed validation"`
+    Details []ValidationDetail `json:"details"`
+}
+
+// ValidationDetail is one failed schema keyword.
+type ValidationDetail struct {
+    Keyword string `json:"keyword" example:"required"`
+    Path    string `json:"path" example:"/commands/0"`
+    Message string `json:"message" example:"missing properties: 'type'"`
+}
+
+// BatchSubmitRequest is the body of POST /api/v1/transactions/batch.
+type BatchSubmitRequest struct {
+    Transactions []json.RawMessage `json:"transactions" binding:"required" swaggertype:"array,object"` // each item is a POST /api/v1/transactions body
+}
+
+// BatchSubmitResult reports the outcome of one transaction in a batch, in request order.
+type BatchSubmitResult struct {
+    ID      string             `json:"id,omitempty" example:"9f2c4e..."`
+    Error   string             `json:"error,omitempty"`
+    Details []ValidationDetail `json:"details,omitempty"` // failed schema keywords, when the item was invalid
+}
+
+// BatchSubmitResponse is returned by POST /api/v1/transactions/batch.
+type BatchSubmitResponse struct {
+    Results []BatchSubmitResult `json:"results"`
+}
+
+// MultiSigCreateRequest is the body of POST /api/v1/transactions/multisig.
+type MultiSigCreateRequest struct {
+    Commands  json.RawMessage `json:"commands" binding:"required" swaggertype:"array,object"`
+    Threshold int             `json:"threshold" binding:"required" example:"2"`
+    Signers   []string        `json:"signers" binding:"required"` // hex ed25519 public keys
+}
+
+// MultiSigSignRequest is the body of POST /api/v1/transactions/multisig/:id/sign.
+type MultiSigSignRequest struct {
+    Signer    string `json:"signer" binding:"required"`    // hex ed25519 public key
+    Signature string `json:"signature" binding:"required"` // hex ed25519 signature over the digest bytes
+}
+
+// MultiSigTransaction is an M-of-N transaction and the signatures collected so far.
+// @Description Multi-signature transaction; status is pending, executing, executed or failed
+type MultiSigTransaction struct {
+    ID         string            `json:"id"`
+    Commands   json.RawMessage   `json:"commands" swaggertype:"array,object"`
+    Threshold  int               `json:"threshold" example:"2"`
+    Signers    []string          `json:"signers"`
+    Signatures []string          `json:"signatures"` // aligned with Signers; "" until signed
+    Digest     string            `json:"digest"` // hex sha256; signers sign its raw bytes
+    Status     string            `json:"status" enums:"pending,executing,executed,failed"`
+    TxID       *string           `json:"tx_id,omitempty"`
+    CreatedAt  string            `json:"created_at" format:"date-time"`
+}
+
+// WalletTransferRecord is one entry of an address's transfer history.
+// @Description A transfer into or out of a wallet address
+type WalletTransferRecord struct {
+    TxID         string `json:"tx_id" example:"9f2c4e..."`
+    Direction    string `json:"direction" example:"out" enums:"in,out"`
+    Amount       int64  `json:"amount" example:"250"`
+    Counterparty string `json:"counterparty" example:"garp1qxy..."`
+    Timestamp    int64  `json:"timestamp" example:"1717171717000"` // unix milliseconds
+    Status       string `json:"status" example:"confirmed"`
+}
+
+// TokenMintRequest is the body of POST /api/v1/tokens/mint.
+type TokenMintRequest struct {
+    AssetID   string `json:"asset_id" binding:"required" example:"GARP-USD"`
+    Amount    int64  `json:"amount" binding:"required" example:"1000000"`
+    Recipient string `json:"recipient" binding:"required" example:"garp1qxy..."`
+    Memo      string `json:"memo,omitempty"`
+}
+
+// StakeRequest is the body of POST /api/v1/staking/stake. The staker is the bearer token's subject.
+type StakeRequest struct {
+    Amount    int64  `json:"amount" binding:"required,gt=0" example:"5000"`
+    Validator string `json:"validator" binding:"required" example:"garpvaloper1abc..."`
+}
+
+// UnstakeRequest is the body of POST /api/v1/staking/unstake.
+type UnstakeRequest struct {
+    StakeID string `json:"stake_id" binding:"required" example:"stk_9f2c4e"`
+}
+
+// ProposalCreateRequest is the body of POST /api/v1/governance/proposals. The optional
+// contract_id and choice name the on-chain action exercised if the proposal passes.
+type ProposalCreateRequest struct {
+    Description   string          `json:"description" binding:"required" example:"Raise the validator commission cap to 10%"`
+    VotingEndSlot int64           `json:"voting_end_slot" binding:"required,gt=0" example:"20480"`
+    ContractID    string          `json:"contract_id,omitempty" binding:"required_with=Choice" example:"00ab3f..."`
+    Choice        string          `json:"choice,omitempty" binding:"required_with=ContractID" example:"SetCommissionCap"`
+    Arguments     json.RawMessage `json:"arguments,omitempty" swaggertype:"object"`
+}
+
+// VoteRequest is the body of POST /api/v1/governance/proposals/{id}/vote.
+type VoteRequest struct {
+    Vote string `json:"vote" binding:"required,oneof=yes no abstain" example:"yes"`
+}
+
+// NFTCreateRequest is the body of POST /api/v1/nfts.
+type NFTCreateRequest struct {
+    Owner       string `json:"owner" binding:"required" example:"garp1qxy..."`
+    AssetID     string `json:"asset_id" binding:"required" example:"art-0001"`
+    MetadataURI string `json:"metadata_uri" binding:"required" example:"ipfs://bafy.../0001.json"`
+    Supply      int64  `json:"supply" example:"1"` // 1 for a unique NFT (the default), more for semi-fungibles
+}
+
+// FeeEstimate is returned by POST /api/v1/transactions/estimate.
+// @Description Estimated cost of a transaction, from a participant-side simulation
+type FeeEstimate struct {
+    Fee      int64  `json:"fee" example:"1500"`
+    GasLimit int64  `json:"gas_limit" example:"21000"`
+    Currency string `json:"currency" example:"GARP"`
+}
+
+// MessageCreateRequest is the body of POST /messages. Content is end-to-end encrypted by
+// the client; the backend stores it as given (sealed again at rest when a KEK is configured).
+type MessageCreateRequest struct {
+    Sender            string `json:"sender" binding:"required" example:"0xabc..."`
+    Recipient         string `json:"recipient" binding:"required" example:"0xdef..."`
+    ContentCiphertext string `json:"content_ciphertext" binding:"required"`
+    ContentNonce      string `json:"content_nonce" binding:"required"`
+}
+
+// ChatMessage is a chat message as returned by POST and GET /messages.
+type ChatMessage struct {
+    ID                int64  `json:"id" example:"42"`
+    Sender            string `json:"sender" example:"0xabc..."`
+    Recipient         string `json:"recipient" example:"0xdef..."`
+    ContentCiphertext string `json:"content_ciphertext"`
+    ContentNonce      string `json:"content_nonce"`
+    Hash              string `json:"hash"` // hex SHA-256 of ciphertext || nonce
+    CreatedAt         string `json:"created_at" format:"date-time"`
+    Anchored          bool   `json:"anchored"`
+    BlockNumber       *int64 `json:"block_number"` // set once anchored
+}
\ No newline at end of file
@@ -0,0 +1,163 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// RecordedCall is one call made against a MockParticipantClient.
+type RecordedCall struct {
+	Method string
+	Args   []any // call arguments after ctx, excluding out
+}
+
+// MockParticipantClient implements ParticipantClientInterface without a network.
+// Responses are keyed by method name and JSON round-tripped into the caller's out
+// value, so they can be structs, maps or raw JSON. An entry in Errors makes that
+// method fail instead. Every call is recorded, including failed ones.
+type MockParticipantClient struct {
+	mu        sync.Mutex
+	responses map[string]any
+	errors    map[string]error
+	calls     []RecordedCall
+}
+
+var _ ParticipantClientInterface = (*MockParticipantClient)(nil)
+
+func NewMock() *MockParticipantClient {
+	return &MockParticipantClient{responses: make(map[string]any), errors: make(map[string]error)}
+}
+
+// SetResponse makes method decode v into out on every subsequent call.
+func (m *MockParticipantClient) SetResponse(method string, v any) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[method] = v
+}
+
+// SetError makes method return err on every subsequent call; nil clears it.
+func (m *MockParticipantClient) SetError(method string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err == nil {
+		delete(m.errors, method)
+		return
+	}
+	m.errors[method] = err
+}
+
+// RecordedCalls returns the calls made so far, oldest first.
+func (m *MockParticipantClient) RecordedCalls() []RecordedCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]RecordedCall(nil), m.calls...)
+}
+
+// Reset clears recorded calls, responses and errors.
+func (m *MockParticipantClient) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = nil
+	m.responses = make(map[string]any)
+	m.errors = make(map[string]error)
+}
+
+func (m *MockParticipantClient) respond(ctx context.Context, method string, out any, args ...any) error {
+	m.mu.Lock()
+	m.calls = append(m.calls, RecordedCall{Method: method, Args: args})
+	resp, hasResp := m.responses[method]
+	failWith := m.errors[method]
+	m.mu.Unlock()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if failWith != nil {
+		return failWith
+	}
+	if out == nil || !hasResp {
+		return nil
+	}
+	var b []byte
+	switch v := resp.(type) {
+	case []byte:
+		b = v
+	case json.RawMessage:
+		b = v
+	default:
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			return fmt.Errorf("mock %s: failed to encode response: %w", method, err)
+		}
+	}
+	return json.Unmarshal(b, out)
+}
+
+func (m *MockParticipantClient) Health(ctx context.Context) error {
+	return m.respond(ctx, "Health", nil)
+}
+func (m *MockParticipantClient) NodeStatus(ctx context.Context, out any) error {
+	return m.respond(ctx, "NodeStatus", out)
+}
+func (m *MockParticipantClient) SubmitTransaction(ctx context.Context, in any, out any) error {
+	return m.respond(ctx, "SubmitTransaction", out, in)
+}
+func (m *MockParticipantClient) SimulateTransaction(ctx context.Context, in any, out any) error {
+	return m.respond(ctx, "SimulateTransaction", out, in)
+}
+func (m *MockParticipantClient) GetTransaction(ctx context.Context, id string, out any) error {
+	return m.respond(ctx, "GetTransaction", out, id)
+}
+func (m *MockParticipantClient) CreateContract(ctx context.Context, in any, out any) error {
+	return m.respond(ctx, "CreateContract", out, in)
+}
+func (m *MockParticipantClient) GetContract(ctx context.Context, id string, out any) error {
+	return m.respond(ctx, "GetContract", out, id)
+}
+func (m *MockParticipantClient) IssueTokens(ctx context.Context, in any, out any) error {
+	return m.respond(ctx, "IssueTokens", out, in)
+}
+func (m *MockParticipantClient) ExerciseContract(ctx context.Context, id string, in any, out any) error {
+	return m.respond(ctx, "ExerciseContract", out, id, in)
+}
+func (m *MockParticipantClient) ArchiveContract(ctx context.Context, id string) error {
+	return m.respond(ctx, "ArchiveContract", nil, id)
+}
+func (m *MockParticipantClient) Contracts(ctx context.Context, out any) error {
+	return m.respond(ctx, "Contracts", out)
+}
+func (m *MockParticipantClient) ContractsByParty(ctx context.Context, party string, out any) error {
+	return m.respond(ctx, "ContractsByParty", out, party)
+}
+func (m *MockParticipantClient) AccountBalance(ctx context.Context, address string, out any) error {
+	return m.respond(ctx, "AccountBalance", out, address)
+}
+func (m *MockParticipantClient) WalletBalances(ctx context.Context, out any) error {
+	return m.respond(ctx, "WalletBalances", out)
+}
+func (m *MockParticipantClient) WalletHistory(ctx context.Context, out any) error {
+	return m.respond(ctx, "WalletHistory", out)
+}
+func (m *MockParticipantClient) WalletHistoryPaginated(ctx context.Context, address, beforeTxID string, limit int, out any) error {
+	return m.respond(ctx, "WalletHistoryPaginated", out, address, beforeTxID, limit)
+}
+func (m *MockParticipantClient) Stake(ctx context.Context, staker string, amount int64, validator string, out any) error {
+	return m.respond(ctx, "Stake", out, staker, amount, validator)
+}
+func (m *MockParticipantClient) Unstake(ctx context.Context, stakeID string, out any) error {
+	return m.respond(ctx, "Unstake", out, stakeID)
+}
+func (m *MockParticipantClient) LatestBlock(ctx context.Context, out any) error {
+	return m.respond(ctx, "LatestBlock", out)
+}
+func (m *MockParticipantClient) BlockByNumber(ctx context.Context, n uint64, out any) error {
+	return m.respond(ctx, "BlockByNumber", out, n)
+}
+func (m *MockParticipantClient) BlockByHash(ctx context.Context, h string, out any) error {
+	return m.respond(ctx, "BlockByHash", out, h)
+}
+func (m *MockParticipantClient) LedgerCheckpoint(ctx context.Context, out any) error {
+	return m.respond(ctx, "LedgerCheckpoint", out)
+}
@@ -2,16 +2,52 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"time"
+
+	"garp-backend/internal/logger"
 )
 
+// ParticipantClientInterface is the set of participant node calls the backend makes.
+// ParticipantClient talks to a live node; MockParticipantClient stands in for it in tests.
+type ParticipantClientInterface interface {
+	Health(ctx context.Context) error
+	NodeStatus(ctx context.Context, out any) error
+	SubmitTransaction(ctx context.Context, in any, out any) error
+	SimulateTransaction(ctx context.Context, in any, out any) error
+	GetTransaction(ctx context.Context, id string, out any) error
+	CreateContract(ctx context.Context, in any, out any) error
+	GetContract(ctx context.Context, id string, out any) error
+	IssueTokens(ctx context.Context, in any, out any) error
+	ExerciseContract(ctx context.Context, id string, in any, out any) error
+	ArchiveContract(ctx context.Context, id string) error
+	Contracts(ctx context.Context, out any) error
+	ContractsByParty(ctx context.Context, party string, out any) error
+	AccountBalance(ctx context.Context, address string, out any) error
+	WalletBalances(ctx context.Context, out any) error
+	WalletHistory(ctx context.Context, out any) error
+	WalletHistoryPaginated(ctx context.Context, address, beforeTxID string, limit int, out any) error
+	Stake(ctx context.Context, staker string, amount int64, validator string, out any) error
+	Unstake(ctx context.Context, stakeID string, out any) error
+	LatestBlock(ctx context.Context, out any) error
+	BlockByNumber(ctx context.Context, n uint64, out any) error
+	BlockByHash(ctx context.Context, h string, out any) error
+	LedgerCheckpoint(ctx context.Context, out any) error
+}
+
+var _ ParticipantClientInterface = (*ParticipantClient)(nil)
+
 type ParticipantClient struct {
+	mu   sync.RWMutex
 	base string
 	http *http.Client
 }
@@ -20,6 +56,19 @@ func New(base string) *ParticipantClient {
 	return &ParticipantClient{base: base, http: &http.Client{Timeout: 15 * time.Second}}
 }
 
+// SetBaseURL points the client at a different participant node
+func (c *ParticipantClient) SetBaseURL(base string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.base = base
+}
+
+func (c *ParticipantClient) baseURL() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.base
+}
+
 // WithTLS configures mTLS for the client if certs are provided
 func (c *ParticipantClient) WithTLS(clientCertFile, clientKeyFile, caCertFile string) error {
 	if clientCertFile == "" || clientKeyFile == "" || caCertFile == "" {
@@ -42,42 +91,47 @@ func (c *ParticipantClient) WithTLS(clientCertFile, clientKeyFile, caCertFile st
 	return nil
 }
 
-func (c *ParticipantClient) get(path string, out any) error {
-	resp, err := c.http.Get(c.base + path)
+func (c *ParticipantClient) get(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL()+path, nil)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("participant %s returned %d", path, resp.StatusCode)
-	}
-	return json.NewDecoder(resp.Body).Decode(out)
+	return c.do(req, path, out)
 }
 
-func (c *ParticipantClient) post(path string, in any, out any) error {
+func (c *ParticipantClient) post(ctx context.Context, path string, in any, out any) error {
 	buf := &bytes.Buffer{}
 	if err := json.NewEncoder(buf).Encode(in); err != nil {
 		return err
 	}
-	resp, err := c.http.Post(c.base+path, "application/json", buf)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL()+path, buf)
 	if err != nil {
 		return err
 	}
-	defer resp.Body.Close()
-	if resp.StatusCode >= 300 {
-		return fmt.Errorf("participant %s returned %d", path, resp.StatusCode)
+	req.Header.Set("Content-Type", "application/json")
+	return c.do(req, path, out)
+}
+
+func (c *ParticipantClient) delete(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.baseURL()+path, nil)
+	if err != nil {
+		return err
 	}
-	return json.NewDecoder(resp.Body).Decode(out)
+	return c.do(req, path, out)
 }
 
-func (c *ParticipantClient) delete(path string, out any) error {
-	req, _ := http.NewRequest(http.MethodDelete, c.base+path, nil)
+// do sends req and decodes the JSON response into out (if non-nil). Failures are
+// logged with the request-scoped logger so they carry trace_id/span_id.
+func (c *ParticipantClient) do(req *http.Request, path string, out any) error {
+	log := logger.FromContext(req.Context())
 	resp, err := c.http.Do(req)
 	if err != nil {
+		log.Error("participant request failed", "method", req.Method, "path", path, "error", err)
 		return err
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode >= 300 {
+		log.Warn("participant returned error status", "method", req.Method, "path", path, "status", resp.StatusCode)
 		return fmt.Errorf("participant %s returned %d", path, resp.StatusCode)
 	}
 	if out != nil {
@@ -86,35 +140,87 @@ func (c *ParticipantClient) delete(path string, out any) error {
 	return nil
 }
 
+// Health reports whether the participant node answers its /health endpoint.
+func (c *ParticipantClient) Health(ctx context.Context) error {
+	return c.get(ctx, "/health", nil)
+}
+
 // Proxies for known participant endpoints
-func (c *ParticipantClient) NodeStatus(out any) error { return c.get("/api/v1/node/status", out) }
-func (c *ParticipantClient) SubmitTransaction(in any, out any) error {
-	return c.post("/api/v1/transactions", in, out)
+func (c *ParticipantClient) NodeStatus(ctx context.Context, out any) error {
+	return c.get(ctx, "/api/v1/node/status", out)
+}
+func (c *ParticipantClient) SubmitTransaction(ctx context.Context, in any, out any) error {
+	return c.post(ctx, "/api/v1/transactions", in, out)
+}
+func (c *ParticipantClient) SimulateTransaction(ctx context.Context, in any, out any) error {
+	return c.post(ctx, "/api/v1/transactions/simulate", in, out)
+}
+func (c *ParticipantClient) GetTransaction(ctx context.Context, id string, out any) error {
+	return c.get(ctx, "/api/v1/transactions/"+id, out)
+}
+func (c *ParticipantClient) CreateContract(ctx context.Context, in any, out any) error {
+	return c.post(ctx, "/api/v1/contracts", in, out)
 }
-func (c *ParticipantClient) GetTransaction(id string, out any) error {
-	return c.get("/api/v1/transactions/"+id, out)
+func (c *ParticipantClient) GetContract(ctx context.Context, id string, out any) error {
+	return c.get(ctx, "/api/v1/contracts/"+url.PathEscape(id), out)
 }
-func (c *ParticipantClient) CreateContract(in any, out any) error {
-	return c.post("/api/v1/contracts", in, out)
+
+// IssueTokens submits a token issuance to the participant's issuance contract.
+func (c *ParticipantClient) IssueTokens(ctx context.Context, in any, out any) error {
+	return c.post(ctx, "/api/v1/tokens/issue", in, out)
+}
+func (c *ParticipantClient) ExerciseContract(ctx context.Context, id string, in any, out any) error {
+	return c.post(ctx, "/api/v1/contracts/"+id+"/exercise", in, out)
+}
+func (c *ParticipantClient) ArchiveContract(ctx context.Context, id string) error {
+	return c.delete(ctx, "/api/v1/contracts/"+id+"/archive", nil)
 }
-func (c *ParticipantClient) ExerciseContract(id string, in any, out any) error {
-	return c.post("/api/v1/contracts/"+id+"/exercise", in, out)
+func (c *ParticipantClient) Contracts(ctx context.Context, out any) error {
+	return c.get(ctx, "/api/v1/contracts", out)
+}
+
+// ContractsByParty lists active contracts filtered by party on the participant.
+func (c *ParticipantClient) ContractsByParty(ctx context.Context, party string, out any) error {
+	return c.get(ctx, "/api/v1/contracts?party="+url.QueryEscape(party), out)
 }
-func (c *ParticipantClient) ArchiveContract(id string) error {
-	return c.delete("/api/v1/contracts/"+id+"/archive", nil)
+func (c *ParticipantClient) AccountBalance(ctx context.Context, address string, out any) error {
+	return c.get(ctx, "/api/v1/accounts/"+address+"/balance", out)
+}
+func (c *ParticipantClient) WalletBalances(ctx context.Context, out any) error {
+	return c.get(ctx, "/api/v1/wallet/balances", out)
+}
+func (c *ParticipantClient) WalletHistory(ctx context.Context, out any) error {
+	return c.get(ctx, "/api/v1/wallet/history", out)
+}
+
+// WalletHistoryPaginated lists up to limit transfers of address, newest first, starting
+// after beforeTxID when it is non-empty.
+func (c *ParticipantClient) WalletHistoryPaginated(ctx context.Context, address, beforeTxID string, limit int, out any) error {
+	q := url.Values{"address": {address}, "limit": {strconv.Itoa(limit)}}
+	if beforeTxID != "" {
+		q.Set("before", beforeTxID)
+	}
+	return c.get(ctx, "/api/v1/wallet/history?"+q.Encode(), out)
+}
+
+// Stake delegates amount from staker's wallet to validator.
+func (c *ParticipantClient) Stake(ctx context.Context, staker string, amount int64, validator string, out any) error {
+	return c.post(ctx, "/api/v1/staking/stake", map[string]any{"staker": staker, "amount": amount, "validator": validator}, out)
+}
+
+// Unstake starts withdrawing a stake; funds unlock once the unbonding period ends.
+func (c *ParticipantClient) Unstake(ctx context.Context, stakeID string, out any) error {
+	return c.post(ctx, "/api/v1/staking/unstake", map[string]any{"stake_id": stakeID}, out)
 }
-func (c *ParticipantClient) Contracts(out any) error { return c.get("/api/v1/contracts", out) }
-func (c *ParticipantClient) WalletBalances(out any) error {
-	return c.get("/api/v1/wallet/balances", out)
+func (c *ParticipantClient) LatestBlock(ctx context.Context, out any) error {
+	return c.get(ctx, "/api/v1/blocks/latest", out)
 }
-func (c *ParticipantClient) WalletHistory(out any) error { return c.get("/api/v1/wallet/history", out) }
-func (c *ParticipantClient) LatestBlock(out any) error   { return c.get("/api/v1/blocks/latest", out) }
-func (c *ParticipantClient) BlockByNumber(n uint64, out any) error {
-	return c.get(fmt.Sprintf("/api/v1/blocks/%d", n), out)
+func (c *ParticipantClient) BlockByNumber(ctx context.Context, n uint64, out any) error {
+	return c.get(ctx, fmt.Sprintf("/api/v1/blocks/%d", n), out)
 }
-func (c *ParticipantClient) BlockByHash(h string, out any) error {
-	return c.get("/api/v1/blocks/hash/"+h, out)
+func (c *ParticipantClient) BlockByHash(ctx context.Context, h string, out any) error {
+	return c.get(ctx, "/api/v1/blocks/hash/"+h, out)
 }
-func (c *ParticipantClient) LedgerCheckpoint(out any) error {
-	return c.get("/api/v1/ledger/checkpoint", out)
+func (c *ParticipantClient) LedgerCheckpoint(ctx context.Context, out any) error {
+	return c.get(ctx, "/api/v1/ledger/checkpoint", out)
 }
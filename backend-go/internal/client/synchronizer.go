@@ -1,34 +1,159 @@
 package client
 
 import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
     "encoding/json"
+    "errors"
     "fmt"
+    "io"
     "net/http"
+    "strings"
     "time"
     "bytes"
+
+    apimodel "garp-backend/internal/api"
+    "garp-backend/internal/logger"
 )
 
 type SynchronizerClient struct { base string; http *http.Client }
 
 func NewSynchronizer(base string) *SynchronizerClient { return &SynchronizerClient{ base: base, http: &http.Client{ Timeout: 10 * time.Second } } }
 
-func (c *SynchronizerClient) get(path string, out any) error {
-    resp, err := c.http.Get(c.base + path)
+func (c *SynchronizerClient) get(ctx context.Context, path string, out any) error {
+    return c.do(ctx, http.MethodGet, path, nil, out)
+}
+
+// do sends the request and decodes the JSON response, logging failures with the request-scoped logger.
+func (c *SynchronizerClient) do(ctx context.Context, method, path string, body io.Reader, out any) error {
+    log := logger.FromContext(ctx)
+    req, err := http.NewRequestWithContext(ctx, method, c.base+path, body)
     if err != nil { return err }
+    if body != nil { req.Header.Set("Content-Type", "application/json") }
+    resp, err := c.http.Do(req)
+    if err != nil {
+        log.Error("synchronizer request failed", "method", method, "path", path, "error", err)
+        return err
+    }
     defer resp.Body.Close()
-    if resp.StatusCode >= 300 { return fmt.Errorf("synchronizer %s returned %d", path, resp.StatusCode) }
+    if resp.StatusCode >= 300 {
+        log.Warn("synchronizer returned error status", "method", method, "path", path, "status", resp.StatusCode)
+        return fmt.Errorf("synchronizer %s returned %d", path, resp.StatusCode)
+    }
     return json.NewDecoder(resp.Body).Decode(out)
 }
 
-func (c *SynchronizerClient) LatestBlock(out any) error { return c.get("/api/v1/blocks/latest", out) }
-func (c *SynchronizerClient) BlockByNumber(n uint64, out any) error { return c.get(fmt.Sprintf("/api/v1/blocks/%d", n), out) }
-func (c *SynchronizerClient) Status(out any) error { return c.get("/api/v1/status", out) }
-func (c *SynchronizerClient) TxStatus(id string, out any) error { return c.get("/api/v1/transactions/"+id+"/status", out) }
-func (c *SynchronizerClient) SubmitTransaction(in any, out any) error {
-    b, _ := json.Marshal(in)
-    resp, err := c.http.Post(c.base+"/api/v1/transactions", "application/json", bytes.NewReader(b))
+func (c *SynchronizerClient) LatestBlock(ctx context.Context, out any) error { return c.get(ctx, "/api/v1/blocks/latest", out) }
+func (c *SynchronizerClient) BlockByNumber(ctx context.Context, n uint64, out any) error { return c.get(ctx, fmt.Sprintf("/api/v1/blocks/%d", n), out) }
+func (c *SynchronizerClient) Status(ctx context.Context, out any) error { return c.get(ctx, "/api/v1/status", out) }
+func (c *SynchronizerClient) TxStatus(ctx context.Context, id string, out any) error { return c.get(ctx, "/api/v1/transactions/"+id+"/status", out) }
+func (c *SynchronizerClient) SubmitTransaction(ctx context.Context, in any, out any) error {
+    b, err := json.Marshal(in)
     if err != nil { return err }
-    defer resp.Body.Close()
-    if resp.StatusCode >= 300 { return fmt.Errorf("synchronizer /transactions returned %d", resp.StatusCode) }
-    return json.NewDecoder(resp.Body).Decode(out)
+    return c.do(ctx, http.MethodPost, "/api/v1/transactions", bytes.NewReader(b), out)
+}
+
+// BlockRange fetches the blocks with slots from..to inclusive, in ascending slot order.
+func (c *SynchronizerClient) BlockRange(ctx context.Context, from, to int64, out any) error {
+    return c.get(ctx, fmt.Sprintf("/api/v1/blocks?from=%d&to=%d", from, to), out)
+}
+
+// BlockFinality reports whether the block at blockNumber is final, i.e. the latest block is
+// at least requiredConfirmations slots past it. A block beyond the latest one is not final.
+func (c *SynchronizerClient) BlockFinality(ctx context.Context, blockNumber uint64, requiredConfirmations int) (bool, error) {
+    if requiredConfirmations < 0 { return false, errors.New("required confirmations must not be negative") }
+    var latest apimodel.BlockInfo
+    if err := c.LatestBlock(ctx, &latest); err != nil { return false, err }
+    if latest.Slot < 0 || blockNumber > uint64(latest.Slot) { return false, nil }
+    return uint64(latest.Slot)-blockNumber >= uint64(requiredConfirmations), nil
+}
+
+// WaitForFinality polls BlockFinality every pollInterval until the block is final, and
+// returns ctx's error if it expires first. Failed polls are retried on the next interval.
+func (c *SynchronizerClient) WaitForFinality(ctx context.Context, blockNumber uint64, requiredConfirmations int, pollInterval time.Duration) error {
+    if pollInterval <= 0 { return errors.New("poll interval must be positive") }
+    ticker := time.NewTicker(pollInterval)
+    defer ticker.Stop()
+    for {
+        final, err := c.BlockFinality(ctx, blockNumber, requiredConfirmations)
+        if err == nil && final { return nil }
+        if err != nil && ctx.Err() == nil {
+            logger.FromContext(ctx).Debug("finality check failed, retrying", "block", blockNumber, "error", err)
+        }
+        select {
+        case <-ctx.Done():
+            return ctx.Err()
+        case <-ticker.C:
+        }
+    }
+}
+
+// LedgerCheckpoint commits to the ledger up to SlotNumber: Hash is the hex SHA-256 of the
+// IDs of the last TransactionCount transactions at or before that slot, concatenated in
+// ledger order.
+type LedgerCheckpoint struct {
+    SlotNumber       int64     `json:"slot_number"`
+    Hash             string    `json:"hash"`
+    Timestamp        time.Time `json:"timestamp"`
+    TransactionCount int64     `json:"transaction_count"`
+}
+
+// ErrCheckpointCorrupted is returned by ValidateCheckpoint when the checkpoint or the blocks
+// it covers are internally inconsistent, as opposed to merely hashing differently.
+var ErrCheckpointCorrupted = errors.New("checkpoint corrupted")
+
+// checkpointRangeSize is the number of slots ValidateCheckpoint fetches per BlockRange call.
+const checkpointRangeSize = 100
+
+// GetLedgerCheckpoint fetches the synchronizer's latest ledger checkpoint.
+func (c *SynchronizerClient) GetLedgerCheckpoint(ctx context.Context) (*LedgerCheckpoint, error) {
+    var cp LedgerCheckpoint
+    if err := c.get(ctx, "/api/v1/ledger/checkpoint", &cp); err != nil { return nil, err }
+    return &cp, nil
+}
+
+// ValidateCheckpoint recomputes checkpoint's hash from the blocks the synchronizer serves,
+// walking back from SlotNumber until TransactionCount transaction IDs are collected. It
+// returns true if the hashes match and false, nil if they differ. A checkpoint that cannot
+// describe the ledger (a negative count, more transactions than the ledger holds, or blocks
+// returned out of the requested range) yields false, ErrCheckpointCorrupted.
+func (c *SynchronizerClient) ValidateCheckpoint(ctx context.Context, checkpoint *LedgerCheckpoint) (bool, error) {
+    if checkpoint == nil { return false, errors.New("checkpoint is nil") }
+    if checkpoint.SlotNumber < 0 || checkpoint.TransactionCount < 0 {
+        return false, fmt.Errorf("%w: negative slot or transaction count", ErrCheckpointCorrupted)
+    }
+
+    // Collect per-block ID lists newest first, then flatten them in ledger order
+    var newestFirst [][]string
+    var collected int64
+    for to := checkpoint.SlotNumber; to >= 0 && collected < checkpoint.TransactionCount; to -= checkpointRangeSize {
+        from := max(to-checkpointRangeSize+1, 0)
+        var blocks []apimodel.BlockInfo
+        if err := c.BlockRange(ctx, from, to, &blocks); err != nil { return false, err }
+        for i := len(blocks) - 1; i >= 0 && collected < checkpoint.TransactionCount; i-- {
+            b := blocks[i]
+            if b.Slot < from || b.Slot > to || (i > 0 && blocks[i-1].Slot >= b.Slot) {
+                return false, fmt.Errorf("%w: block range %d-%d returned slot %d out of order", ErrCheckpointCorrupted, from, to, b.Slot)
+            }
+            if b.Transactions == nil { continue }
+            ids := make([]string, 0, len(*b.Transactions))
+            for _, tx := range *b.Transactions { ids = append(ids, tx.ID) }
+            newestFirst = append(newestFirst, ids)
+            collected += int64(len(ids))
+        }
+    }
+    if collected < checkpoint.TransactionCount {
+        return false, fmt.Errorf("%w: ledger holds %d transactions up to slot %d, checkpoint claims %d",
+            ErrCheckpointCorrupted, collected, checkpoint.SlotNumber, checkpoint.TransactionCount)
+    }
+
+    ids := make([]string, 0, collected)
+    for i := len(newestFirst) - 1; i >= 0; i-- { ids = append(ids, newestFirst[i]...) }
+    ids = ids[int64(len(ids))-checkpoint.TransactionCount:]
+
+    h := sha256.New()
+    for _, id := range ids { h.Write([]byte(id)) }
+    want := strings.ToLower(strings.TrimPrefix(checkpoint.Hash, "0x"))
+    return hex.EncodeToString(h.Sum(nil)) == want, nil
 }
\ No newline at end of file
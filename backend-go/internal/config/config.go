@@ -1,41 +1,136 @@
 package config
 
 import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "log/slog"
+    "net"
+    "net/mail"
+    "net/url"
     "os"
+    "path/filepath"
     "strings"
+    "sync"
+    "time"
     "github.com/BurntSushi/toml"
+    "gopkg.in/yaml.v3"
 )
 
 type Config struct {
     Server struct {
-        Port int `toml:"port"`
-    } `toml:"server"`
+        Port int `toml:"port" yaml:"port"`
+        RequestTimeout string `toml:"request_timeout" yaml:"request_timeout"` // Go duration, e.g. "30s"
+        GRPCPort int `toml:"grpc_port" yaml:"grpc_port"`
+        TLSCert string `toml:"tls_cert" yaml:"tls_cert"` // serve HTTPS when both cert and key are set
+        TLSKey  string `toml:"tls_key" yaml:"tls_key"`
+        AutoTLSDomain string `toml:"auto_tls_domain" yaml:"auto_tls_domain"` // obtain and renew a Let's Encrypt certificate for this domain instead of tls_cert/tls_key
+        MaxBodyBytes int64 `toml:"max_body_bytes" yaml:"max_body_bytes"` // request body limit for routes not in RouteBodyLimitConfig; 0 = unlimited
+        RouteBodyLimitConfig map[string]int64 `toml:"route_body_limits" yaml:"route_body_limits"` // per-route body limit keyed by route pattern, e.g. "/api/v1/nfts/:contract_id"; 0 = unlimited
+        TenantHeader string `toml:"tenant_header" yaml:"tenant_header"` // optional, e.g. "X-Tenant-ID"; scopes message and transaction storage to the tenant it names
+        RecordRequestBodies bool `toml:"record_request_bodies" yaml:"record_request_bodies"` // keep request bodies (signed transactions included) with recorded requests so POSTs can be replayed
+    } `toml:"server" yaml:"server"`
     Participant struct {
-        BaseURL string `toml:"base_url"`
-    } `toml:"participant"`
+        BaseURL string `toml:"base_url" yaml:"base_url"`
+        BatchConcurrency int `toml:"batch_concurrency" yaml:"batch_concurrency"` // parallel submissions per batch request
+        V2BaseURL string `toml:"v2_base_url" yaml:"v2_base_url"` // optional; /api/v2 is proxied here, alongside /api/v1
+    } `toml:"participant" yaml:"participant"`
+    State struct {
+        SnapshotPath string `toml:"snapshot_path" yaml:"snapshot_path"` // optional; in-memory state is restored from and saved to this file every 30s
+    } `toml:"state" yaml:"state"`
     Synchronizer struct {
-        BaseURL string `toml:"base_url"`
-    } `toml:"synchronizer"`
+        BaseURL string `toml:"base_url" yaml:"base_url"`
+    } `toml:"synchronizer" yaml:"synchronizer"`
+    RateLimit struct {
+        RPM      int  `toml:"rpm" yaml:"rpm"`
+        Adaptive bool `toml:"adaptive" yaml:"adaptive"` // scale RPM per client by its recent 4xx rate
+    } `toml:"rate_limit" yaml:"rate_limit"`
     Database struct {
-        PostgresURL string `toml:"postgres_url"`
-        RedisURL    string `toml:"redis_url"`
-    } `toml:"database"`
+        PostgresURL string `toml:"postgres_url" yaml:"postgres_url"`
+        RedisURL    string `toml:"redis_url" yaml:"redis_url"`
+        PGMaxConns        int    `toml:"pg_max_conns" yaml:"pg_max_conns"`                 // 0 uses the storage default (10)
+        PGMinConns        int    `toml:"pg_min_conns" yaml:"pg_min_conns"`
+        PGMaxConnLifetime string `toml:"pg_max_conn_lifetime" yaml:"pg_max_conn_lifetime"` // duration, e.g. "1h"
+        PGMaxConnIdleTime string `toml:"pg_max_conn_idle_time" yaml:"pg_max_conn_idle_time"`
+        RedisPoolSize     int    `toml:"redis_pool_size" yaml:"redis_pool_size"`           // 0 uses the go-redis default
+        ArchiveDSN        string `toml:"archive_dsn" yaml:"archive_dsn"`                   // cold-storage Postgres for /admin/db/archive; empty disables it
+    } `toml:"database" yaml:"database"`
     TLS struct {
-        ClientCert string `toml:"client_cert"`
-        ClientKey  string `toml:"client_key"`
-        CACert     string `toml:"ca_cert"`
-    } `toml:"tls"`
+        ClientCert string `toml:"client_cert" yaml:"client_cert"`
+        ClientKey  string `toml:"client_key" yaml:"client_key"`
+        CACert     string `toml:"ca_cert" yaml:"ca_cert"`
+    } `toml:"tls" yaml:"tls"`
     OTEL struct {
-        Endpoint string `toml:"endpoint"`
-        ServiceName string `toml:"service_name"`
-    } `toml:"otel"`
+        Endpoint string `toml:"endpoint" yaml:"endpoint"`
+        ServiceName string `toml:"service_name" yaml:"service_name"`
+    } `toml:"otel" yaml:"otel"`
+    Sentry struct {
+        DSN string `toml:"dsn" yaml:"dsn"` // panics and handler errors are reported when set
+    } `toml:"sentry" yaml:"sentry"`
+    Metrics struct {
+        PushgatewayURL string `toml:"pushgateway_url" yaml:"pushgateway_url"` // optional; metrics are also pushed here for short-lived runs
+        PushInterval   string `toml:"push_interval" yaml:"push_interval"`     // duration between periodic pushes, e.g. "15s"
+    } `toml:"metrics" yaml:"metrics"`
+    Cloud struct {
+        AWSRegion string `toml:"aws_region" yaml:"aws_region"`
+        S3Bucket  string `toml:"s3_bucket" yaml:"s3_bucket"`
+        WebhookSecret string `toml:"webhook_secret" yaml:"webhook_secret"`
+        WebhookURL    string `toml:"webhook_url" yaml:"webhook_url"` // optional; receives signed transaction status events, with failed deliveries retried from Redis
+        GCPProjectID    string `toml:"gcp_project_id" yaml:"gcp_project_id"`
+        BigQueryDataset string `toml:"bigquery_dataset" yaml:"bigquery_dataset"` // optional; streams block transactions to BigQuery
+        BigQueryTable   string `toml:"bigquery_table" yaml:"bigquery_table"`
+    } `toml:"cloud" yaml:"cloud"`
+    Search struct {
+        ElasticsearchURL string `toml:"elasticsearch_url" yaml:"elasticsearch_url"` // optional; enables transaction search indexing
+    } `toml:"search" yaml:"search"`
+    ERP struct {
+        BaseURL string `toml:"base_url" yaml:"base_url"` // optional; mirrors transaction status updates into the ERP
+        APIKey  string `toml:"api_key" yaml:"api_key"`
+    } `toml:"erp" yaml:"erp"`
+    CRM struct {
+        BaseURL string `toml:"base_url" yaml:"base_url"` // optional; enables the /enterprise/crm endpoints
+        APIKey  string `toml:"api_key" yaml:"api_key"`
+    } `toml:"crm" yaml:"crm"`
+    SMTP struct {
+        Host     string   `toml:"host" yaml:"host"` // optional; enables email notifications
+        Port     int      `toml:"port" yaml:"port"`
+        User     string   `toml:"user" yaml:"user"`
+        Pass     string   `toml:"pass" yaml:"pass"`
+        From     string   `toml:"from" yaml:"from"`
+        NotifyTo []string `toml:"notify_to" yaml:"notify_to"` // recipients of failed-transaction alerts
+    } `toml:"smtp" yaml:"smtp"`
+    Slack struct {
+        WebhookURL string `toml:"webhook_url" yaml:"webhook_url"` // optional; incoming webhook for operational alerts
+    } `toml:"slack" yaml:"slack"`
+    NATS struct {
+        URL string `toml:"url" yaml:"url"` // optional; enables internal eventing over NATS
+    } `toml:"nats" yaml:"nats"`
+    Security struct {
+        IPAllowList []string `toml:"ip_allowlist" yaml:"ip_allowlist"` // CIDRs or IPs allowed on /enterprise; empty allows all
+        IPBlockList []string `toml:"ip_blocklist" yaml:"ip_blocklist"` // CIDRs or IPs refused on /enterprise
+        TrustedProxies []string `toml:"trusted_proxies" yaml:"trusted_proxies"` // CIDRs or IPs whose X-Forwarded-For is believed; empty trusts none
+        AdminToken  string   `toml:"admin_token" yaml:"admin_token"`   // bearer token for /admin; empty restricts /admin to localhost
+        JWTSecret   string   `toml:"jwt_secret" yaml:"jwt_secret"`     // HS256 secret shared with the gateway; role-protected routes refuse all requests without it
+        EnterpriseClientCA string `toml:"enterprise_client_ca" yaml:"enterprise_client_ca"` // CA bundle; when set, /enterprise requires a client certificate it signed
+        MessageKEKSecret string `toml:"message_kek_secret" yaml:"message_kek_secret"` // GCP Secret Manager secret in cloud.gcp_project_id holding the base64 AES-256 key that wraps per-tenant message keys; enables message content encryption
+        MessageKEKVaultPath string `toml:"message_kek_vault_path" yaml:"message_kek_vault_path"` // alternatively, a Vault KV v2 path (VAULT_* settings) whose "kek" field holds that key
+    } `toml:"security" yaml:"security"`
 }
 
 func Default() Config {
     var c Config
     c.Server.Port = 8081
+    c.Server.RequestTimeout = "30s"
+    c.Server.GRPCPort = 8082
+    c.Server.MaxBodyBytes = 10 << 20
+    c.Server.RouteBodyLimitConfig = map[string]int64{
+        "/api/v1/transactions":    64 << 10,
+        "/enterprise/cloud/upload": 100 << 20,
+    }
     c.Participant.BaseURL = "http://participant:8090"
+    c.Participant.BatchConcurrency = 10
     c.Synchronizer.BaseURL = "http://synchronizer:8000"
+    c.RateLimit.RPM = 120
     c.Database.PostgresURL = "postgres://postgres:postgres@db:5432/garp?sslmode=disable"
     c.Database.RedisURL = "redis://redis:6379"
     c.TLS.ClientCert = ""
@@ -43,6 +138,11 @@ func Default() Config {
     c.TLS.CACert = ""
     c.OTEL.Endpoint = ""
     c.OTEL.ServiceName = "garp-backend"
+    c.Metrics.PushInterval = "15s"
+    c.Cloud.AWSRegion = ""
+    c.Cloud.S3Bucket = ""
+    c.Cloud.WebhookSecret = ""
+    c.Cloud.BigQueryTable = "transactions"
     return c
 }
 
@@ -51,22 +151,326 @@ func LoadFile(path string, out *Config) error {
     return err
 }
 
-func ApplyEnv(out *Config) {
+// LoadFileYAML decodes a YAML config file using the same keys as the TOML format.
+func LoadFileYAML(path string, out *Config) error {
+    b, err := os.ReadFile(path)
+    if err != nil { return err }
+    return yaml.Unmarshal(b, out)
+}
+
+// LoadFileAuto picks the TOML or YAML decoder based on the file extension.
+func LoadFileAuto(path string, out *Config) error {
+    switch strings.ToLower(filepath.Ext(path)) {
+    case ".toml":
+        return LoadFile(path, out)
+    case ".yaml", ".yml":
+        return LoadFileYAML(path, out)
+    default:
+        return fmt.Errorf("unsupported config file extension: %s", filepath.Ext(path))
+    }
+}
+
+// ApplyEnv loads the file named by CONFIG_FILE (if set) and then applies env overrides on top.
+func ApplyEnv(out *Config) error {
+    if v := os.Getenv("CONFIG_FILE"); v != "" {
+        if err := LoadFileAuto(v, out); err != nil { return fmt.Errorf("load %s: %w", v, err) }
+    }
+    applyOverrides(out)
+    return nil
+}
+
+func applyOverrides(out *Config) {
     // Simple env overrides
     if v := os.Getenv("BACKEND_PORT"); v != "" { out.Server.Port = atoiSafe(v, out.Server.Port) }
+    if v := os.Getenv("BACKEND_GRPC_PORT"); v != "" { out.Server.GRPCPort = atoiSafe(v, out.Server.GRPCPort) }
+    if v := os.Getenv("BACKEND_REQUEST_TIMEOUT"); v != "" { out.Server.RequestTimeout = v }
+    if v := os.Getenv("BACKEND_TLS_CERT"); v != "" { out.Server.TLSCert = v }
+    if v := os.Getenv("BACKEND_TLS_KEY"); v != "" { out.Server.TLSKey = v }
+    if v := os.Getenv("AUTO_TLS_DOMAIN"); v != "" { out.Server.AutoTLSDomain = v }
+    if v := os.Getenv("BACKEND_MAX_BODY_BYTES"); v != "" { out.Server.MaxBodyBytes = int64(atoiSafe(v, int(out.Server.MaxBodyBytes))) }
+    if v := os.Getenv("TENANT_HEADER"); v != "" { out.Server.TenantHeader = v }
+    if v := os.Getenv("RECORD_REQUEST_BODIES"); v != "" { out.Server.RecordRequestBodies = v == "true" }
+    if v := os.Getenv("ROUTE_BODY_LIMITS"); v != "" {
+        var m map[string]int64
+        if err := json.Unmarshal([]byte(v), &m); err != nil {
+            slog.Warn("config: ignoring invalid ROUTE_BODY_LIMITS", "error", err)
+        } else {
+            out.Server.RouteBodyLimitConfig = m
+        }
+    }
     if v := os.Getenv("PARTICIPANT_URL"); v != "" { out.Participant.BaseURL = v }
+    if v := os.Getenv("BACKEND_V2_URL"); v != "" { out.Participant.V2BaseURL = v }
+    if v := os.Getenv("BATCH_CONCURRENCY"); v != "" { out.Participant.BatchConcurrency = atoiSafe(v, out.Participant.BatchConcurrency) }
     if v := os.Getenv("SYNCHRONIZER_URL"); v != "" { out.Synchronizer.BaseURL = v }
+    if v := os.Getenv("STATE_SNAPSHOT_PATH"); v != "" { out.State.SnapshotPath = v }
     if v := os.Getenv("POSTGRES_URL"); v != "" { out.Database.PostgresURL = v }
     if v := os.Getenv("REDIS_URL"); v != "" { out.Database.RedisURL = v }
+    if v := os.Getenv("PG_MAX_CONNS"); v != "" { out.Database.PGMaxConns = atoiSafe(v, out.Database.PGMaxConns) }
+    if v := os.Getenv("PG_MIN_CONNS"); v != "" { out.Database.PGMinConns = atoiSafe(v, out.Database.PGMinConns) }
+    if v := os.Getenv("PG_MAX_CONN_LIFETIME"); v != "" { out.Database.PGMaxConnLifetime = v }
+    if v := os.Getenv("PG_MAX_CONN_IDLE_TIME"); v != "" { out.Database.PGMaxConnIdleTime = v }
+    if v := os.Getenv("ARCHIVE_DSN"); v != "" { out.Database.ArchiveDSN = v }
+    if v := os.Getenv("REDIS_POOL_SIZE"); v != "" { out.Database.RedisPoolSize = atoiSafe(v, out.Database.RedisPoolSize) }
     if v := os.Getenv("TLS_CLIENT_CERT"); v != "" { out.TLS.ClientCert = v }
     if v := os.Getenv("TLS_CLIENT_KEY"); v != "" { out.TLS.ClientKey = v }
     if v := os.Getenv("TLS_CA_CERT"); v != "" { out.TLS.CACert = v }
     if v := os.Getenv("OTEL_ENDPOINT"); v != "" { out.OTEL.Endpoint = v }
     if v := os.Getenv("OTEL_SERVICE_NAME"); v != "" { out.OTEL.ServiceName = v }
+    if v := os.Getenv("SENTRY_DSN"); v != "" { out.Sentry.DSN = v }
+    if v := os.Getenv("PUSHGATEWAY_URL"); v != "" { out.Metrics.PushgatewayURL = v }
+    if v := os.Getenv("PUSHGATEWAY_INTERVAL"); v != "" { out.Metrics.PushInterval = v }
+    if v := os.Getenv("AWS_REGION"); v != "" { out.Cloud.AWSRegion = v }
+    if v := os.Getenv("S3_BUCKET"); v != "" { out.Cloud.S3Bucket = v }
+    if v := os.Getenv("WEBHOOK_SECRET"); v != "" { out.Cloud.WebhookSecret = v }
+    if v := os.Getenv("WEBHOOK_URL"); v != "" { out.Cloud.WebhookURL = v }
+    if v := os.Getenv("GCP_PROJECT_ID"); v != "" { out.Cloud.GCPProjectID = v }
+    if v := os.Getenv("BIGQUERY_DATASET"); v != "" { out.Cloud.BigQueryDataset = v }
+    if v := os.Getenv("BIGQUERY_TABLE"); v != "" { out.Cloud.BigQueryTable = v }
+    if v := os.Getenv("ELASTICSEARCH_URL"); v != "" { out.Search.ElasticsearchURL = v }
+    if v := os.Getenv("NATS_URL"); v != "" { out.NATS.URL = v }
+    if v := os.Getenv("ERP_BASE_URL"); v != "" { out.ERP.BaseURL = v }
+    if v := os.Getenv("ERP_API_KEY"); v != "" { out.ERP.APIKey = v }
+    if v := os.Getenv("CRM_BASE_URL"); v != "" { out.CRM.BaseURL = v }
+    if v := os.Getenv("CRM_API_KEY"); v != "" { out.CRM.APIKey = v }
+    if v := os.Getenv("SMTP_HOST"); v != "" { out.SMTP.Host = v }
+    if v := os.Getenv("SMTP_PORT"); v != "" { out.SMTP.Port = atoiSafe(v, out.SMTP.Port) }
+    if v := os.Getenv("SMTP_USER"); v != "" { out.SMTP.User = v }
+    if v := os.Getenv("SMTP_PASS"); v != "" { out.SMTP.Pass = v }
+    if v := os.Getenv("SMTP_FROM"); v != "" { out.SMTP.From = v }
+    if v := os.Getenv("SMTP_NOTIFY_TO"); v != "" { out.SMTP.NotifyTo = splitList(v) }
+    if v := os.Getenv("SLACK_WEBHOOK_URL"); v != "" { out.Slack.WebhookURL = v }
+    if v := os.Getenv("IP_ALLOWLIST"); v != "" { out.Security.IPAllowList = splitList(v) }
+    if v := os.Getenv("IP_BLOCKLIST"); v != "" { out.Security.IPBlockList = splitList(v) }
+    if v := os.Getenv("TRUSTED_PROXIES"); v != "" { out.Security.TrustedProxies = splitList(v) }
+    if v := os.Getenv("ADMIN_TOKEN"); v != "" { out.Security.AdminToken = v }
+    if v := os.Getenv("JWT_SECRET"); v != "" { out.Security.JWTSecret = v }
+    if v := os.Getenv("ENTERPRISE_CLIENT_CA"); v != "" { out.Security.EnterpriseClientCA = v }
+    if v := os.Getenv("MESSAGE_KEK_SECRET"); v != "" { out.Security.MessageKEKSecret = v }
+    if v := os.Getenv("MESSAGE_KEK_VAULT_PATH"); v != "" { out.Security.MessageKEKVaultPath = v }
+    if v := os.Getenv("RATE_LIMIT_RPM"); v != "" { out.RateLimit.RPM = atoiSafe(v, out.RateLimit.RPM) }
+    if v := os.Getenv("RATE_LIMIT_ADAPTIVE"); v != "" { out.RateLimit.Adaptive = v == "true" }
+}
+
+// Watch polls the config file's modification time every interval. On change it re-parses
+// the file (with env overrides on top), validates it, and passes valid configs to onChange.
+// Invalid or unreadable files are logged and skipped so the running config stays in effect.
+// Call stop to end polling.
+func Watch(path string, onChange func(Config), interval time.Duration) (stop func(), err error) {
+    fi, err := os.Stat(path)
+    if err != nil { return nil, err }
+    last := fi.ModTime()
+    done := make(chan struct{})
+    go func() {
+        ticker := time.NewTicker(interval)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-done:
+                return
+            case <-ticker.C:
+            }
+            fi, err := os.Stat(path)
+            if err != nil || fi.ModTime().Equal(last) { continue }
+            last = fi.ModTime()
+            next := Default()
+            if err := LoadFileAuto(path, &next); err != nil {
+                slog.Warn("config reload failed", "path", path, "error", err)
+                continue
+            }
+            applyOverrides(&next)
+            if errs := Validate(next); len(errs) > 0 {
+                slog.Warn("config reload: invalid configuration", "path", path, "error", errors.Join(errs...))
+                continue
+            }
+            onChange(next)
+        }
+    }()
+    var once sync.Once
+    return func() { once.Do(func() { close(done) }) }, nil
+}
+
+// Validate checks the configuration and returns every problem found.
+func Validate(c Config) []error {
+    var errs []error
+    if c.Server.Port < 1 || c.Server.Port > 65535 {
+        errs = append(errs, fmt.Errorf("server.port %d out of range 1-65535", c.Server.Port))
+    }
+    if c.Server.GRPCPort < 1 || c.Server.GRPCPort > 65535 {
+        errs = append(errs, fmt.Errorf("server.grpc_port %d out of range 1-65535", c.Server.GRPCPort))
+    } else if c.Server.GRPCPort == c.Server.Port {
+        errs = append(errs, fmt.Errorf("server.grpc_port must differ from server.port"))
+    }
+    if d, err := time.ParseDuration(c.Server.RequestTimeout); err != nil || d <= 0 {
+        errs = append(errs, fmt.Errorf("server.request_timeout: invalid duration %q", c.Server.RequestTimeout))
+    }
+    if c.Server.MaxBodyBytes < 0 {
+        errs = append(errs, fmt.Errorf("server.max_body_bytes must not be negative"))
+    }
+    for route, limit := range c.Server.RouteBodyLimitConfig {
+        if limit < 0 {
+            errs = append(errs, fmt.Errorf("server.route_body_limits[%q] must not be negative", route))
+        }
+    }
+    if (c.Server.TLSCert == "") != (c.Server.TLSKey == "") {
+        errs = append(errs, fmt.Errorf("server.tls_cert and server.tls_key must be set together"))
+    }
+    if c.Server.AutoTLSDomain != "" && c.Server.TLSCert != "" {
+        errs = append(errs, fmt.Errorf("server.auto_tls_domain and server.tls_cert are mutually exclusive"))
+    }
+    if c.Security.EnterpriseClientCA != "" && c.Server.TLSCert == "" && c.Server.AutoTLSDomain == "" {
+        errs = append(errs, fmt.Errorf("security.enterprise_client_ca requires server.tls_cert and server.tls_key, or server.auto_tls_domain"))
+    }
+    if c.Security.MessageKEKSecret != "" && c.Security.MessageKEKVaultPath != "" {
+        errs = append(errs, fmt.Errorf("security.message_kek_secret and security.message_kek_vault_path are mutually exclusive"))
+    }
+    if c.Security.MessageKEKSecret != "" && c.Cloud.GCPProjectID == "" {
+        errs = append(errs, fmt.Errorf("security.message_kek_secret requires cloud.gcp_project_id"))
+    }
+    if err := checkURL(c.Participant.BaseURL, "http", "https"); err != nil {
+        errs = append(errs, fmt.Errorf("participant.base_url: %w", err))
+    }
+    if c.Participant.V2BaseURL != "" {
+        if err := checkURL(c.Participant.V2BaseURL, "http", "https"); err != nil {
+            errs = append(errs, fmt.Errorf("participant.v2_base_url: %w", err))
+        }
+    }
+    if c.Participant.BatchConcurrency < 1 {
+        errs = append(errs, fmt.Errorf("participant.batch_concurrency must be at least 1"))
+    }
+    if err := checkURL(c.Synchronizer.BaseURL, "http", "https"); err != nil {
+        errs = append(errs, fmt.Errorf("synchronizer.base_url: %w", err))
+    }
+    if err := checkURL(c.Database.PostgresURL, "postgres", "postgresql"); err != nil {
+        errs = append(errs, fmt.Errorf("database.postgres_url: %w", err))
+    }
+    if err := checkURL(c.Database.RedisURL, "redis", "rediss"); err != nil {
+        errs = append(errs, fmt.Errorf("database.redis_url: %w", err))
+    }
+    if c.Database.PGMaxConns < 0 || c.Database.PGMinConns < 0 || c.Database.RedisPoolSize < 0 {
+        errs = append(errs, fmt.Errorf("database: pool sizes must not be negative"))
+    } else if c.Database.PGMaxConns > 0 && c.Database.PGMinConns > c.Database.PGMaxConns {
+        errs = append(errs, fmt.Errorf("database.pg_min_conns must not exceed pg_max_conns"))
+    }
+    for key, v := range map[string]string{"pg_max_conn_lifetime": c.Database.PGMaxConnLifetime, "pg_max_conn_idle_time": c.Database.PGMaxConnIdleTime} {
+        if d, err := time.ParseDuration(v); v != "" && (err != nil || d < 0) {
+            errs = append(errs, fmt.Errorf("database.%s: invalid duration %q", key, v))
+        }
+    }
+    if c.Search.ElasticsearchURL != "" {
+        if err := checkURL(c.Search.ElasticsearchURL, "http", "https"); err != nil {
+            errs = append(errs, fmt.Errorf("search.elasticsearch_url: %w", err))
+        }
+    }
+    if c.NATS.URL != "" {
+        if err := checkURL(c.NATS.URL, "nats", "tls"); err != nil {
+            errs = append(errs, fmt.Errorf("nats.url: %w", err))
+        }
+    }
+    if c.ERP.BaseURL != "" {
+        if err := checkURL(c.ERP.BaseURL, "http", "https"); err != nil {
+            errs = append(errs, fmt.Errorf("erp.base_url: %w", err))
+        }
+    }
+    if c.CRM.BaseURL != "" {
+        if err := checkURL(c.CRM.BaseURL, "http", "https"); err != nil {
+            errs = append(errs, fmt.Errorf("crm.base_url: %w", err))
+        }
+    }
+    if c.SMTP.Host != "" {
+        if c.SMTP.Port < 0 || c.SMTP.Port > 65535 {
+            errs = append(errs, fmt.Errorf("smtp.port %d out of range 1-65535", c.SMTP.Port))
+        }
+        if _, err := mail.ParseAddress(c.SMTP.From); err != nil {
+            errs = append(errs, fmt.Errorf("smtp.from: %w", err))
+        }
+        for _, to := range c.SMTP.NotifyTo {
+            if _, err := mail.ParseAddress(to); err != nil {
+                errs = append(errs, fmt.Errorf("smtp.notify_to %q: %w", to, err))
+            }
+        }
+    }
+    if c.Cloud.BigQueryDataset != "" && (c.Cloud.GCPProjectID == "" || c.Cloud.BigQueryTable == "") {
+        errs = append(errs, errors.New("cloud.bigquery_dataset requires cloud.gcp_project_id and cloud.bigquery_table"))
+    }
+    if c.Cloud.WebhookURL != "" {
+        if err := checkURL(c.Cloud.WebhookURL, "http", "https"); err != nil {
+            errs = append(errs, fmt.Errorf("cloud.webhook_url: %w", err))
+        }
+    }
+    if c.Slack.WebhookURL != "" {
+        if err := checkURL(c.Slack.WebhookURL, "https"); err != nil {
+            errs = append(errs, fmt.Errorf("slack.webhook_url: %w", err))
+        }
+    }
+    if c.Metrics.PushgatewayURL != "" {
+        if err := checkURL(c.Metrics.PushgatewayURL, "http", "https"); err != nil {
+            errs = append(errs, fmt.Errorf("metrics.pushgateway_url: %w", err))
+        }
+        if d, err := time.ParseDuration(c.Metrics.PushInterval); err != nil || d <= 0 {
+            errs = append(errs, fmt.Errorf("metrics.push_interval: invalid duration %q", c.Metrics.PushInterval))
+        }
+    }
+    if c.OTEL.Endpoint != "" {
+        // The OTLP exporter takes host:port, but a full URL is accepted too
+        hostport := c.OTEL.Endpoint
+        if strings.Contains(hostport, "://") {
+            if u, err := url.Parse(hostport); err == nil { hostport = u.Host } else { hostport = "" }
+        }
+        if _, _, err := net.SplitHostPort(hostport); err != nil {
+            errs = append(errs, fmt.Errorf("otel.endpoint: invalid endpoint %q", c.OTEL.Endpoint))
+        }
+    }
+    for name, list := range map[string][]string{"security.ip_allowlist": c.Security.IPAllowList, "security.ip_blocklist": c.Security.IPBlockList, "security.trusted_proxies": c.Security.TrustedProxies} {
+        for _, s := range list {
+            if !validCIDR(s) {
+                errs = append(errs, fmt.Errorf("%s: invalid CIDR or IP %q", name, s))
+            }
+        }
+    }
+    tls := map[string]string{"tls.client_cert": c.TLS.ClientCert, "tls.client_key": c.TLS.ClientKey, "tls.ca_cert": c.TLS.CACert}
+    set := 0
+    for _, v := range tls { if v != "" { set++ } }
+    if set != 0 && set != len(tls) {
+        errs = append(errs, fmt.Errorf("tls: client_cert, client_key and ca_cert must be set together"))
+    }
+    if set == len(tls) {
+        for _, name := range []string{"tls.client_cert", "tls.client_key", "tls.ca_cert"} {
+            if _, err := os.Stat(tls[name]); err != nil {
+                errs = append(errs, fmt.Errorf("%s: %w", name, err))
+            }
+        }
+    }
+    return errs
+}
+
+func checkURL(raw string, schemes ...string) error {
+    u, err := url.Parse(raw)
+    if err != nil { return err }
+    for _, s := range schemes {
+        if u.Scheme == s {
+            if u.Host == "" { return fmt.Errorf("missing host in %q", raw) }
+            return nil
+        }
+    }
+    return fmt.Errorf("scheme %q not one of %s", u.Scheme, strings.Join(schemes, ", "))
 }
 
 func atoiSafe(s string, def int) int {
     n := 0
     for _, ch := range strings.TrimSpace(s) { if ch < '0' || ch > '9' { return def } ; n = n*10 + int(ch-'0') }
     return n
+}
+
+// splitList splits a comma-separated env value, dropping empty entries.
+func splitList(v string) []string {
+    var out []string
+    for _, p := range strings.Split(v, ",") {
+        if p = strings.TrimSpace(p); p != "" { out = append(out, p) }
+    }
+    return out
+}
+
+func validCIDR(s string) bool {
+    if _, _, err := net.ParseCIDR(s); err == nil { return true }
+    return net.ParseIP(s) != nil
 }
\ No newline at end of file
@@ -0,0 +1,43 @@
+package config
+
+import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "os"
+)
+
+// DefaultSecretManagerSecret names the secret LoadSecretManager reads when
+// GCP_SECRET_NAME is unset.
+const DefaultSecretManagerSecret = "garp-backend-config"
+
+// SecretSource fetches the latest version of a named secret. integration.GCPSecretManager
+// implements it; the interface keeps this package free of cloud SDK imports.
+type SecretSource interface {
+    GetSecretLatest(ctx context.Context, secretName string) (string, error)
+}
+
+// SecretManagerEnabled reports whether GCP_USE_SECRET_MANAGER=true, i.e. credentials
+// should come from GCP Secret Manager rather than the environment.
+func SecretManagerEnabled() bool { return os.Getenv("GCP_USE_SECRET_MANAGER") == "true" }
+
+// SecretManagerSecretName returns GCP_SECRET_NAME, or DefaultSecretManagerSecret.
+func SecretManagerSecretName() string {
+    if v := os.Getenv("GCP_SECRET_NAME"); v != "" { return v }
+    return DefaultSecretManagerSecret
+}
+
+// LoadSecretManager is the Secret Manager counterpart of LoadVault: it returns the default
+// config with secretName merged on top. The secret's payload is a JSON object using the same
+// "section.key" keys, e.g. {"database.postgres_url": "...", "security.jwt_secret": "..."}.
+func LoadSecretManager(ctx context.Context, src SecretSource, secretName string) (*Config, error) {
+    payload, err := src.GetSecretLatest(ctx, secretName)
+    if err != nil { return nil, fmt.Errorf("secret manager: %w", err) }
+    var data map[string]interface{}
+    if err := json.Unmarshal([]byte(payload), &data); err != nil {
+        return nil, fmt.Errorf("secret manager: %s is not a JSON object: %w", secretName, err)
+    }
+    c := Default()
+    if err := mergeSecrets(&c, data, "secret manager"); err != nil { return nil, err }
+    return &c, nil
+}
\ No newline at end of file
@@ -0,0 +1,137 @@
+package config
+
+import (
+    "context"
+    "fmt"
+    "os"
+    "reflect"
+    "strconv"
+    "strings"
+    "time"
+
+    vault "github.com/hashicorp/vault/api"
+)
+
+// VaultConfig describes how to read secrets from a HashiCorp Vault KV v2 engine.
+// It is only ever populated from the environment (see VaultConfigFromEnv) so that
+// Vault credentials never end up in a config file.
+type VaultConfig struct {
+    Address    string // VAULT_ADDR
+    AuthMethod string // VAULT_AUTH_METHOD: "token" (default), "approle" or "kubernetes"
+    Token      string // VAULT_TOKEN, for token auth
+    RoleID     string // VAULT_ROLE_ID, for AppRole auth
+    SecretID   string // VAULT_SECRET_ID, for AppRole auth
+    K8sRole    string // VAULT_K8S_ROLE, for Kubernetes auth
+    K8sJWTPath string // VAULT_K8S_JWT_PATH, service account token for Kubernetes auth
+    Mount      string // VAULT_KV_MOUNT, KV v2 mount point (default "secret")
+    Path       string // VAULT_SECRET_PATH, secret path within the mount
+}
+
+// VaultConfigFromEnv reads the Vault settings from VAULT_* environment variables.
+func VaultConfigFromEnv() VaultConfig {
+    vc := VaultConfig{
+        Address:    os.Getenv("VAULT_ADDR"),
+        AuthMethod: os.Getenv("VAULT_AUTH_METHOD"),
+        Token:      os.Getenv("VAULT_TOKEN"),
+        RoleID:     os.Getenv("VAULT_ROLE_ID"),
+        SecretID:   os.Getenv("VAULT_SECRET_ID"),
+        K8sRole:    os.Getenv("VAULT_K8S_ROLE"),
+        K8sJWTPath: os.Getenv("VAULT_K8S_JWT_PATH"),
+        Mount:      os.Getenv("VAULT_KV_MOUNT"),
+        Path:       os.Getenv("VAULT_SECRET_PATH"),
+    }
+    if vc.AuthMethod == "" { vc.AuthMethod = "token" }
+    if vc.K8sJWTPath == "" { vc.K8sJWTPath = "/var/run/secrets/kubernetes.io/serviceaccount/token" }
+    if vc.Mount == "" { vc.Mount = "secret" }
+    return vc
+}
+
+// LoadVault returns the default config with the Vault secret merged on top.
+// Secret keys use the "section.key" form of the TOML keys, e.g. "database.postgres_url"
+// or "tls.client_key"; unknown keys are ignored.
+func LoadVault(vc VaultConfig) (*Config, error) {
+    if vc.Path == "" {
+        return nil, fmt.Errorf("vault: secret path is required")
+    }
+    client, err := NewVaultClient(vc)
+    if err != nil { return nil, err }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    secret, err := client.KVv2(vc.Mount).Get(ctx, vc.Path)
+    if err != nil { return nil, fmt.Errorf("vault: read %s/%s: %w", vc.Mount, vc.Path, err) }
+
+    c := Default()
+    if err := mergeSecrets(&c, secret.Data, "vault"); err != nil { return nil, err }
+    return &c, nil
+}
+
+// NewVaultClient returns a client for vc.Address logged in with vc's auth method. vc.Path
+// is not used, so callers can read secrets of their own.
+func NewVaultClient(vc VaultConfig) (*vault.Client, error) {
+    if vc.Address == "" {
+        return nil, fmt.Errorf("vault: address is required")
+    }
+    vcfg := vault.DefaultConfig()
+    vcfg.Address = vc.Address
+    client, err := vault.NewClient(vcfg)
+    if err != nil { return nil, fmt.Errorf("vault: %w", err) }
+
+    ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+    defer cancel()
+    if err := vaultLogin(ctx, client, vc); err != nil { return nil, err }
+    return client, nil
+}
+
+func vaultLogin(ctx context.Context, client *vault.Client, vc VaultConfig) error {
+    var path string
+    var body map[string]interface{}
+    switch vc.AuthMethod {
+    case "token":
+        if vc.Token == "" { return fmt.Errorf("vault: VAULT_TOKEN is required for token auth") }
+        client.SetToken(vc.Token)
+        return nil
+    case "approle":
+        path = "auth/approle/login"
+        body = map[string]interface{}{"role_id": vc.RoleID, "secret_id": vc.SecretID}
+    case "kubernetes":
+        jwt, err := os.ReadFile(vc.K8sJWTPath)
+        if err != nil { return fmt.Errorf("vault: read service account token: %w", err) }
+        path = "auth/kubernetes/login"
+        body = map[string]interface{}{"role": vc.K8sRole, "jwt": strings.TrimSpace(string(jwt))}
+    default:
+        return fmt.Errorf("vault: unsupported auth method %q", vc.AuthMethod)
+    }
+    secret, err := client.Logical().WriteWithContext(ctx, path, body)
+    if err != nil { return fmt.Errorf("vault: %s login: %w", vc.AuthMethod, err) }
+    if secret == nil || secret.Auth == nil { return fmt.Errorf("vault: %s login returned no token", vc.AuthMethod) }
+    client.SetToken(secret.Auth.ClientToken)
+    return nil
+}
+
+// mergeSecrets copies "section.key" entries into the matching Config fields by TOML tag.
+// source prefixes error messages.
+func mergeSecrets(out *Config, data map[string]interface{}, source string) error {
+    v := reflect.ValueOf(out).Elem()
+    t := v.Type()
+    for i := 0; i < t.NumField(); i++ {
+        section := t.Field(i).Tag.Get("toml")
+        sv := v.Field(i)
+        st := sv.Type()
+        for j := 0; j < st.NumField(); j++ {
+            key := section + "." + st.Field(j).Tag.Get("toml")
+            raw, ok := data[key]
+            if !ok { continue }
+            f := sv.Field(j)
+            switch f.Kind() {
+            case reflect.String:
+                f.SetString(fmt.Sprint(raw))
+            case reflect.Int:
+                n, err := strconv.Atoi(fmt.Sprint(raw))
+                if err != nil { return fmt.Errorf("%s: %s: %w", source, key, err) }
+                f.SetInt(int64(n))
+            }
+        }
+    }
+    return nil
+}
\ No newline at end of file
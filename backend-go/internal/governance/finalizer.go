@@ -0,0 +1,68 @@
+package governance
+
+import (
+    "context"
+    "errors"
+    "log/slog"
+    "time"
+
+    apimodel "garp-backend/internal/api"
+    "garp-backend/internal/client"
+    "garp-backend/internal/storage"
+)
+
+// batchSize caps the proposals finalized per tick.
+const batchSize = 100
+
+// Finalizer closes governance proposals once the chain reaches their voting_end_slot.
+type Finalizer struct {
+    Participant client.ParticipantClientInterface
+}
+
+// Start polls syncClient for the latest slot every pollInterval and finalizes every open
+// proposal whose voting has ended: a passed proposal has its on-chain action exercised and
+// becomes executed, anything else becomes rejected. A proposal whose action fails stays open
+// and is retried on the next tick. Start blocks until ctx is cancelled and then returns nil.
+func (f *Finalizer) Start(ctx context.Context, s *storage.Storage, syncClient *client.SynchronizerClient, pollInterval time.Duration) error {
+    if pollInterval <= 0 { return errors.New("governance finalizer: poll interval must be positive") }
+    if f.Participant == nil { return errors.New("governance finalizer: participant client is required") }
+
+    ticker := time.NewTicker(pollInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case <-ticker.C:
+        }
+        var block apimodel.BlockInfo
+        if err := syncClient.LatestBlock(ctx, &block); err != nil {
+            continue // the client already logged it
+        }
+        due, err := s.DueProposals(ctx, block.Slot, batchSize)
+        if err != nil {
+            slog.Warn("governance finalizer: failed to list proposals", "slot", block.Slot, "error", err)
+            continue
+        }
+        for _, p := range due { f.finalize(ctx, s, p) }
+    }
+}
+
+func (f *Finalizer) finalize(ctx context.Context, s *storage.Storage, p storage.Proposal) {
+    status := storage.ProposalRejected
+    if p.Tally.Passed() {
+        status = storage.ProposalExecuted
+        if p.ContractID != nil && p.Choice != nil {
+            in := map[string]interface{}{"choice": *p.Choice, "arguments": p.Arguments}
+            if err := f.Participant.ExerciseContract(ctx, *p.ContractID, in, nil); err != nil {
+                slog.Warn("governance finalizer: failed to execute proposal", "proposal_id", p.ID, "contract_id", *p.ContractID, "error", err)
+                return
+            }
+        }
+    }
+    if _, err := s.FinalizeProposal(ctx, p.ID, status); err != nil {
+        slog.Error("governance finalizer: failed to update proposal", "proposal_id", p.ID, "status", status, "error", err)
+        return
+    }
+    slog.Info("proposal finalized", "proposal_id", p.ID, "status", status, "yes", p.Tally.Yes, "no", p.Tally.No, "abstain", p.Tally.Abstain)
+}
\ No newline at end of file
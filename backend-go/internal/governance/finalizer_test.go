@@ -0,0 +1,96 @@
+package governance
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "testing"
+    "time"
+
+    "garp-backend/internal/client"
+    "garp-backend/internal/storage"
+    "garp-backend/internal/storage/storagetest"
+)
+
+func TestFinalize(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+    defer cancel()
+    env, err := storagetest.Start(ctx)
+    if errors.Is(err, storagetest.ErrNoDocker) {
+        t.Skip(err)
+    }
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer env.Close()
+    s := env.Storage
+
+    contractID, choice := "contract-1", "Release"
+    create := func(t *testing.T, votes ...string) storage.Proposal {
+        t.Helper()
+        p, err := s.CreateProposal(ctx, storage.Proposal{Proposer: "alice", Description: t.Name(), VotingEndSlot: 10,
+            ContractID: &contractID, Choice: &choice, Arguments: json.RawMessage(`{"amount":5}`)})
+        if err != nil {
+            t.Fatal(err)
+        }
+        for i, v := range votes {
+            if err := s.CastVote(ctx, p.ID, "voter-"+string(rune('a'+i)), v); err != nil {
+                t.Fatal(err)
+            }
+        }
+        if p, err = s.GetProposal(ctx, p.ID); err != nil {
+            t.Fatal(err)
+        }
+        return p
+    }
+    statusOf := func(t *testing.T, id string) string {
+        t.Helper()
+        p, err := s.GetProposal(ctx, id)
+        if err != nil {
+            t.Fatal(err)
+        }
+        return p.Status
+    }
+
+    t.Run("passed proposal exercises its choice", func(t *testing.T) {
+        mock := client.NewMock()
+        p := create(t, "yes", "yes", "no")
+        (&Finalizer{Participant: mock}).finalize(ctx, s, p)
+
+        calls := mock.RecordedCalls()
+        if len(calls) != 1 || calls[0].Method != "ExerciseContract" || calls[0].Args[0] != contractID {
+            t.Fatalf("calls = %+v, want one ExerciseContract on %s", calls, contractID)
+        }
+        in, _ := calls[0].Args[1].(map[string]interface{})
+        if in["choice"] != choice {
+            t.Errorf("exercised choice %v, want %s", in["choice"], choice)
+        }
+        if st := statusOf(t, p.ID); st != storage.ProposalExecuted {
+            t.Errorf("status = %s, want %s", st, storage.ProposalExecuted)
+        }
+    })
+
+    t.Run("failed exercise leaves the proposal open", func(t *testing.T) {
+        mock := client.NewMock()
+        mock.SetError("ExerciseContract", errors.New("participant down"))
+        p := create(t, "yes")
+        (&Finalizer{Participant: mock}).finalize(ctx, s, p)
+
+        if st := statusOf(t, p.ID); st != storage.ProposalOpen {
+            t.Errorf("status = %s, want %s", st, storage.ProposalOpen)
+        }
+    })
+
+    t.Run("rejected proposal is not exercised", func(t *testing.T) {
+        mock := client.NewMock()
+        p := create(t, "no", "abstain")
+        (&Finalizer{Participant: mock}).finalize(ctx, s, p)
+
+        if calls := mock.RecordedCalls(); len(calls) != 0 {
+            t.Fatalf("calls = %+v, want none", calls)
+        }
+        if st := statusOf(t, p.ID); st != storage.ProposalRejected {
+            t.Errorf("status = %s, want %s", st, storage.ProposalRejected)
+        }
+    })
+}
\ No newline at end of file
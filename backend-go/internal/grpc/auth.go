@@ -0,0 +1,36 @@
+package grpc
+
+import (
+    "context"
+
+    gogrpc "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    "garp-backend/internal/middleware"
+)
+
+type callerKey struct{}
+
+// AuthInterceptor requires every call to carry "authorization: Bearer <token>" metadata with
+// an HS256 token signed with secret, the tokens middleware.JWTAuth accepts over REST. With no
+// secret configured every call is refused. The token's subject is available to handlers
+// through callerFromContext.
+func AuthInterceptor(secret string) gogrpc.UnaryServerInterceptor {
+    return func(ctx context.Context, req any, _ *gogrpc.UnaryServerInfo, handler gogrpc.UnaryHandler) (any, error) {
+        var authorization string
+        if md, ok := metadata.FromIncomingContext(ctx); ok {
+            if v := md.Get("authorization"); len(v) > 0 { authorization = v[0] }
+        }
+        sub, _, err := middleware.ParseBearerToken(secret, authorization)
+        if err != nil { return nil, status.Error(codes.Unauthenticated, err.Error()) }
+        return handler(context.WithValue(ctx, callerKey{}, sub), req)
+    }
+}
+
+// callerFromContext returns the subject AuthInterceptor verified, or "".
+func callerFromContext(ctx context.Context) string {
+    sub, _ := ctx.Value(callerKey{}).(string)
+    return sub
+}
\ No newline at end of file
@@ -0,0 +1,143 @@
+// Package grpc serves the Garp gRPC API (proto/garp.proto) for internal callers.
+// It mirrors the REST handlers and delegates to the same participant client and
+// submission path (txsubmit).
+package grpc
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "net"
+
+    gogrpc "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/status"
+
+    "garp-backend/internal/api"
+    "garp-backend/internal/client"
+    "garp-backend/internal/grpc/garppb"
+    "garp-backend/internal/middleware"
+    "garp-backend/internal/txsubmit"
+)
+
+// GRPCServer implements garppb.GarpServer.
+type GRPCServer struct {
+    garppb.UnimplementedGarpServer
+    participant client.ParticipantClientInterface
+    submitter   *txsubmit.Submitter
+    srv         *gogrpc.Server
+}
+
+// NewGRPCServer builds a server with the Garp service registered. Every call is
+// authenticated by AuthInterceptor with jwtSecret before any interceptors in opts run.
+func NewGRPCServer(participant client.ParticipantClientInterface, submitter *txsubmit.Submitter, jwtSecret string, opts ...gogrpc.ServerOption) *GRPCServer {
+    opts = append([]gogrpc.ServerOption{gogrpc.ChainUnaryInterceptor(AuthInterceptor(jwtSecret))}, opts...)
+    s := &GRPCServer{participant: participant, submitter: submitter, srv: gogrpc.NewServer(opts...)}
+    garppb.RegisterGarpServer(s.srv, s)
+    return s
+}
+
+// Serve listens on addr and blocks until Stop is called or the listener fails.
+func (s *GRPCServer) Serve(addr string) error {
+    lis, err := net.Listen("tcp", addr)
+    if err != nil { return fmt.Errorf("failed to listen on %s: %w", addr, err) }
+    return s.srv.Serve(lis)
+}
+
+// Stop waits for in-flight RPCs to finish, or cancels them once ctx is done.
+func (s *GRPCServer) Stop(ctx context.Context) {
+    done := make(chan struct{})
+    go func() { s.srv.GracefulStop(); close(done) }()
+    select {
+    case <-done:
+    case <-ctx.Done():
+        s.srv.Stop()
+    }
+}
+
+func (s *GRPCServer) GetTransaction(ctx context.Context, req *garppb.GetTransactionRequest) (*garppb.Transaction, error) {
+    if req.GetId() == "" { return nil, status.Error(codes.InvalidArgument, "id is required") }
+    var tx api.TransactionInfo
+    if err := s.participant.GetTransaction(ctx, req.GetId(), &tx); err != nil { return nil, upstreamError(err) }
+    return &garppb.Transaction{
+        Id:        tx.ID,
+        Submitter: deref(tx.Submitter),
+        Status:    deref(tx.Status),
+        CreatedAt: deref(tx.CreatedAt),
+        Error:     deref(tx.Error),
+    }, nil
+}
+
+// SubmitTransaction goes through the same path as POST /api/v1/transactions: the payload is
+// validated against its schema, retries from the same caller are deduplicated, and the
+// accepted transaction is saved and pooled.
+func (s *GRPCServer) SubmitTransaction(ctx context.Context, req *garppb.SubmitTransactionRequest) (*garppb.SubmitTransactionResponse, error) {
+    tx, err := s.submitter.SubmitOnce(ctx, callerFromContext(ctx), req.GetPayload())
+    var ve *txsubmit.ValidationError
+    switch {
+    case errors.Is(err, middleware.ErrInvalidJSON):
+        return nil, status.Error(codes.InvalidArgument, "payload must be a JSON transaction body")
+    case errors.As(err, &ve):
+        return nil, status.Error(codes.InvalidArgument, ve.Error())
+    case errors.Is(err, txsubmit.ErrInFlight):
+        return nil, status.Error(codes.Aborted, err.Error())
+    case err != nil:
+        return nil, upstreamError(err)
+    }
+    st := deref(tx.Status)
+    if st == "" { st = "pending" }
+    return &garppb.SubmitTransactionResponse{Id: tx.ID, Status: st}, nil
+}
+
+func (s *GRPCServer) GetBlock(ctx context.Context, req *garppb.GetBlockRequest) (*garppb.Block, error) {
+    var b api.BlockInfo
+    var err error
+    switch sel := req.GetSelector().(type) {
+    case *garppb.GetBlockRequest_Number:
+        err = s.participant.BlockByNumber(ctx, sel.Number, &b)
+    case *garppb.GetBlockRequest_Hash:
+        err = s.participant.BlockByHash(ctx, sel.Hash, &b)
+    default:
+        err = s.participant.LatestBlock(ctx, &b)
+    }
+    if err != nil { return nil, upstreamError(err) }
+    out := &garppb.Block{
+        Slot:        b.Slot,
+        Hash:        b.Hash,
+        ParentHash:  deref(b.ParentHash),
+        TimestampMs: deref(b.TimestampMs),
+        Leader:      deref(b.Leader),
+    }
+    if b.Transactions != nil {
+        for _, t := range *b.Transactions {
+            out.Transactions = append(out.Transactions, &garppb.BlockTx{Id: t.ID, Submitter: deref(t.Submitter), CommandType: deref(t.CommandType)})
+        }
+    }
+    return out, nil
+}
+
+func (s *GRPCServer) GetBalance(ctx context.Context, req *garppb.GetBalanceRequest) (*garppb.Balance, error) {
+    if req.GetAddress() == "" { return nil, status.Error(codes.InvalidArgument, "address is required") }
+    var acct api.Account
+    if err := s.participant.AccountBalance(ctx, req.GetAddress(), &acct); err != nil { return nil, upstreamError(err) }
+    if acct.Address == "" { acct.Address = req.GetAddress() }
+    return &garppb.Balance{Address: acct.Address, Balance: acct.Balance, Nonce: acct.Nonce}, nil
+}
+
+// upstreamError maps participant client failures onto gRPC status codes.
+func upstreamError(err error) error {
+    switch {
+    case errors.Is(err, context.DeadlineExceeded):
+        return status.Error(codes.DeadlineExceeded, err.Error())
+    case errors.Is(err, context.Canceled):
+        return status.Error(codes.Canceled, err.Error())
+    default:
+        return status.Error(codes.Unavailable, err.Error())
+    }
+}
+
+func deref[T any](p *T) T {
+    var zero T
+    if p == nil { return zero }
+    return *p
+}
\ No newline at end of file
@@ -0,0 +1,199 @@
+package grpc
+
+import (
+    "context"
+    "errors"
+    "reflect"
+    "testing"
+    "time"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/redis/go-redis/v9"
+    gogrpc "google.golang.org/grpc"
+    "google.golang.org/grpc/codes"
+    "google.golang.org/grpc/metadata"
+    "google.golang.org/grpc/status"
+
+    "garp-backend/internal/api"
+    "garp-backend/internal/client"
+    "garp-backend/internal/grpc/garppb"
+    "garp-backend/internal/state"
+    "garp-backend/internal/storage"
+    "garp-backend/internal/storage/storagetest"
+    "garp-backend/internal/txsubmit"
+)
+
+const validPayload = `{"submitter":"alice","commands":[{"type":"transfer","args":{"amount":5}}]}`
+
+func TestGetTransactionDelegates(t *testing.T) {
+    mock := client.NewMock()
+    st := "confirmed"
+    mock.SetResponse("GetTransaction", api.TransactionInfo{ID: "tx-1", Status: &st})
+    s := NewGRPCServer(mock, nil, "")
+
+    got, err := s.GetTransaction(context.Background(), &garppb.GetTransactionRequest{Id: "tx-1"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.GetId() != "tx-1" || got.GetStatus() != "confirmed" {
+        t.Fatalf("GetTransaction = %v", got)
+    }
+    want := []client.RecordedCall{{Method: "GetTransaction", Args: []any{"tx-1"}}}
+    if calls := mock.RecordedCalls(); !reflect.DeepEqual(calls, want) {
+        t.Fatalf("calls = %+v, want %+v", calls, want)
+    }
+}
+
+func TestGetBlockSelector(t *testing.T) {
+    tests := []struct {
+        name string
+        req  *garppb.GetBlockRequest
+        want client.RecordedCall
+    }{
+        {name: "number", req: &garppb.GetBlockRequest{Selector: &garppb.GetBlockRequest_Number{Number: 7}}, want: client.RecordedCall{Method: "BlockByNumber", Args: []any{uint64(7)}}},
+        {name: "hash", req: &garppb.GetBlockRequest{Selector: &garppb.GetBlockRequest_Hash{Hash: "0xab"}}, want: client.RecordedCall{Method: "BlockByHash", Args: []any{"0xab"}}},
+        {name: "latest", req: &garppb.GetBlockRequest{}, want: client.RecordedCall{Method: "LatestBlock"}},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            mock := client.NewMock()
+            mock.SetResponse(tt.want.Method, api.BlockInfo{Slot: 7, Hash: "0xab"})
+            got, err := NewGRPCServer(mock, nil, "").GetBlock(context.Background(), tt.req)
+            if err != nil {
+                t.Fatal(err)
+            }
+            if got.GetSlot() != 7 || got.GetHash() != "0xab" {
+                t.Fatalf("GetBlock = %v", got)
+            }
+            if calls := mock.RecordedCalls(); len(calls) != 1 || !reflect.DeepEqual(calls[0], tt.want) {
+                t.Fatalf("calls = %+v, want %+v", calls, tt.want)
+            }
+        })
+    }
+}
+
+func TestSubmitTransactionUpstreamError(t *testing.T) {
+    mock := client.NewMock()
+    mock.SetError("SubmitTransaction", errors.New("participant down"))
+    // A nil store panics if SaveTx is reached, so this also checks nothing is stored
+    s := NewGRPCServer(mock, txsubmit.New(mock, nil, state.NewTxPool()), "")
+
+    _, err := s.SubmitTransaction(context.Background(), &garppb.SubmitTransactionRequest{Payload: []byte(validPayload)})
+    if status.Code(err) != codes.Unavailable {
+        t.Fatalf("SubmitTransaction error = %v, want Unavailable", err)
+    }
+    // Both are refused before reaching the participant
+    if _, err := s.SubmitTransaction(context.Background(), &garppb.SubmitTransactionRequest{Payload: []byte(`not json`)}); status.Code(err) != codes.InvalidArgument {
+        t.Fatalf("invalid payload error = %v, want InvalidArgument", err)
+    }
+    if _, err := s.SubmitTransaction(context.Background(), &garppb.SubmitTransactionRequest{Payload: []byte(`{"kind":"transfer"}`)}); status.Code(err) != codes.InvalidArgument {
+        t.Fatalf("payload failing the schema: error = %v, want InvalidArgument", err)
+    }
+    if n := len(mock.RecordedCalls()); n != 1 {
+        t.Fatalf("participant called %d times, want 1", n)
+    }
+}
+
+func TestSubmitTransactionSavesTx(t *testing.T) {
+    ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+    defer cancel()
+    env, err := storagetest.Start(ctx)
+    if errors.Is(err, storagetest.ErrNoDocker) {
+        t.Skip(err)
+    }
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer env.Close()
+
+    mock := client.NewMock()
+    mock.SetResponse("SubmitTransaction", api.TransactionInfo{ID: "tx-grpc-1"})
+    s := NewGRPCServer(mock, txsubmit.New(mock, env.Storage, state.NewTxPool()), "")
+
+    payload := []byte(validPayload)
+    got, err := s.SubmitTransaction(ctx, &garppb.SubmitTransactionRequest{Payload: payload})
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got.GetId() != "tx-grpc-1" || got.GetStatus() != "pending" {
+        t.Fatalf("SubmitTransaction = %v", got)
+    }
+    var stored []byte
+    if err := env.Storage.PG.QueryRow(ctx, `SELECT payload FROM transactions WHERE tx_hash = $1`, "tx-grpc-1").Scan(&stored); err != nil {
+        t.Fatalf("transaction not saved: %v", err)
+    }
+    if string(stored) != string(payload) {
+        t.Fatalf("saved payload %s, want %s", stored, payload)
+    }
+}
+
+func TestSubmitTransactionDeduplicates(t *testing.T) {
+    rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+    defer rdb.Close()
+    mock := client.NewMock()
+    accepted := "accepted"
+    // No ID, so nothing is saved and the Redis-only store is enough
+    mock.SetResponse("SubmitTransaction", api.TransactionInfo{Status: &accepted})
+    s := NewGRPCServer(mock, txsubmit.New(mock, &storage.Storage{Redis: rdb}, state.NewTxPool()), "")
+
+    submit := func(caller string) {
+        t.Helper()
+        ctx := context.WithValue(context.Background(), callerKey{}, caller)
+        got, err := s.SubmitTransaction(ctx, &garppb.SubmitTransactionRequest{Payload: []byte(validPayload)})
+        if err != nil {
+            t.Fatal(err)
+        }
+        if got.GetStatus() != "accepted" {
+            t.Fatalf("SubmitTransaction = %v, want status accepted", got)
+        }
+    }
+    submit("alice")
+    submit("alice") // a retry gets the first result back
+    submit("bob")   // another caller with the same body is submitted
+    if n := len(mock.RecordedCalls()); n != 2 {
+        t.Fatalf("participant called %d times, want 2", n)
+    }
+}
+
+func TestAuthInterceptor(t *testing.T) {
+    const secret = "test-secret"
+    sign := func(key string) string {
+        tok, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "alice"}).SignedString([]byte(key))
+        if err != nil {
+            t.Fatal(err)
+        }
+        return "Bearer " + tok
+    }
+    tests := []struct {
+        name          string
+        secret        string
+        authorization string
+        wantCode      codes.Code
+    }{
+        {name: "valid token", secret: secret, authorization: sign(secret), wantCode: codes.OK},
+        {name: "no token", secret: secret, wantCode: codes.Unauthenticated},
+        {name: "wrong key", secret: secret, authorization: sign("other"), wantCode: codes.Unauthenticated},
+        {name: "not configured", authorization: sign(""), wantCode: codes.Unauthenticated},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            ctx := context.Background()
+            if tt.authorization != "" {
+                ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+            }
+            var caller string
+            handler := func(ctx context.Context, _ any) (any, error) {
+                caller = callerFromContext(ctx)
+                return "ok", nil
+            }
+            _, err := AuthInterceptor(tt.secret)(ctx, nil, &gogrpc.UnaryServerInfo{FullMethod: "/garp.Garp/SubmitTransaction"}, handler)
+            if status.Code(err) != tt.wantCode {
+                t.Fatalf("error = %v, want %v", err, tt.wantCode)
+            }
+            if tt.wantCode == codes.OK && caller != "alice" {
+                t.Fatalf("handler saw caller %q, want alice", caller)
+            }
+        })
+    }
+}
\ No newline at end of file
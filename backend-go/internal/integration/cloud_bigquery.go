@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/googleapi"
+)
+
+// bigQueryInsertChunk keeps each streaming insert under the recommended 500 rows.
+const bigQueryInsertChunk = 500
+
+// BigQueryStreamer streams transactions into BigQuery for analytics.
+type BigQueryStreamer struct {
+	client *bigquery.Client
+}
+
+// bigQueryTransaction is the BigQuery row for a TransactionRecord. It exists because
+// BigQuery has no unsigned integers and confirmed_at must be nullable.
+type bigQueryTransaction struct {
+	ID          string                 `bigquery:"id"`
+	Submitter   string                 `bigquery:"submitter"`
+	Status      string                 `bigquery:"status"`
+	CreatedAt   time.Time              `bigquery:"created_at"`
+	ConfirmedAt bigquery.NullTimestamp `bigquery:"confirmed_at"`
+	BlockNumber int64                  `bigquery:"block_number"`
+	BlockHash   string                 `bigquery:"block_hash"`
+	Data        string                 `bigquery:"data"`
+}
+
+var bigQueryTransactionSchema = func() bigquery.Schema {
+	schema, err := bigquery.InferSchema(bigQueryTransaction{})
+	if err != nil {
+		panic(fmt.Sprintf("bigquery: cannot infer transaction schema: %v", err))
+	}
+	return schema
+}()
+
+// NewBigQueryStreamer connects to BigQuery in the configured GCP project using
+// application default credentials.
+func (ci *CloudIntegration) NewBigQueryStreamer(ctx context.Context) (*BigQueryStreamer, error) {
+	if ci.gcpProject == "" {
+		return nil, errors.New("GCP project ID not configured")
+	}
+	client, err := bigquery.NewClient(ctx, ci.gcpProject)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	return &BigQueryStreamer{client: client}, nil
+}
+
+var _ bigquery.ValueSaver = TransactionRecord{}
+
+// Save implements bigquery.ValueSaver. The transaction ID is the insert ID, so rows
+// resent within BigQuery's deduplication window are not stored twice.
+func (r TransactionRecord) Save() (map[string]bigquery.Value, string, error) {
+	row := bigQueryTransaction{
+		ID:          r.ID,
+		Submitter:   r.Submitter,
+		Status:      r.Status,
+		CreatedAt:   r.CreatedAt,
+		ConfirmedAt: bigquery.NullTimestamp{Timestamp: r.ConfirmedAt, Valid: !r.ConfirmedAt.IsZero()},
+		BlockNumber: int64(r.BlockNumber),
+		BlockHash:   r.BlockHash,
+		Data:        r.Data,
+	}
+	return (&bigquery.StructSaver{Schema: bigQueryTransactionSchema, InsertID: r.ID, Struct: row}).Save()
+}
+
+// EnsureTable creates datasetID.tableID with the transaction schema, partitioned by day on
+// created_at, unless it already exists. The dataset itself must exist.
+func (b *BigQueryStreamer) EnsureTable(ctx context.Context, datasetID, tableID string) error {
+	table := b.client.Dataset(datasetID).Table(tableID)
+	_, err := table.Metadata(ctx)
+	if err == nil {
+		return nil
+	}
+	if !isGoogleAPIStatus(err, http.StatusNotFound) {
+		return fmt.Errorf("failed to look up BigQuery table %s.%s: %w", datasetID, tableID, err)
+	}
+	err = table.Create(ctx, &bigquery.TableMetadata{
+		Schema:           bigQueryTransactionSchema,
+		TimePartitioning: &bigquery.TimePartitioning{Field: "created_at"},
+	})
+	if err != nil && !isGoogleAPIStatus(err, http.StatusConflict) { // another replica created it first
+		return fmt.Errorf("failed to create BigQuery table %s.%s: %w", datasetID, tableID, err)
+	}
+	return nil
+}
+
+// StreamTransactions appends txs to datasetID.tableID with the streaming insert API.
+func (b *BigQueryStreamer) StreamTransactions(ctx context.Context, datasetID, tableID string, txs []TransactionRecord) error {
+	inserter := b.client.Dataset(datasetID).Table(tableID).Inserter()
+	for start := 0; start < len(txs); start += bigQueryInsertChunk {
+		if err := inserter.Put(ctx, txs[start:min(start+bigQueryInsertChunk, len(txs))]); err != nil {
+			var rowErrs bigquery.PutMultiError
+			if errors.As(err, &rowErrs) {
+				return fmt.Errorf("BigQuery rejected %d of %d rows: %w", len(rowErrs), len(txs), err)
+			}
+			return fmt.Errorf("failed to stream transactions to BigQuery: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close releases the BigQuery client.
+func (b *BigQueryStreamer) Close() error {
+	return b.client.Close()
+}
+
+func isGoogleAPIStatus(err error, code int) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == code
+}
\ No newline at end of file
@@ -3,9 +3,21 @@ package integration
 import (
     "context"
     "bytes"
+    "crypto/rand"
+    "crypto/hmac"
+    "crypto/sha256"
+    "crypto/tls"
+    "encoding/hex"
     "encoding/json"
+    "errors"
     "fmt"
+    "hash/crc32"
+    "io"
+    "log/slog"
+    "math"
     "net/http"
+    "os"
+    "strings"
     "time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -13,21 +25,41 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"cloud.google.com/go/pubsub"
+	secretmanager "cloud.google.com/go/secretmanager/apiv1"
+	"cloud.google.com/go/secretmanager/apiv1/secretmanagerpb"
 	"cloud.google.com/go/storage"
+	"github.com/Azure/azure-sdk-for-go/sdk/messaging/azservicebus"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/redis/go-redis/v9"
+
+	"garp-backend/internal/otel"
 )
 
 // CloudIntegration provides integration with cloud services
 type CloudIntegration struct {
-	awsSession  *session.Session
-	gcpProject  string
-	httpClient  *http.Client
+	awsSession   *session.Session
+	gcpProject   string
+	azureAccount string
+	azureKey     string
+	azureSBConn  string
+	kafkaBrokers []string
+	kafkaTLS     *tls.Config
+	httpClient   *http.Client
 }
 
 // CloudConfig holds cloud integration configuration
 type CloudConfig struct {
-	AWSRegion    string
-	GCPProjectID string
-	HTTPTimeout  time.Duration
+	AWSRegion           string
+	GCPProjectID        string
+	AzureStorageAccount string
+	AzureStorageKey     string
+	// AzureServiceBusConnectionString is the namespace connection string from the Azure portal
+	AzureServiceBusConnectionString string
+	// KafkaBootstrapServers are the host:port seed brokers for KafkaCloudBridge
+	KafkaBootstrapServers []string
+	// KafkaTLSConfig enables TLS to the brokers when set
+	KafkaTLSConfig *tls.Config
+	HTTPTimeout    time.Duration
 }
 
 // NewCloudIntegration creates a new cloud integration instance
@@ -46,8 +78,8 @@ func NewCloudIntegration(config CloudConfig) (*CloudIntegration, error) {
 	}
 	
 	// Validate GCP project ID if provided
-	if config.GCPProjectID == "" && config.AWSRegion == "" {
-		return nil, fmt.Errorf("either AWS region or GCP project ID must be provided")
+	if config.GCPProjectID == "" && config.AWSRegion == "" && config.AzureStorageAccount == "" && config.AzureServiceBusConnectionString == "" && len(config.KafkaBootstrapServers) == 0 {
+		return nil, fmt.Errorf("either AWS region, GCP project ID, an Azure account or Kafka brokers must be provided")
 	}
 	
 	httpClient := &http.Client{
@@ -55,9 +87,14 @@ func NewCloudIntegration(config CloudConfig) (*CloudIntegration, error) {
 	}
 	
 	return &CloudIntegration{
-		awsSession: awsSession,
-		gcpProject: config.GCPProjectID,
-		httpClient: httpClient,
+		awsSession:   awsSession,
+		gcpProject:   config.GCPProjectID,
+		azureAccount: config.AzureStorageAccount,
+		azureKey:     config.AzureStorageKey,
+		azureSBConn:  config.AzureServiceBusConnectionString,
+		kafkaBrokers: config.KafkaBootstrapServers,
+		kafkaTLS:     config.KafkaTLSConfig,
+		httpClient:   otel.HTTPInjectMiddleware(httpClient),
 	}, nil
 }
 
@@ -109,13 +146,124 @@ func (s3s *S3Storage) DownloadFromS3(ctx context.Context, bucket, key string) ([
 	return buf, nil
 }
 
+// defaultPartSize is the multi-part chunk size used when none is given (S3 requires at least 5 MB)
+const defaultPartSize = 8 * 1024 * 1024
+
+// UploadLarge streams data to S3 using the multi-part upload API.
+// The upload is aborted on error or context cancellation so no orphaned parts are left behind.
+func (s3s *S3Storage) UploadLarge(ctx context.Context, bucket, key string, reader io.Reader, partSize int64) (err error) {
+	if partSize <= 0 {
+		partSize = defaultPartSize
+	}
+
+	created, err := s3s.client.CreateMultipartUploadWithContext(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create multipart upload: %w", err)
+	}
+	uploadID := created.UploadId
+
+	abort := func() {
+		// Use a fresh context so the abort still goes out after cancellation
+		_, _ = s3s.client.AbortMultipartUploadWithContext(context.Background(), &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(bucket),
+			Key:      aws.String(key),
+			UploadId: uploadID,
+		})
+	}
+	defer func() {
+		if err != nil {
+			abort()
+		}
+	}()
+
+	var parts []*s3.CompletedPart
+	buf := make([]byte, partSize)
+	for partNumber := int64(1); ; partNumber++ {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		n, readErr := io.ReadFull(reader, buf)
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil && readErr != io.ErrUnexpectedEOF {
+			return fmt.Errorf("failed to read part %d: %w", partNumber, readErr)
+		}
+
+		part, err := s3s.client.UploadPartWithContext(ctx, &s3.UploadPartInput{
+			Bucket:        aws.String(bucket),
+			Key:           aws.String(key),
+			UploadId:      uploadID,
+			PartNumber:    aws.Int64(partNumber),
+			Body:          bytes.NewReader(buf[:n]),
+			ContentLength: aws.Int64(int64(n)),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to upload part %d: %w", partNumber, err)
+		}
+		parts = append(parts, &s3.CompletedPart{ETag: part.ETag, PartNumber: aws.Int64(partNumber)})
+
+		// A short read means this was the last part
+		if readErr == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+
+	// S3 rejects completing an upload without parts, so store empty objects directly
+	if len(parts) == 0 {
+		abort()
+		return s3s.UploadToS3(ctx, bucket, key, nil)
+	}
+
+	_, err = s3s.client.CompleteMultipartUploadWithContext(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          aws.String(bucket),
+		Key:             aws.String(key),
+		UploadId:        uploadID,
+		MultipartUpload: &s3.CompletedMultipartUpload{Parts: parts},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete multipart upload: %w", err)
+	}
+	return nil
+}
+
+// PresignUpload returns a time-limited URL the client can PUT an object to directly
+func (s3s *S3Storage) PresignUpload(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	req, _ := s3s.client.PutObjectRequest(&s3.PutObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+	return req.Presign(ttl)
+}
+
+// PresignDownload returns a time-limited URL the client can GET an object from directly
+func (s3s *S3Storage) PresignDownload(ctx context.Context, bucket, key string, ttl time.Duration) (string, error) {
+	req, _ := s3s.client.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	req.SetContext(ctx)
+	return req.Presign(ttl)
+}
+
+// ErrFIFOQueueRequiresGroupID is returned when a FIFO queue is sent a message without a
+// message group ID
+var ErrFIFOQueueRequiresGroupID = errors.New("FIFO queue requires a message group ID; use SendFIFOMessage")
+
 // SQSQueue provides AWS SQS integration
 type SQSQueue struct {
 	client *sqs.SQS
 	url    string
+	fifo   bool // the URL ends in .fifo, so every message needs a group ID
 }
 
-// NewSQSQueue creates a new SQS queue instance
+// NewSQSQueue creates a new SQS queue instance. Queues whose URL ends in ".fifo" are FIFO
+// queues and must be sent to with SendFIFOMessage.
 func (ci *CloudIntegration) NewSQSQueue(queueURL string) (*SQSQueue, error) {
 	if ci.awsSession == nil {
 		return nil, fmt.Errorf("AWS session not initialized")
@@ -124,11 +272,21 @@ func (ci *CloudIntegration) NewSQSQueue(queueURL string) (*SQSQueue, error) {
 	return &SQSQueue{
 		client: sqs.New(ci.awsSession),
 		url:    queueURL,
+		fifo:   isFIFOQueueURL(queueURL),
 	}, nil
 }
 
-// SendMessageToSQS sends a message to SQS
+// IsFIFO reports whether the queue is a FIFO queue
+func (sqsq *SQSQueue) IsFIFO() bool { return sqsq.fifo }
+
+func isFIFOQueueURL(queueURL string) bool { return strings.HasSuffix(queueURL, ".fifo") }
+
+// SendMessageToSQS sends a message to a standard SQS queue. FIFO queues return
+// ErrFIFOQueueRequiresGroupID.
 func (sqsq *SQSQueue) SendMessageToSQS(ctx context.Context, message string) error {
+	if sqsq.fifo {
+		return fmt.Errorf("%w: %s", ErrFIFOQueueRequiresGroupID, sqsq.url)
+	}
 	_, err := sqsq.client.SendMessageWithContext(ctx, &sqs.SendMessageInput{
 		QueueUrl:    aws.String(sqsq.url),
 		MessageBody: aws.String(message),
@@ -136,6 +294,29 @@ func (sqsq *SQSQueue) SendMessageToSQS(ctx context.Context, message string) erro
 	return err
 }
 
+// SendFIFOMessage sends a message to a FIFO queue. Messages sharing groupID are delivered
+// in order; a message with the deduplicationID of one sent in the last five minutes is
+// accepted but not delivered again. deduplicationID may be empty only if the queue has
+// content-based deduplication enabled.
+func (sqsq *SQSQueue) SendFIFOMessage(ctx context.Context, message, groupID, deduplicationID string) error {
+	if !sqsq.fifo {
+		return fmt.Errorf("%s is not a FIFO queue; use SendMessageToSQS", sqsq.url)
+	}
+	if groupID == "" {
+		return ErrFIFOQueueRequiresGroupID
+	}
+	input := &sqs.SendMessageInput{
+		QueueUrl:       aws.String(sqsq.url),
+		MessageBody:    aws.String(message),
+		MessageGroupId: aws.String(groupID),
+	}
+	if deduplicationID != "" {
+		input.MessageDeduplicationId = aws.String(deduplicationID)
+	}
+	_, err := sqsq.client.SendMessageWithContext(ctx, input)
+	return err
+}
+
 // ReceiveMessagesFromSQS receives messages from SQS
 func (sqsq *SQSQueue) ReceiveMessagesFromSQS(ctx context.Context, maxMessages int64) ([]*sqs.Message, error) {
 	result, err := sqsq.client.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
@@ -260,9 +441,591 @@ func (gcpPubSub *GCPPubSub) SubscribeToPubSub(ctx context.Context, subscriptionN
 	})
 }
 
+// GCPSecretManager reads secrets from Google Cloud Secret Manager
+type GCPSecretManager struct {
+	client  *secretmanager.Client
+	project string
+}
+
+// NewGCPSecretManager creates a Secret Manager client for the configured GCP project,
+// authenticated with application default credentials
+func (ci *CloudIntegration) NewGCPSecretManager(ctx context.Context) (*GCPSecretManager, error) {
+	if ci.gcpProject == "" {
+		return nil, fmt.Errorf("GCP project ID not configured")
+	}
+
+	client, err := secretmanager.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCP Secret Manager client: %w", err)
+	}
+
+	return &GCPSecretManager{
+		client:  client,
+		project: ci.gcpProject,
+	}, nil
+}
+
+// GetSecret returns the payload of one version of a secret. secretName is either a bare
+// secret ID in the configured project or a full "projects/<p>/secrets/<id>" name.
+// The payload checksum is verified before it is returned.
+func (sm *GCPSecretManager) GetSecret(ctx context.Context, secretName, version string) (string, error) {
+	name := secretName
+	if !strings.HasPrefix(name, "projects/") {
+		name = "projects/" + sm.project + "/secrets/" + secretName
+	}
+	name += "/versions/" + version
+
+	resp, err := sm.client.AccessSecretVersion(ctx, &secretmanagerpb.AccessSecretVersionRequest{Name: name})
+	if err != nil {
+		return "", fmt.Errorf("failed to access secret %s: %w", name, err)
+	}
+	data := resp.GetPayload().GetData()
+	if sum := resp.GetPayload().DataCrc32C; sum != nil && int64(crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli))) != *sum {
+		return "", fmt.Errorf("secret %s failed its checksum", name)
+	}
+	return string(data), nil
+}
+
+// GetSecretLatest returns the payload of the latest enabled version of a secret
+func (sm *GCPSecretManager) GetSecretLatest(ctx context.Context, secretName string) (string, error) {
+	return sm.GetSecret(ctx, secretName, "latest")
+}
+
+// Close releases the Secret Manager client
+func (sm *GCPSecretManager) Close() error {
+	return sm.client.Close()
+}
+
+// AzureBlobStorage provides Azure Blob Storage integration
+type AzureBlobStorage struct {
+	client *azblob.Client
+}
+
+// NewAzureBlobStorage creates a new Azure Blob Storage instance using shared key auth.
+// Empty arguments fall back to the account and key from CloudConfig.
+func (ci *CloudIntegration) NewAzureBlobStorage(accountName, accountKey string) (*AzureBlobStorage, error) {
+	if accountName == "" {
+		accountName = ci.azureAccount
+	}
+	if accountKey == "" {
+		accountKey = ci.azureKey
+	}
+	if accountName == "" || accountKey == "" {
+		return nil, fmt.Errorf("Azure storage account not configured")
+	}
+
+	cred, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure credential: %w", err)
+	}
+
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", accountName)
+	client, err := azblob.NewClientWithSharedKeyCredential(serviceURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Azure blob client: %w", err)
+	}
+
+	return &AzureBlobStorage{
+		client: client,
+	}, nil
+}
+
+// Upload uploads data to an Azure blob
+func (abs *AzureBlobStorage) Upload(ctx context.Context, container, blob string, data []byte) error {
+	_, err := abs.client.UploadBuffer(ctx, container, blob, data, nil)
+	return err
+}
+
+// Download downloads data from an Azure blob
+func (abs *AzureBlobStorage) Download(ctx context.Context, container, blob string) ([]byte, error) {
+	resp, err := abs.client.DownloadStream(ctx, container, blob, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	return io.ReadAll(resp.Body)
+}
+
+// Delete deletes an Azure blob
+func (abs *AzureBlobStorage) Delete(ctx context.Context, container, blob string) error {
+	_, err := abs.client.DeleteBlob(ctx, container, blob, nil)
+	return err
+}
+
+// ServiceBusEntityType selects whether an AzureServiceBus talks to a queue or a topic
+type ServiceBusEntityType string
+
+const (
+	ServiceBusQueue ServiceBusEntityType = "queue"
+	ServiceBusTopic ServiceBusEntityType = "topic"
+)
+
+// ErrDeadLetter, wrapped in a ReceiveMessages handler's error, dead-letters the message
+// instead of abandoning it for redelivery, e.g. because it can never be processed.
+var ErrDeadLetter = errors.New("dead-letter message")
+
+// AzureServiceBus provides Azure Service Bus integration through the azservicebus SDK
+type AzureServiceBus struct {
+	client       *azservicebus.Client
+	entityType   ServiceBusEntityType
+	subscription string
+}
+
+// NewAzureServiceBus creates a new Service Bus instance for queues or topics.
+// The subscription name is only used when receiving from a topic.
+func (ci *CloudIntegration) NewAzureServiceBus(entityType ServiceBusEntityType, subscription string) (*AzureServiceBus, error) {
+	if ci.azureSBConn == "" {
+		return nil, fmt.Errorf("Azure Service Bus connection string not configured")
+	}
+	if entityType != ServiceBusQueue && entityType != ServiceBusTopic {
+		return nil, fmt.Errorf("unsupported Service Bus entity type: %s", entityType)
+	}
+	if entityType == ServiceBusTopic && subscription == "" {
+		return nil, fmt.Errorf("subscription name is required for Service Bus topics")
+	}
+
+	// Connection strings look like Endpoint=sb://ns.servicebus.windows.net/;SharedAccessKeyName=...;SharedAccessKey=...
+	client, err := azservicebus.NewClientFromConnectionString(ci.azureSBConn, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Azure Service Bus connection string: %w", err)
+	}
+
+	return &AzureServiceBus{
+		client:       client,
+		entityType:   entityType,
+		subscription: subscription,
+	}, nil
+}
+
+// Close closes the connection to the Service Bus namespace
+func (sb *AzureServiceBus) Close(ctx context.Context) error {
+	return sb.client.Close(ctx)
+}
+
+// SendMessage sends a message to a queue or topic, optionally within a session
+func (sb *AzureServiceBus) SendMessage(ctx context.Context, queueOrTopic string, body []byte, sessionID string) error {
+	sender, err := sb.client.NewSender(queueOrTopic, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create Service Bus sender: %w", err)
+	}
+	defer sender.Close(context.WithoutCancel(ctx))
+
+	contentType := "application/json"
+	msg := &azservicebus.Message{Body: body, ContentType: &contentType}
+	if sessionID != "" {
+		msg.SessionID = &sessionID
+	}
+	if err := sender.SendMessage(ctx, msg, nil); err != nil {
+		return fmt.Errorf("failed to send message: %w", err)
+	}
+	return nil
+}
+
+// ReceiveMessages waits for messages and receives up to maxMessages using peek-lock,
+// completing each message when the handler succeeds. When the handler fails the message is
+// abandoned for redelivery, and Service Bus dead-letters it once the entity's maximum
+// delivery count is reached; a handler error wrapping ErrDeadLetter dead-letters it at once.
+// For topics, queue names the topic and messages are read from the configured subscription.
+func (sb *AzureServiceBus) ReceiveMessages(ctx context.Context, queue string, maxMessages int, handler func([]byte) error) error {
+	var receiver *azservicebus.Receiver
+	var err error
+	if sb.entityType == ServiceBusTopic {
+		receiver, err = sb.client.NewReceiverForSubscription(queue, sb.subscription, nil)
+	} else {
+		receiver, err = sb.client.NewReceiverForQueue(queue, nil)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create Service Bus receiver: %w", err)
+	}
+	defer receiver.Close(context.WithoutCancel(ctx))
+
+	msgs, err := receiver.ReceiveMessages(ctx, maxMessages, nil)
+	if err != nil {
+		return fmt.Errorf("failed to receive messages: %w", err)
+	}
+	for _, msg := range msgs {
+		herr := handler(msg.Body)
+		switch {
+		case herr == nil:
+			err = receiver.CompleteMessage(ctx, msg, nil)
+		case errors.Is(herr, ErrDeadLetter):
+			reason, desc := "HandlerRejected", herr.Error()
+			err = receiver.DeadLetterMessage(ctx, msg, &azservicebus.DeadLetterOptions{Reason: &reason, ErrorDescription: &desc})
+		default:
+			err = receiver.AbandonMessage(ctx, msg, nil)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to settle message %s: %w", msg.MessageID, err)
+		}
+	}
+
+	return nil
+}
+
+// EventBus publishes enterprise events to a message broker so the same event
+// can fan out to SQS, Azure Service Bus, or GCP Pub/Sub
+type EventBus interface {
+	PublishEvent(ctx context.Context, destination string, event BlockchainToEnterpriseEvent) error
+}
+
+// PublishEvent sends the event to SQS; a non-empty destination overrides the queue URL.
+// On a FIFO queue events are grouped by type and deduplicated by ID.
+func (sqsq *SQSQueue) PublishEvent(ctx context.Context, destination string, event BlockchainToEnterpriseEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	queueURL := sqsq.url
+	if destination != "" {
+		queueURL = destination
+	}
+	input := &sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(string(data)),
+	}
+	if isFIFOQueueURL(queueURL) {
+		group := event.EventType
+		if group == "" {
+			group = "events"
+		}
+		input.MessageGroupId = aws.String(group)
+		if event.ID != "" {
+			input.MessageDeduplicationId = aws.String(event.ID)
+		}
+	}
+	_, err = sqsq.client.SendMessageWithContext(ctx, input)
+	return err
+}
+
+// PublishEvent sends the event to a Service Bus queue or topic
+func (sb *AzureServiceBus) PublishEvent(ctx context.Context, destination string, event BlockchainToEnterpriseEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return sb.SendMessage(ctx, destination, data, "")
+}
+
+// PublishEvent sends the event to a Pub/Sub topic
+func (gcpPubSub *GCPPubSub) PublishEvent(ctx context.Context, destination string, event BlockchainToEnterpriseEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return gcpPubSub.PublishToPubSub(ctx, destination, data)
+}
+
+// WebhookSignatureHeader carries the HMAC-SHA256 signature of the webhook body
+const WebhookSignatureHeader = "X-GARP-Signature"
+
+var (
+	// ErrMissingSignature is returned when a webhook arrives without a signature header
+	ErrMissingSignature = errors.New("missing webhook signature")
+	// ErrInvalidSignature is returned when the webhook signature does not match the body
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrRetryQueued is wrapped in a failed send's error when the delivery was queued for
+	// the retry worker
+	ErrRetryQueued = errors.New("queued for retry")
+)
+
 // WebhookSender provides webhook integration capabilities
 type WebhookSender struct {
 	httpClient *http.Client
+	// WebhookSecret signs outgoing payloads when set
+	WebhookSecret string
+	// UseCloudEvents sends blockchain events as structured-mode CloudEvents 1.0
+	UseCloudEvents bool
+	// CloudEventsBinary switches UseCloudEvents to binary mode: the attributes travel as
+	// ce-* headers and the body is the event data alone
+	CloudEventsBinary bool
+	delivery       *WebhookDeliveryConfig
+}
+
+// WebhookDeliveryConfig controls retries of failed webhook deliveries
+type WebhookDeliveryConfig struct {
+	MaxAttempts   int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	BackoffFactor float64
+	Redis         *redis.Client // Holds the delivery queue of failed attempts
+	QueueKey      string        // Redis list key, defaults to "webhook_delivery_queue"
+	// WorkerID names the worker's processing list and lease; defaults to one unique per
+	// RunDeliveryWorker call. Only set it to something unique per worker.
+	WorkerID string
+	// LeaseTTL is how long a worker that stopped renewing its lease keeps its in-flight
+	// entries before another worker requeues them, defaults to 30s
+	LeaseTTL time.Duration
+}
+
+// webhookDelivery is a queued delivery awaiting retry
+type webhookDelivery struct {
+	URL         string          `json:"url"`
+	ContentType string            `json:"content_type"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Payload     json.RawMessage   `json:"payload"`
+	Attempts    int               `json:"attempts"`
+	NextAt      time.Time         `json:"next_at"`
+}
+
+// WithDelivery enables queued retries of failed deliveries, filling in defaults for unset fields
+func (ws *WebhookSender) WithDelivery(cfg WebhookDeliveryConfig) *WebhookSender {
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = time.Second
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 5 * time.Minute
+	}
+	if cfg.BackoffFactor < 1 {
+		cfg.BackoffFactor = 2
+	}
+	if cfg.QueueKey == "" {
+		cfg.QueueKey = "webhook_delivery_queue"
+	}
+	if cfg.LeaseTTL <= 0 {
+		cfg.LeaseTTL = 30 * time.Second
+	}
+	ws.delivery = &cfg
+	return ws
+}
+
+// backoff returns the delay before the next attempt after the given number of failed attempts
+func (cfg *WebhookDeliveryConfig) backoff(attempts int) time.Duration {
+	d := float64(cfg.BaseDelay) * math.Pow(cfg.BackoffFactor, float64(attempts-1))
+	if d > float64(cfg.MaxDelay) {
+		return cfg.MaxDelay
+	}
+	return time.Duration(d)
+}
+
+// enqueue stores a failed delivery in Redis for the retry worker
+func (ws *WebhookSender) enqueue(ctx context.Context, d webhookDelivery) error {
+	d.NextAt = time.Now().Add(ws.delivery.backoff(d.Attempts))
+	b, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	return ws.delivery.Redis.LPush(ctx, ws.delivery.QueueKey, b).Err()
+}
+
+// RunDeliveryWorker retries queued deliveries until ctx is cancelled.
+// Each entry is moved atomically (BLMOVE) into a processing list of this worker's own and
+// only removed from it once it has been delivered, requeued or given up on. The worker holds
+// a lease key that it renews every LeaseTTL/3; when a worker dies its lease expires, and
+// any other worker puts the entries left in its processing list back on the queue, at start
+// and every LeaseTTL after. A worker that stops cleanly returns its own entries. Several
+// workers can share a queue, and delivery is at-least-once. Deliveries that still fail after
+// MaxAttempts are dropped.
+func (ws *WebhookSender) RunDeliveryWorker(ctx context.Context) error {
+	if ws.delivery == nil || ws.delivery.Redis == nil {
+		return fmt.Errorf("webhook delivery queue not configured")
+	}
+	rdb, queue := ws.delivery.Redis, ws.delivery.QueueKey
+	id := ws.delivery.WorkerID
+	if id == "" {
+		id = newWorkerID()
+	}
+	processing := queue + ":processing:" + id
+	lease := queue + ":worker:" + id
+	ttl := ws.delivery.LeaseTTL
+
+	renew := func() error {
+		return rdb.Set(context.WithoutCancel(ctx), lease, time.Now().UTC().Format(time.RFC3339), ttl).Err()
+	}
+	if err := renew(); err != nil {
+		return fmt.Errorf("failed to register webhook delivery worker: %w", err)
+	}
+	defer func() {
+		// Hand back anything still in flight, e.g. an entry that failed to requeue
+		bg := context.WithoutCancel(ctx)
+		if err := requeueAll(bg, rdb, processing, queue); err != nil {
+			slog.Error("webhook delivery: failed to return in-flight entries", "error", err)
+		}
+		_ = rdb.Del(bg, lease).Err()
+	}()
+
+	renewCtx, stopRenew := context.WithCancel(ctx)
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		t := time.NewTicker(ttl / 3)
+		defer t.Stop()
+		for {
+			select {
+			case <-renewCtx.Done():
+				return
+			case <-t.C:
+				if err := renew(); err != nil {
+					slog.Error("webhook delivery: failed to renew worker lease", "error", err)
+				}
+			}
+		}
+	}()
+	defer func() {
+		stopRenew()
+		<-renewed
+	}()
+
+	if err := ws.recoverOrphans(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		return fmt.Errorf("failed to recover in-flight webhook deliveries: %w", err)
+	}
+	nextRecovery := time.Now().Add(ttl)
+
+	ack := func(raw string) {
+		if err := rdb.LRem(context.WithoutCancel(ctx), processing, 1, raw).Err(); err != nil {
+			slog.Error("webhook delivery: failed to acknowledge entry", "error", err)
+		}
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil
+		}
+		if time.Now().After(nextRecovery) {
+			if err := ws.recoverOrphans(ctx); err != nil && ctx.Err() == nil {
+				slog.Error("webhook delivery: failed to recover entries of stopped workers", "error", err)
+			}
+			nextRecovery = time.Now().Add(ttl)
+		}
+		raw, err := rdb.BLMove(ctx, queue, processing, "RIGHT", "LEFT", time.Second).Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			slog.Error("webhook delivery: redis error", "error", err)
+			time.Sleep(time.Second)
+			continue
+		}
+
+		var d webhookDelivery
+		if err := json.Unmarshal([]byte(raw), &d); err != nil {
+			slog.Warn("webhook delivery: dropping malformed entry", "error", err)
+			ack(raw)
+			continue
+		}
+
+		// Not due yet: put it back and wait a little before looking again
+		if wait := time.Until(d.NextAt); wait > 0 {
+			_, err := rdb.TxPipelined(context.WithoutCancel(ctx), func(p redis.Pipeliner) error {
+				p.LPush(ctx, queue, raw)
+				p.LRem(ctx, processing, 1, raw)
+				return nil
+			})
+			if err != nil {
+				slog.Error("webhook delivery: failed to return entry to the queue", "error", err)
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(min(wait, time.Second)):
+			}
+			continue
+		}
+
+		if err := ws.deliver(ctx, d.URL, d.ContentType, d.Headers, d.Payload); err != nil {
+			d.Attempts++
+			if d.Attempts >= ws.delivery.MaxAttempts {
+				slog.Error("webhook delivery failed, giving up", "url", d.URL, "attempts", d.Attempts, "error", err)
+			} else if err := ws.enqueue(context.WithoutCancel(ctx), d); err != nil {
+				slog.Error("webhook delivery: failed to requeue", "url", d.URL, "error", err)
+				continue // leave it in the processing list; it goes back on the queue when the worker stops
+			}
+		}
+		ack(raw)
+	}
+}
+
+// recoverOrphans puts the entries in the processing lists of workers whose lease has expired
+// back on the queue, along with the single shared processing list older versions used.
+func (ws *WebhookSender) recoverOrphans(ctx context.Context) error {
+	rdb, queue := ws.delivery.Redis, ws.delivery.QueueKey
+	prefix := queue + ":processing:"
+	orphans := []string{queue + ":processing"}
+	iter := rdb.Scan(ctx, 0, prefix+"*", 100).Iterator()
+	for iter.Next(ctx) {
+		key := iter.Val()
+		alive, err := rdb.Exists(ctx, queue+":worker:"+strings.TrimPrefix(key, prefix)).Result()
+		if err != nil {
+			return err
+		}
+		if alive == 0 {
+			orphans = append(orphans, key)
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	for _, key := range orphans {
+		if err := requeueAll(ctx, rdb, key, queue); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requeueAll moves every entry of the list from back onto queue
+func requeueAll(ctx context.Context, rdb *redis.Client, from, queue string) error {
+	for {
+		err := rdb.LMove(ctx, from, queue, "RIGHT", "LEFT").Err()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// newWorkerID returns a delivery worker ID that is unique across hosts and processes
+func newWorkerID() string {
+	host, _ := os.Hostname()
+	b := make([]byte, 4)
+	_, _ = rand.Read(b)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(b))
+}
+
+// signWebhook returns the signature header value for a payload
+func signWebhook(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyWebhookSignature checks a "sha256=<hex>" signature header against the body
+func VerifyWebhookSignature(secret string, body []byte, sigHeader string) error {
+	if sigHeader == "" {
+		return ErrMissingSignature
+	}
+	sigHex, ok := strings.CutPrefix(sigHeader, "sha256=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	sig, err := hex.DecodeString(sigHex)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// NewWebhookSender creates a webhook sender that does not need a cloud provider configured.
+// Requests carry the trace context like the CloudIntegration clients.
+func NewWebhookSender(timeout time.Duration) *WebhookSender {
+	return &WebhookSender{
+		httpClient: otel.HTTPInjectMiddleware(&http.Client{Timeout: timeout}),
+	}
 }
 
 // NewWebhookSender creates a new webhook sender instance
@@ -272,14 +1035,33 @@ func (ci *CloudIntegration) NewWebhookSender() *WebhookSender {
 	}
 }
 
-// SendWebhook sends a webhook to a specified URL
+// SendWebhook sends a webhook to a specified URL.
+// When delivery retries are enabled, a failed attempt is also queued for the retry worker,
+// and the returned error wraps ErrRetryQueued if that succeeded.
 func (ws *WebhookSender) SendWebhook(ctx context.Context, url string, payload interface{}) error {
+    return ws.send(ctx, url, "application/json", nil, payload)
+}
+
+// send marshals and delivers a payload, queueing a retry on failure when enabled
+func (ws *WebhookSender) send(ctx context.Context, url, contentType string, headers map[string]string, payload interface{}) error {
     // Marshal the payload to JSON
     data, err := json.Marshal(payload)
     if err != nil {
         return fmt.Errorf("failed to marshal payload: %w", err)
     }
-    
+
+    err = ws.deliver(ctx, url, contentType, headers, data)
+    if err != nil && ws.delivery != nil && ws.delivery.Redis != nil {
+        if qerr := ws.enqueue(ctx, webhookDelivery{URL: url, ContentType: contentType, Headers: headers, Payload: data, Attempts: 1}); qerr != nil {
+            return fmt.Errorf("%w (failed to queue retry: %v)", err, qerr)
+        }
+        return fmt.Errorf("%w (%w)", err, ErrRetryQueued)
+    }
+    return err
+}
+
+// deliver makes a single delivery attempt of an encoded payload
+func (ws *WebhookSender) deliver(ctx context.Context, url, contentType string, headers map[string]string, data []byte) error {
     // Create the HTTP request
     req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(data))
     if err != nil {
@@ -287,8 +1069,17 @@ func (ws *WebhookSender) SendWebhook(ctx context.Context, url string, payload in
     }
 	
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
+	if contentType == "" {
+		contentType = "application/json"
+	}
+	req.Header.Set("Content-Type", contentType)
 	req.Header.Set("User-Agent", "GARP-Blockchain/1.0")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if ws.WebhookSecret != "" {
+		req.Header.Set(WebhookSignatureHeader, signWebhook(ws.WebhookSecret, data))
+	}
 	
 	// Send the request
 	resp, err := ws.httpClient.Do(req)
@@ -313,6 +1104,33 @@ type BlockchainEvent struct {
 	Data      interface{} `json:"data"`
 }
 
+// CloudEventSource identifies GARP as the producer of CloudEvents
+const CloudEventSource = "urn:garp:blockchain"
+
+// ToCloudEvent converts the event to a structured-mode CloudEvents 1.0 envelope
+func (e BlockchainEvent) ToCloudEvent() map[string]interface{} {
+	return map[string]interface{}{
+		"specversion":     "1.0",
+		"type":            e.Type,
+		"source":          CloudEventSource,
+		"id":              e.ID,
+		"time":            e.Timestamp.UTC().Format(time.RFC3339Nano),
+		"datacontenttype": "application/json",
+		"data":            e.Data,
+	}
+}
+
+// cloudEventHeaders returns the binary-mode CloudEvents 1.0 HTTP headers for the event
+func (e BlockchainEvent) cloudEventHeaders() map[string]string {
+	return map[string]string{
+		"ce-specversion": "1.0",
+		"ce-type":        e.Type,
+		"ce-source":      CloudEventSource,
+		"ce-id":          e.ID,
+		"ce-time":        e.Timestamp.UTC().Format(time.RFC3339Nano),
+	}
+}
+
 // SendBlockchainEvent sends a blockchain event via webhook
 func (ws *WebhookSender) SendBlockchainEvent(ctx context.Context, url string, eventType string, data interface{}) error {
 	event := BlockchainEvent{
@@ -321,6 +1139,12 @@ func (ws *WebhookSender) SendBlockchainEvent(ctx context.Context, url string, ev
 		Timestamp: time.Now().UTC(),
 		Data:      data,
 	}
-	
+
+	if ws.UseCloudEvents && ws.CloudEventsBinary {
+		return ws.send(ctx, url, "application/json", event.cloudEventHeaders(), event.Data)
+	}
+	if ws.UseCloudEvents {
+		return ws.send(ctx, url, "application/cloudevents+json", nil, event.ToCloudEvent())
+	}
 	return ws.SendWebhook(ctx, url, event)
 }
\ No newline at end of file
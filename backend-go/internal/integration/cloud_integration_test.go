@@ -0,0 +1,207 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestVerifyWebhookSignature(t *testing.T) {
+	const secret = "webhook-secret"
+	body := []byte(`{"id":"evt-1","type":"block.finalized"}`)
+	sig := signWebhook(secret, body)
+
+	tests := []struct {
+		name   string
+		body   []byte
+		header string
+		want   error
+	}{
+		{name: "correct signature", body: body, header: sig, want: nil},
+		{name: "tampered body", body: []byte(`{"id":"evt-1","type":"block.reverted"}`), header: sig, want: ErrInvalidSignature},
+		{name: "missing header", body: body, header: "", want: ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := VerifyWebhookSignature(secret, tt.body, tt.header); !errors.Is(err, tt.want) {
+				t.Fatalf("VerifyWebhookSignature() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestToCloudEvent(t *testing.T) {
+	ts := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	e := BlockchainEvent{ID: "evt-1", Type: "block.finalized", Timestamp: ts, Data: map[string]int{"height": 7}}
+
+	raw, err := json.Marshal(e.ToCloudEvent())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ce map[string]interface{}
+	if err := json.Unmarshal(raw, &ce); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"specversion":     "1.0",
+		"id":              "evt-1",
+		"source":          CloudEventSource,
+		"type":            "block.finalized",
+		"time":            "2024-05-01T12:00:00Z",
+		"datacontenttype": "application/json",
+	}
+	for k, v := range want {
+		if got, _ := ce[k].(string); got != v {
+			t.Errorf("%s = %q, want %q", k, got, v)
+		}
+	}
+	if data, _ := ce["data"].(map[string]interface{}); data["height"] != float64(7) {
+		t.Errorf("data = %v, want height 7", ce["data"])
+	}
+}
+
+func TestSendBlockchainEventCloudEventsModes(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header.Clone(), body: body}
+	}))
+	defer srv.Close()
+
+	t.Run("structured", func(t *testing.T) {
+		ws := NewWebhookSender(time.Second)
+		ws.UseCloudEvents = true
+		if err := ws.SendBlockchainEvent(context.Background(), srv.URL, "block.finalized", map[string]int{"height": 7}); err != nil {
+			t.Fatal(err)
+		}
+		r := <-got
+		if ct := r.header.Get("Content-Type"); ct != "application/cloudevents+json" {
+			t.Errorf("Content-Type = %q, want application/cloudevents+json", ct)
+		}
+		if v := r.header.Get("ce-specversion"); v != "" {
+			t.Errorf("structured mode sent ce-specversion header %q", v)
+		}
+		var ce map[string]interface{}
+		if err := json.Unmarshal(r.body, &ce); err != nil {
+			t.Fatal(err)
+		}
+		for _, k := range []string{"specversion", "id", "source", "type"} {
+			if s, _ := ce[k].(string); s == "" {
+				t.Errorf("envelope is missing %q", k)
+			}
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		ws := NewWebhookSender(time.Second)
+		ws.UseCloudEvents = true
+		ws.CloudEventsBinary = true
+		if err := ws.SendBlockchainEvent(context.Background(), srv.URL, "block.finalized", map[string]int{"height": 7}); err != nil {
+			t.Fatal(err)
+		}
+		r := <-got
+		if ct := r.header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q, want application/json", ct)
+		}
+		want := map[string]string{"ce-specversion": "1.0", "ce-source": CloudEventSource, "ce-type": "block.finalized"}
+		for k, v := range want {
+			if h := r.header.Get(k); h != v {
+				t.Errorf("%s = %q, want %q", k, h, v)
+			}
+		}
+		if r.header.Get("ce-id") == "" {
+			t.Error("ce-id header is missing")
+		}
+		if string(r.body) != `{"height":7}` {
+			t.Errorf("body = %s, want the event data only", r.body)
+		}
+	})
+}
+
+func TestRunDeliveryWorkerRetriesAndAcks(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer rdb.Close()
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	ws := NewWebhookSender(time.Second).WithDelivery(WebhookDeliveryConfig{Redis: rdb, BaseDelay: 10 * time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := ws.SendWebhook(ctx, srv.URL, map[string]string{"hash": "tx-1"}); !errors.Is(err, ErrRetryQueued) {
+		t.Fatalf("first delivery: err = %v, want ErrRetryQueued", err)
+	}
+	// A worker that died mid-delivery leaves its entry in its processing list, with no lease
+	processing := ws.delivery.QueueKey + ":processing:dead"
+	if err := rdb.LMove(ctx, ws.delivery.QueueKey, processing, "RIGHT", "LEFT").Err(); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() { done <- ws.RunDeliveryWorker(ctx) }()
+	deadline := time.Now().Add(5 * time.Second)
+	for calls.Load() < 3 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	// Give the worker a moment to acknowledge the delivery
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if n := calls.Load(); n != 3 {
+		t.Fatalf("webhook called %d times, want 3", n)
+	}
+	for _, key := range []string{ws.delivery.QueueKey, processing} {
+		if n, _ := rdb.LLen(context.Background(), key).Result(); n != 0 {
+			t.Errorf("%s still holds %d entries", key, n)
+		}
+	}
+	// The stopped worker leaves neither its processing list nor its lease behind
+	if keys, _ := rdb.Keys(context.Background(), ws.delivery.QueueKey+":*").Result(); len(keys) != 0 {
+		t.Errorf("keys left after the worker stopped: %v", keys)
+	}
+}
+
+func TestRecoverOrphansSkipsLiveWorkers(t *testing.T) {
+	rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+	defer rdb.Close()
+	ctx := context.Background()
+	ws := NewWebhookSender(time.Second).WithDelivery(WebhookDeliveryConfig{Redis: rdb})
+	queue := ws.delivery.QueueKey
+
+	rdb.RPush(ctx, queue+":processing:live", "in-flight")
+	rdb.Set(ctx, queue+":worker:live", "now", time.Minute)
+	rdb.RPush(ctx, queue+":processing:dead", "orphan")
+	rdb.RPush(ctx, queue+":processing", "legacy")
+	if err := ws.recoverOrphans(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	got, _ := rdb.LRange(ctx, queue, 0, -1).Result()
+	slices.Sort(got)
+	if want := []string{"legacy", "orphan"}; !slices.Equal(got, want) {
+		t.Errorf("queue = %v, want %v", got, want)
+	}
+	if got, _ := rdb.LRange(ctx, queue+":processing:live", 0, -1).Result(); !slices.Equal(got, []string{"in-flight"}) {
+		t.Errorf("live worker's processing list = %v, want it untouched", got)
+	}
+}
\ No newline at end of file
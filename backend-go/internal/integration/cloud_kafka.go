@@ -0,0 +1,166 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	// kafkaProduceChunk caps the messages handed to the writer per WriteMessages call.
+	kafkaProduceChunk = 1000
+	// kafkaProduceAttempts bounds how often ProduceEvents resends messages that failed with
+	// a retriable error, such as a partition leader moving mid-write.
+	kafkaProduceAttempts = 5
+)
+
+// KafkaCloudBridge streams BlockchainEvents to and from Kafka topics as JSON.
+type KafkaCloudBridge struct {
+	brokers []string
+	dialer  *kafka.Dialer
+	writer  messageWriter
+}
+
+// messageWriter is the part of *kafka.Writer the bridge produces with.
+type messageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// NewKafkaCloudBridge creates a bridge to the brokers in CloudConfig.KafkaBootstrapServers.
+// Connections are opened lazily on the first produce or consume.
+func (ci *CloudIntegration) NewKafkaCloudBridge() (*KafkaCloudBridge, error) {
+	if len(ci.kafkaBrokers) == 0 {
+		return nil, errors.New("kafka bootstrap servers not configured")
+	}
+	return &KafkaCloudBridge{
+		brokers: ci.kafkaBrokers,
+		dialer:  &kafka.Dialer{Timeout: 10 * time.Second, DualStack: true, TLS: ci.kafkaTLS},
+		writer: &kafka.Writer{
+			Addr:         kafka.TCP(ci.kafkaBrokers...),
+			Balancer:     &kafka.Hash{}, // events with the same ID land on the same partition
+			RequiredAcks: kafka.RequireAll,
+			BatchSize:    500,
+			BatchTimeout: 10 * time.Millisecond, // writes are synchronous; don't wait long for a batch to fill
+			// The writer refreshes metadata and retries on leader changes before giving up
+			MaxAttempts:     10,
+			WriteBackoffMin: 100 * time.Millisecond,
+			WriteBackoffMax: 2 * time.Second,
+			Transport:       &kafka.Transport{TLS: ci.kafkaTLS},
+		},
+	}, nil
+}
+
+// ProduceEvents writes events to topic in batches, keyed by event ID. Messages that still
+// fail with a retriable error after the writer's own retries (typically because a
+// partition leader moved) are resent up to kafkaProduceAttempts times. Only those messages
+// are resent, as reported per message in kafka.WriteErrors, so the rest are not duplicated.
+// Any other failure is returned as an error.
+func (k *KafkaCloudBridge) ProduceEvents(ctx context.Context, topic string, events []BlockchainEvent) error {
+	msgs := make([]kafka.Message, len(events))
+	for i, e := range events {
+		value, err := json.Marshal(e)
+		if err != nil {
+			return fmt.Errorf("failed to marshal event %s: %w", e.ID, err)
+		}
+		msgs[i] = kafka.Message{
+			Topic:   topic,
+			Key:     []byte(e.ID),
+			Value:   value,
+			Time:    e.Timestamp,
+			Headers: []kafka.Header{{Key: "type", Value: []byte(e.Type)}},
+		}
+	}
+	for start := 0; start < len(msgs); start += kafkaProduceChunk {
+		if err := k.writeWithRetry(ctx, msgs[start:min(start+kafkaProduceChunk, len(msgs))]); err != nil {
+			return fmt.Errorf("failed to produce to kafka topic %s: %w", topic, err)
+		}
+	}
+	return nil
+}
+
+func (k *KafkaCloudBridge) writeWithRetry(ctx context.Context, msgs []kafka.Message) error {
+	backoff := 250 * time.Millisecond
+	for attempt := 1; ; attempt++ {
+		err := k.writer.WriteMessages(ctx, msgs...)
+		if err == nil {
+			return nil
+		}
+		var writeErrs kafka.WriteErrors
+		if !errors.As(err, &writeErrs) || attempt == kafkaProduceAttempts {
+			return err
+		}
+		// Resend only the messages that failed, and only if every failure is retriable
+		var retry []kafka.Message
+		for i, werr := range writeErrs {
+			if werr == nil {
+				continue
+			}
+			var kerr kafka.Error
+			if !errors.As(werr, &kerr) || !kerr.Temporary() {
+				return werr
+			}
+			retry = append(retry, msgs[i])
+		}
+		slog.Warn("kafka write failed, retrying", "failed", len(retry), "attempt", attempt, "error", err)
+		msgs = retry
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// ConsumeEvents reads topic as a member of consumer group groupID and passes each event to
+// handler, committing its offset once handler returns nil. If handler fails, the event is
+// left uncommitted and ConsumeEvents returns the error, so the event is redelivered when
+// the group resumes. Leader changes and rebalances are handled by the reader. ConsumeEvents
+// blocks until ctx is cancelled, in which case it returns nil.
+func (k *KafkaCloudBridge) ConsumeEvents(ctx context.Context, topic, groupID string, handler func(BlockchainEvent) error) error {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:        k.brokers,
+		GroupID:        groupID,
+		Topic:          topic,
+		Dialer:         k.dialer,
+		MinBytes:       1,
+		MaxBytes:       10 << 20,
+		CommitInterval: 0, // commit synchronously after each handled event
+		StartOffset:    kafka.FirstOffset,
+	})
+	defer r.Close()
+
+	for {
+		m, err := r.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to fetch from kafka topic %s: %w", topic, err)
+		}
+		var e BlockchainEvent
+		if err := json.Unmarshal(m.Value, &e); err != nil {
+			// A malformed message can never succeed; skip it rather than block the partition
+			slog.Warn("skipping malformed kafka event", "topic", topic, "partition", m.Partition, "offset", m.Offset, "error", err)
+		} else if err := handler(e); err != nil {
+			return fmt.Errorf("handler failed for kafka event %s at %s/%d@%d: %w", e.ID, topic, m.Partition, m.Offset, err)
+		}
+		if err := r.CommitMessages(ctx, m); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("failed to commit kafka offset: %w", err)
+		}
+	}
+}
+
+// Close flushes and closes the producer.
+func (k *KafkaCloudBridge) Close() error {
+	return k.writer.Close()
+}
\ No newline at end of file
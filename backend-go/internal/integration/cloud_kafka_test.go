@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// fakeKafkaWriter records the keys of every WriteMessages call and fails them as told.
+type fakeKafkaWriter struct {
+	calls [][]string
+	fail  func(call int, msgs []kafka.Message) error
+}
+
+func (w *fakeKafkaWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	keys := make([]string, len(msgs))
+	for i, m := range msgs {
+		keys[i] = string(m.Key)
+	}
+	w.calls = append(w.calls, keys)
+	return w.fail(len(w.calls), msgs)
+}
+
+func (w *fakeKafkaWriter) Close() error { return nil }
+
+func TestProduceEventsRetriesFailedMessages(t *testing.T) {
+	events := []BlockchainEvent{{ID: "a", Timestamp: time.Now()}, {ID: "b", Timestamp: time.Now()}, {ID: "c", Timestamp: time.Now()}}
+	tests := []struct {
+		name      string
+		fail      func(call int, msgs []kafka.Message) error
+		wantCalls [][]string
+		wantErr   error
+	}{
+		{
+			name: "only the failed message is resent",
+			fail: func(call int, msgs []kafka.Message) error {
+				if call == 1 {
+					return kafka.WriteErrors{nil, kafka.LeaderNotAvailable, nil}
+				}
+				return nil
+			},
+			wantCalls: [][]string{{"a", "b", "c"}, {"b"}},
+		},
+		{
+			name: "a permanent failure is not retried",
+			fail: func(call int, msgs []kafka.Message) error {
+				return kafka.WriteErrors{nil, kafka.LeaderNotAvailable, kafka.MessageSizeTooLarge}
+			},
+			wantCalls: [][]string{{"a", "b", "c"}},
+			wantErr:   kafka.MessageSizeTooLarge,
+		},
+		{
+			name: "an error for the whole batch is returned",
+			fail: func(call int, msgs []kafka.Message) error {
+				return kafka.TopicAuthorizationFailed
+			},
+			wantCalls: [][]string{{"a", "b", "c"}},
+			wantErr:   kafka.TopicAuthorizationFailed,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &fakeKafkaWriter{fail: tt.fail}
+			k := &KafkaCloudBridge{writer: w}
+			err := k.ProduceEvents(context.Background(), "events", events)
+			if !errors.Is(err, tt.wantErr) || (tt.wantErr == nil) != (err == nil) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.EqualFunc(w.calls, tt.wantCalls, slices.Equal) {
+				t.Fatalf("writes = %v, want %v", w.calls, tt.wantCalls)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,149 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// archiveBatchSize is how many transactions ArchiveTransactions moves per round trip.
+const archiveBatchSize = 500
+
+// transactionColumns lists blockchain_transactions columns in TransactionRecord order.
+var transactionColumns = []string{"id", "submitter", "status", "created_at", "confirmed_at", "block_number", "block_hash", "data"}
+
+// ArchiveTransactions moves transactions created before olderThan into the database at
+// archiveDSN (same driver as dbi), creating its schema if needed, and returns how many
+// were moved. Rows are copied in batches and each batch is deleted from the primary only
+// after the archive has committed it, so an interrupted run can simply be repeated:
+// rows already present in the archive are skipped.
+func (dbi *DBIntegration) ArchiveTransactions(ctx context.Context, olderThan time.Time, archiveDSN string) (int64, error) {
+	archive, err := NewDBIntegration(Config{Driver: dbi.driver, DSN: archiveDSN, MaxConns: 2})
+	if err != nil {
+		return 0, fmt.Errorf("failed to open archive database: %w", err)
+	}
+	defer archive.db.Close()
+	if err := archive.InitializeSchema(ctx); err != nil {
+		return 0, fmt.Errorf("failed to initialize archive schema: %w", err)
+	}
+
+	var archived int64
+	for {
+		batch, err := dbi.transactionsOlderThan(ctx, olderThan, archiveBatchSize)
+		if err != nil {
+			return archived, err
+		}
+		if len(batch) == 0 {
+			return archived, nil
+		}
+		if err := archive.copyTransactions(ctx, batch); err != nil {
+			return archived, fmt.Errorf("failed to copy transactions to archive: %w", err)
+		}
+		n, err := dbi.deleteTransactions(ctx, batch)
+		archived += n
+		if err != nil {
+			return archived, fmt.Errorf("failed to delete archived transactions: %w", err)
+		}
+		if len(batch) < archiveBatchSize {
+			return archived, nil
+		}
+	}
+}
+
+// transactionsOlderThan returns up to limit of the oldest transactions created before cutoff.
+func (dbi *DBIntegration) transactionsOlderThan(ctx context.Context, cutoff time.Time, limit int) ([]TransactionRecord, error) {
+	query := fmt.Sprintf(`
+		SELECT id, submitter, status, created_at, confirmed_at, block_number, block_hash, data
+		FROM blockchain_transactions
+		WHERE created_at < %s
+		ORDER BY created_at
+		LIMIT %s
+	`, dbi.placeholder(1), dbi.placeholder(2))
+	rows, err := dbi.db.QueryContext(ctx, query, cutoff.UTC(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []TransactionRecord
+	for rows.Next() {
+		var tx TransactionRecord
+		if err := rows.Scan(&tx.ID, &tx.Submitter, &tx.Status, &tx.CreatedAt, &tx.ConfirmedAt, &tx.BlockNumber, &tx.BlockHash, &tx.Data); err != nil {
+			return nil, err
+		}
+		out = append(out, tx)
+	}
+	return out, rows.Err()
+}
+
+// copyTransactions inserts batch in one transaction, ignoring rows that already exist.
+// Postgres streams the batch with COPY into a staging table; MySQL and SQLite use a
+// single multi-row INSERT.
+func (dbi *DBIntegration) copyTransactions(ctx context.Context, batch []TransactionRecord) error {
+	tx, err := dbi.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if dbi.driver == "postgres" {
+		if _, err := tx.ExecContext(ctx, `CREATE TEMP TABLE archive_staging (LIKE blockchain_transactions) ON COMMIT DROP`); err != nil {
+			return err
+		}
+		stmt, err := tx.PrepareContext(ctx, pq.CopyIn("archive_staging", transactionColumns...))
+		if err != nil {
+			return err
+		}
+		for _, r := range batch {
+			if _, err := stmt.ExecContext(ctx, r.ID, r.Submitter, r.Status, r.CreatedAt, r.ConfirmedAt, r.BlockNumber, r.BlockHash, r.Data); err != nil {
+				stmt.Close()
+				return err
+			}
+		}
+		if _, err := stmt.ExecContext(ctx); err != nil { // flush the COPY
+			stmt.Close()
+			return err
+		}
+		if err := stmt.Close(); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO blockchain_transactions SELECT * FROM archive_staging ON CONFLICT (id) DO NOTHING`); err != nil {
+			return err
+		}
+		return tx.Commit()
+	}
+
+	verb := "INSERT OR IGNORE"
+	if dbi.driver == "mysql" {
+		verb = "INSERT IGNORE"
+	}
+	rowsSQL := make([]string, len(batch))
+	args := make([]interface{}, 0, len(batch)*len(transactionColumns))
+	for i, r := range batch {
+		rowsSQL[i] = "(?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args, r.ID, r.Submitter, r.Status, r.CreatedAt, r.ConfirmedAt, r.BlockNumber, r.BlockHash, r.Data)
+	}
+	query := fmt.Sprintf("%s INTO blockchain_transactions (%s) VALUES %s", verb, strings.Join(transactionColumns, ", "), strings.Join(rowsSQL, ", "))
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// deleteTransactions removes the batch's rows by ID and returns how many were deleted.
+func (dbi *DBIntegration) deleteTransactions(ctx context.Context, batch []TransactionRecord) (int64, error) {
+	markers := make([]string, len(batch))
+	args := make([]interface{}, len(batch))
+	for i, r := range batch {
+		markers[i] = dbi.placeholder(i + 1)
+		args[i] = r.ID
+	}
+	res, err := dbi.db.ExecContext(ctx, "DELETE FROM blockchain_transactions WHERE id IN ("+strings.Join(markers, ", ")+")", args...)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
\ No newline at end of file
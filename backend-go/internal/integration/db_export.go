@@ -0,0 +1,94 @@
+package integration
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ExportFormat selects the encoding written by ExportTransactions.
+type ExportFormat string
+
+const (
+	ExportCSV  ExportFormat = "csv"
+	ExportJSON ExportFormat = "json"
+)
+
+// ExportTransactions writes every transaction matching filter to w, oldest first.
+// Rows are streamed as they are read, so the result set is never held in memory.
+// CSV output starts with a header row of the TransactionRecord column names; JSON
+// output is a single array of TransactionRecord objects.
+func (dbi *DBIntegration) ExportTransactions(ctx context.Context, filter TransactionFilter, format ExportFormat, w io.Writer) error {
+	if format != ExportCSV && format != ExportJSON {
+		return fmt.Errorf("unsupported export format: %s", format)
+	}
+	query := `
+		SELECT id, submitter, status, created_at, confirmed_at, block_number, block_hash, data
+		FROM blockchain_transactions
+	`
+	where, args := dbi.whereClause(filter)
+	query += where + " ORDER BY created_at"
+
+	rows, err := dbi.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var (
+		cw    *csv.Writer
+		first = true
+	)
+	if format == ExportCSV {
+		cw = csv.NewWriter(w)
+		if err := cw.Write(transactionColumns); err != nil {
+			return err
+		}
+	} else if _, err := io.WriteString(w, "["); err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		var tx TransactionRecord
+		if err := rows.Scan(&tx.ID, &tx.Submitter, &tx.Status, &tx.CreatedAt, &tx.ConfirmedAt, &tx.BlockNumber, &tx.BlockHash, &tx.Data); err != nil {
+			return err
+		}
+		if cw != nil {
+			if err := cw.Write([]string{
+				tx.ID, tx.Submitter, tx.Status,
+				tx.CreatedAt.UTC().Format(time.RFC3339), tx.ConfirmedAt.UTC().Format(time.RFC3339),
+				strconv.FormatUint(tx.BlockNumber, 10), tx.BlockHash, tx.Data,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+		b, err := json.Marshal(tx)
+		if err != nil {
+			return fmt.Errorf("failed to marshal transaction %s: %w", tx.ID, err)
+		}
+		if !first {
+			if _, err := io.WriteString(w, ","); err != nil {
+				return err
+			}
+		}
+		first = false
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if cw != nil {
+		cw.Flush()
+		return cw.Error()
+	}
+	_, err = io.WriteString(w, "]")
+	return err
+}
\ No newline at end of file
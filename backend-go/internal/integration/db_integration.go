@@ -4,6 +4,8 @@ import (
     "context"
     "database/sql"
     "fmt"
+    "log/slog"
+    "strings"
     "time"
 
     _ "github.com/lib/pq"
@@ -14,6 +16,16 @@ import (
 type DBIntegration struct {
 	db     *sql.DB
 	driver string
+
+	// Optional asynchronous search indexing, see EnableSearchIndexing
+	searchQueue chan TransactionRecord
+	searchDone  chan struct{}
+}
+
+// queryer is the read side of *sql.DB, so read queries can run against a primary or a replica
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
 // Config holds database configuration
@@ -51,10 +63,44 @@ type AccountRecord struct {
 	Balance     string    `json:"balance" db:"balance"`
 	Nonce       uint64    `json:"nonce" db:"nonce"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+	BlockNumber uint64    `json:"block_number,omitempty" db:"block_number"` // Only set for history snapshots
+}
+
+// ContractEvent represents an event emitted by a smart contract during transaction execution
+type ContractEvent struct {
+	EventID     string    `json:"event_id" db:"event_id"`
+	ContractID  string    `json:"contract_id" db:"contract_id"`
+	BlockNumber uint64    `json:"block_number" db:"block_number"`
+	TxID        string    `json:"tx_id" db:"tx_id"`
+	Topic       string    `json:"topic" db:"topic"`
+	Data        string    `json:"data" db:"data"` // JSON-encoded event payload
+	Timestamp   time.Time `json:"timestamp" db:"timestamp"`
+}
+
+// ContractRecord tracks an active or archived contract and the parties on it
+type ContractRecord struct {
+	ContractID   string    `json:"contract_id" db:"contract_id"`
+	TemplateID   string    `json:"template_id" db:"template_id"`
+	Owner        string    `json:"owner" db:"owner"`
+	Counterparty string    `json:"counterparty,omitempty" db:"counterparty"`
+	Status       string    `json:"status" db:"status"`
+	CreatedAt    time.Time `json:"created_at" db:"created_at"`
 }
 
 // NewDBIntegration creates a new database integration instance
 func NewDBIntegration(config Config) (*DBIntegration, error) {
+	db, err := openDB(config)
+	if err != nil {
+		return nil, err
+	}
+	return &DBIntegration{
+		db:     db,
+		driver: config.Driver,
+	}, nil
+}
+
+// openDB opens a connection pool and verifies it with a ping
+func openDB(config Config) (*sql.DB, error) {
 	db, err := sql.Open(config.Driver, config.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
@@ -71,20 +117,44 @@ func NewDBIntegration(config Config) (*DBIntegration, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	if err := db.PingContext(ctx); err != nil {
+		db.Close()
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
-
-	return &DBIntegration{
-		db:     db,
-		driver: config.Driver,
-	}, nil
+	return db, nil
 }
 
-// Close closes the database connection
+// Close stops search indexing (after draining queued records) and closes the database connection
 func (dbi *DBIntegration) Close() error {
+	if dbi.searchQueue != nil {
+		close(dbi.searchQueue)
+		<-dbi.searchDone
+		dbi.searchQueue = nil
+	}
 	return dbi.db.Close()
 }
 
+// EnableSearchIndexing indexes every successfully inserted transaction in Elasticsearch.
+// Indexing runs on a worker goroutine fed by a channel of the given size, so inserts never
+// wait on Elasticsearch; records are dropped (and logged) when the buffer is full.
+// Call it once, before the DBIntegration is shared between goroutines.
+func (dbi *DBIntegration) EnableSearchIndexing(es *ElasticsearchIntegration, buffer int) {
+	if buffer <= 0 {
+		buffer = 1024
+	}
+	dbi.searchQueue = make(chan TransactionRecord, buffer)
+	dbi.searchDone = make(chan struct{})
+	go func(queue <-chan TransactionRecord) {
+		defer close(dbi.searchDone)
+		for tx := range queue {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			if err := es.IndexTransaction(ctx, tx); err != nil {
+				slog.Error("search indexing failed", "tx_id", tx.ID, "error", err)
+			}
+			cancel()
+		}
+	}(dbi.searchQueue)
+}
+
 // InitializeSchema creates the necessary tables for blockchain data
 func (dbi *DBIntegration) InitializeSchema(ctx context.Context) error {
 	var schema string
@@ -107,9 +177,15 @@ func (dbi *DBIntegration) InitializeSchema(ctx context.Context) error {
 func (dbi *DBIntegration) InsertTransaction(ctx context.Context, tx TransactionRecord) error {
 	query := `
 		INSERT INTO blockchain_transactions (id, submitter, status, created_at, confirmed_at, block_number, block_hash, data)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-	`
+		VALUES ` + dbi.valuesList(8)
 	_, err := dbi.db.ExecContext(ctx, query, tx.ID, tx.Submitter, tx.Status, tx.CreatedAt, tx.ConfirmedAt, tx.BlockNumber, tx.BlockHash, tx.Data)
+	if err == nil && dbi.searchQueue != nil {
+		select {
+		case dbi.searchQueue <- tx:
+		default:
+			slog.Warn("search indexing queue full, dropping transaction", "tx_id", tx.ID)
+		}
+	}
 	return err
 }
 
@@ -117,22 +193,24 @@ func (dbi *DBIntegration) InsertTransaction(ctx context.Context, tx TransactionR
 func (dbi *DBIntegration) UpdateTransactionStatus(ctx context.Context, txID, status string, confirmedAt time.Time, blockNumber uint64, blockHash string) error {
 	query := `
 		UPDATE blockchain_transactions 
-		SET status = $1, confirmed_at = $2, block_number = $3, block_hash = $4
-		WHERE id = $5
-	`
+		SET status = ` + dbi.placeholder(1) + `, confirmed_at = ` + dbi.placeholder(2) + `, block_number = ` + dbi.placeholder(3) + `, block_hash = ` + dbi.placeholder(4) + `
+		WHERE id = ` + dbi.placeholder(5)
 	_, err := dbi.db.ExecContext(ctx, query, status, confirmedAt, blockNumber, blockHash, txID)
 	return err
 }
 
 // GetTransaction retrieves a transaction by ID
 func (dbi *DBIntegration) GetTransaction(ctx context.Context, txID string) (*TransactionRecord, error) {
+	return dbi.getTransaction(ctx, dbi.db, txID)
+}
+
+func (dbi *DBIntegration) getTransaction(ctx context.Context, q queryer, txID string) (*TransactionRecord, error) {
 	var tx TransactionRecord
 	query := `
 		SELECT id, submitter, status, created_at, confirmed_at, block_number, block_hash, data
 		FROM blockchain_transactions
-		WHERE id = $1
-	`
-	err := dbi.db.QueryRowContext(ctx, query, txID).Scan(
+		WHERE id = ` + dbi.placeholder(1)
+	err := q.QueryRowContext(ctx, query, txID).Scan(
 		&tx.ID, &tx.Submitter, &tx.Status, &tx.CreatedAt, &tx.ConfirmedAt, &tx.BlockNumber, &tx.BlockHash, &tx.Data,
 	)
 	if err != nil {
@@ -144,23 +222,84 @@ func (dbi *DBIntegration) GetTransaction(ctx context.Context, txID string) (*Tra
 	return &tx, nil
 }
 
-// ListTransactions lists transactions with optional filters
-func (dbi *DBIntegration) ListTransactions(ctx context.Context, limit, offset int, status string) ([]TransactionRecord, error) {
+// TransactionFilter holds optional filters for listing transactions; zero values are ignored
+type TransactionFilter struct {
+	Status         string
+	Submitter      string
+	FromTime       *time.Time
+	ToTime         *time.Time
+	MinBlockNumber *uint64
+	MaxBlockNumber *uint64
+}
+
+// placeholder returns the positional parameter marker for the nth argument (1-based)
+func (dbi *DBIntegration) placeholder(n int) string {
+	if dbi.driver == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// valuesList returns a parenthesised list of n positional parameter markers
+func (dbi *DBIntegration) valuesList(n int) string {
+	markers := make([]string, n)
+	for i := range markers {
+		markers[i] = dbi.placeholder(i + 1)
+	}
+	return "(" + strings.Join(markers, ", ") + ")"
+}
+
+// whereClause builds a WHERE clause and its arguments from the non-empty filter fields
+func (dbi *DBIntegration) whereClause(filter TransactionFilter) (string, []interface{}) {
+	var conditions []string
+	args := []interface{}{}
+	add := func(cond string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, cond+" "+dbi.placeholder(len(args)))
+	}
+
+	if filter.Status != "" {
+		add("status =", filter.Status)
+	}
+	if filter.Submitter != "" {
+		add("submitter =", filter.Submitter)
+	}
+	if filter.FromTime != nil {
+		add("created_at >=", filter.FromTime.UTC())
+	}
+	if filter.ToTime != nil {
+		add("created_at <=", filter.ToTime.UTC())
+	}
+	if filter.MinBlockNumber != nil {
+		add("block_number >=", *filter.MinBlockNumber)
+	}
+	if filter.MaxBlockNumber != nil {
+		add("block_number <=", *filter.MaxBlockNumber)
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// ListTransactions lists transactions matching the filter, newest first
+func (dbi *DBIntegration) ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]TransactionRecord, error) {
+	return dbi.listTransactions(ctx, dbi.db, filter, limit, offset)
+}
+
+func (dbi *DBIntegration) listTransactions(ctx context.Context, q queryer, filter TransactionFilter, limit, offset int) ([]TransactionRecord, error) {
 	query := `
 		SELECT id, submitter, status, created_at, confirmed_at, block_number, block_hash, data
 		FROM blockchain_transactions
 	`
-	args := []interface{}{}
-	
-	if status != "" {
-		query += " WHERE status = $1"
-		args = append(args, status)
-	}
-	
-	query += " ORDER BY created_at DESC LIMIT $2 OFFSET $3"
+	where, args := dbi.whereClause(filter)
+	query += where
+
+	query += fmt.Sprintf(" ORDER BY created_at DESC LIMIT %s OFFSET %s", dbi.placeholder(len(args)+1), dbi.placeholder(len(args)+2))
 	args = append(args, limit, offset)
 	
-	rows, err := dbi.db.QueryContext(ctx, query, args...)
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -183,21 +322,23 @@ func (dbi *DBIntegration) ListTransactions(ctx context.Context, limit, offset in
 func (dbi *DBIntegration) InsertBlock(ctx context.Context, block BlockRecord) error {
 	query := `
 		INSERT INTO blockchain_blocks (number, hash, parent_hash, timestamp, transaction_count, data)
-		VALUES ($1, $2, $3, $4, $5, $6)
-	`
+		VALUES ` + dbi.valuesList(6)
 	_, err := dbi.db.ExecContext(ctx, query, block.Number, block.Hash, block.ParentHash, block.Timestamp, block.TransactionCount, block.Data)
 	return err
 }
 
 // GetBlockByNumber retrieves a block by number
 func (dbi *DBIntegration) GetBlockByNumber(ctx context.Context, number uint64) (*BlockRecord, error) {
+	return dbi.getBlockByNumber(ctx, dbi.db, number)
+}
+
+func (dbi *DBIntegration) getBlockByNumber(ctx context.Context, q queryer, number uint64) (*BlockRecord, error) {
 	var block BlockRecord
 	query := `
 		SELECT number, hash, parent_hash, timestamp, transaction_count, data
 		FROM blockchain_blocks
-		WHERE number = $1
-	`
-	err := dbi.db.QueryRowContext(ctx, query, number).Scan(
+		WHERE number = ` + dbi.placeholder(1)
+	err := q.QueryRowContext(ctx, query, number).Scan(
 		&block.Number, &block.Hash, &block.ParentHash, &block.Timestamp, &block.TransactionCount, &block.Data,
 	)
 	if err != nil {
@@ -211,13 +352,16 @@ func (dbi *DBIntegration) GetBlockByNumber(ctx context.Context, number uint64) (
 
 // GetBlockByHash retrieves a block by hash
 func (dbi *DBIntegration) GetBlockByHash(ctx context.Context, hash string) (*BlockRecord, error) {
+	return dbi.getBlockByHash(ctx, dbi.db, hash)
+}
+
+func (dbi *DBIntegration) getBlockByHash(ctx context.Context, q queryer, hash string) (*BlockRecord, error) {
 	var block BlockRecord
 	query := `
 		SELECT number, hash, parent_hash, timestamp, transaction_count, data
 		FROM blockchain_blocks
-		WHERE hash = $1
-	`
-	err := dbi.db.QueryRowContext(ctx, query, hash).Scan(
+		WHERE hash = ` + dbi.placeholder(1)
+	err := q.QueryRowContext(ctx, query, hash).Scan(
 		&block.Number, &block.Hash, &block.ParentHash, &block.Timestamp, &block.TransactionCount, &block.Data,
 	)
 	if err != nil {
@@ -229,27 +373,82 @@ func (dbi *DBIntegration) GetBlockByHash(ctx context.Context, hash string) (*Blo
 	return &block, nil
 }
 
+// ListBlocks pages through blocks by number. With order "asc" it returns blocks numbered
+// fromSlot and above; with "desc" (the default) blocks numbered fromSlot and below, so pass
+// math.MaxInt64 to start at the head.
+func (dbi *DBIntegration) ListBlocks(ctx context.Context, fromSlot uint64, limit int, order string) ([]BlockRecord, error) {
+	return dbi.listBlocks(ctx, dbi.db, fromSlot, limit, order)
+}
+
+func (dbi *DBIntegration) listBlocks(ctx context.Context, q queryer, fromSlot uint64, limit int, order string) ([]BlockRecord, error) {
+	cond, dir := "number <= ", "DESC"
+	if order == "asc" {
+		cond, dir = "number >= ", "ASC"
+	}
+	query := `SELECT number, hash, parent_hash, timestamp, transaction_count, data
+		FROM blockchain_blocks
+		WHERE ` + cond + dbi.placeholder(1) + `
+		ORDER BY number ` + dir + `
+		LIMIT ` + dbi.placeholder(2)
+
+	rows, err := q.QueryContext(ctx, query, fromSlot, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []BlockRecord
+	for rows.Next() {
+		var b BlockRecord
+		if err := rows.Scan(&b.Number, &b.Hash, &b.ParentHash, &b.Timestamp, &b.TransactionCount, &b.Data); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, b)
+	}
+	return blocks, rows.Err()
+}
+
 // UpdateAccountBalance updates an account's balance
 func (dbi *DBIntegration) UpdateAccountBalance(ctx context.Context, address, balance string) error {
-	query := `
-		INSERT INTO blockchain_accounts (address, balance, nonce, updated_at)
-		VALUES ($1, $2, 0, $3)
-		ON CONFLICT (address) DO UPDATE
-		SET balance = $2, updated_at = $3
-	`
+	var query string
+	switch dbi.driver {
+	case "mysql":
+		query = `
+			INSERT INTO blockchain_accounts (address, balance, nonce, updated_at)
+			VALUES (?, ?, 0, ?)
+			ON DUPLICATE KEY UPDATE balance = VALUES(balance), updated_at = VALUES(updated_at)
+		`
+	case "sqlite3":
+		query = `
+			INSERT INTO blockchain_accounts (address, balance, nonce, updated_at)
+			VALUES (?, ?, 0, ?)
+			ON CONFLICT (address) DO UPDATE
+			SET balance = excluded.balance, updated_at = excluded.updated_at
+		`
+	default:
+		query = `
+			INSERT INTO blockchain_accounts (address, balance, nonce, updated_at)
+			VALUES ($1, $2, 0, $3)
+			ON CONFLICT (address) DO UPDATE
+			SET balance = $2, updated_at = $3
+		`
+	}
 	_, err := dbi.db.ExecContext(ctx, query, address, balance, time.Now().UTC())
 	return err
 }
 
 // GetAccount retrieves an account by address
 func (dbi *DBIntegration) GetAccount(ctx context.Context, address string) (*AccountRecord, error) {
+	return dbi.getAccount(ctx, dbi.db, address)
+}
+
+func (dbi *DBIntegration) getAccount(ctx context.Context, q queryer, address string) (*AccountRecord, error) {
 	var account AccountRecord
 	query := `
 		SELECT address, balance, nonce, updated_at
 		FROM blockchain_accounts
-		WHERE address = $1
-	`
-	err := dbi.db.QueryRowContext(ctx, query, address).Scan(
+		WHERE address = ` + dbi.placeholder(1)
+	err := q.QueryRowContext(ctx, query, address).Scan(
 		&account.Address, &account.Balance, &account.Nonce, &account.UpdatedAt,
 	)
 	if err != nil {
@@ -261,6 +460,182 @@ func (dbi *DBIntegration) GetAccount(ctx context.Context, address string) (*Acco
 	return &account, nil
 }
 
+// InsertAccountSnapshot appends an account state to the balance history without touching the current balance row
+func (dbi *DBIntegration) InsertAccountSnapshot(ctx context.Context, snapshot AccountRecord, blockNumber uint64) error {
+	timestamp := snapshot.UpdatedAt
+	if timestamp.IsZero() {
+		timestamp = time.Now().UTC()
+	}
+	query := `
+		INSERT INTO blockchain_account_history (address, balance, nonce, block_number, timestamp)
+		VALUES ` + dbi.valuesList(5)
+	_, err := dbi.db.ExecContext(ctx, query, snapshot.Address, snapshot.Balance, snapshot.Nonce, blockNumber, timestamp)
+	return err
+}
+
+// GetAccountHistory retrieves the balance history of an account, newest first
+func (dbi *DBIntegration) GetAccountHistory(ctx context.Context, address string, limit, offset int) ([]AccountRecord, error) {
+	query := `
+		SELECT address, balance, nonce, block_number, timestamp
+		FROM blockchain_account_history
+		WHERE address = ` + dbi.placeholder(1) + `
+		ORDER BY block_number DESC, timestamp DESC
+		LIMIT ` + dbi.placeholder(2) + ` OFFSET ` + dbi.placeholder(3)
+	rows, err := dbi.db.QueryContext(ctx, query, address, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var history []AccountRecord
+	for rows.Next() {
+		var account AccountRecord
+		if err := rows.Scan(&account.Address, &account.Balance, &account.Nonce, &account.BlockNumber, &account.UpdatedAt); err != nil {
+			return nil, err
+		}
+		history = append(history, account)
+	}
+
+	return history, rows.Err()
+}
+
+// InsertContractEvent stores a contract event; re-inserting the same event ID is a no-op.
+// Block processors call this for every event found in a processed block.
+func (dbi *DBIntegration) InsertContractEvent(ctx context.Context, event ContractEvent) error {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+	if event.Data == "" {
+		event.Data = "{}"
+	}
+	// Topics are stored as lowercase hex without 0x so lookups match regardless of input form
+	event.Topic = strings.ToLower(strings.TrimPrefix(event.Topic, "0x"))
+	var query string
+	switch dbi.driver {
+	case "mysql":
+		query = `INSERT IGNORE INTO contract_events (event_id, contract_id, block_number, tx_id, topic, data, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`
+	case "sqlite3":
+		query = `INSERT OR IGNORE INTO contract_events (event_id, contract_id, block_number, tx_id, topic, data, timestamp)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`
+	default:
+		query = `INSERT INTO contract_events (event_id, contract_id, block_number, tx_id, topic, data, timestamp)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)
+			ON CONFLICT (event_id) DO NOTHING`
+	}
+	_, err := dbi.db.ExecContext(ctx, query, event.EventID, event.ContractID, event.BlockNumber, event.TxID, event.Topic, event.Data, event.Timestamp)
+	return err
+}
+
+// GetContractEvents lists a contract's events in block order. topic filters when non-empty;
+// toBlock of 0 means no upper bound.
+func (dbi *DBIntegration) GetContractEvents(ctx context.Context, contractID, topic string, fromBlock, toBlock uint64, limit int) ([]ContractEvent, error) {
+	conds := []string{"contract_id = " + dbi.placeholder(1), "block_number >= " + dbi.placeholder(2)}
+	args := []interface{}{contractID, fromBlock}
+	if toBlock > 0 {
+		args = append(args, toBlock)
+		conds = append(conds, "block_number <= "+dbi.placeholder(len(args)))
+	}
+	if topic != "" {
+		args = append(args, topic)
+		conds = append(conds, "topic = "+dbi.placeholder(len(args)))
+	}
+	args = append(args, limit)
+	query := `SELECT event_id, contract_id, block_number, tx_id, topic, data, timestamp
+		FROM contract_events
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY block_number ASC, timestamp ASC
+		LIMIT ` + dbi.placeholder(len(args))
+
+	rows, err := dbi.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []ContractEvent
+	for rows.Next() {
+		var e ContractEvent
+		if err := rows.Scan(&e.EventID, &e.ContractID, &e.BlockNumber, &e.TxID, &e.Topic, &e.Data, &e.Timestamp); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+// InsertContractRecord stores a contract, or updates its status if it is already known
+func (dbi *DBIntegration) InsertContractRecord(ctx context.Context, rec ContractRecord) error {
+	if rec.Status == "" {
+		rec.Status = "active"
+	}
+	if rec.CreatedAt.IsZero() {
+		rec.CreatedAt = time.Now().UTC()
+	}
+	var query string
+	switch dbi.driver {
+	case "mysql":
+		query = `INSERT INTO contract_records (contract_id, template_id, owner, counterparty, status, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE status = VALUES(status)`
+	case "sqlite3":
+		query = `INSERT INTO contract_records (contract_id, template_id, owner, counterparty, status, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT (contract_id) DO UPDATE SET status = excluded.status`
+	default:
+		query = `INSERT INTO contract_records (contract_id, template_id, owner, counterparty, status, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)
+			ON CONFLICT (contract_id) DO UPDATE SET status = EXCLUDED.status`
+	}
+	_, err := dbi.db.ExecContext(ctx, query, rec.ContractID, rec.TemplateID, rec.Owner, rec.Counterparty, rec.Status, rec.CreatedAt)
+	return err
+}
+
+// ListContractsByOwner lists an owner's contracts, newest first. status filters when non-empty.
+func (dbi *DBIntegration) ListContractsByOwner(ctx context.Context, owner, status string, limit, offset int) ([]ContractRecord, error) {
+	return dbi.listContractsByOwner(ctx, dbi.db, owner, status, limit, offset)
+}
+
+func (dbi *DBIntegration) listContractsByOwner(ctx context.Context, q queryer, owner, status string, limit, offset int) ([]ContractRecord, error) {
+	conds := []string{"owner = " + dbi.placeholder(1)}
+	args := []interface{}{owner}
+	if status != "" {
+		args = append(args, status)
+		conds = append(conds, "status = "+dbi.placeholder(len(args)))
+	}
+	args = append(args, limit, offset)
+	query := `SELECT contract_id, template_id, owner, counterparty, status, created_at
+		FROM contract_records
+		WHERE ` + strings.Join(conds, " AND ") + `
+		ORDER BY created_at DESC
+		LIMIT ` + dbi.placeholder(len(args)-1) + ` OFFSET ` + dbi.placeholder(len(args))
+
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []ContractRecord
+	for rows.Next() {
+		var r ContractRecord
+		if err := rows.Scan(&r.ContractID, &r.TemplateID, &r.Owner, &r.Counterparty, &r.Status, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, r)
+	}
+	return records, rows.Err()
+}
+
+// RecordSagaStep appends one saga step transition to the saga log
+func (dbi *DBIntegration) RecordSagaStep(ctx context.Context, sagaID, saga, step, state, errMsg string) error {
+	query := `
+		INSERT INTO saga_log (saga_id, saga, step, state, error, recorded_at)
+		VALUES ` + dbi.valuesList(6)
+	_, err := dbi.db.ExecContext(ctx, query, sagaID, saga, step, state, errMsg, time.Now().UTC())
+	return err
+}
+
 // Database schemas for different drivers
 const postgresSchema = `
 CREATE TABLE IF NOT EXISTS blockchain_transactions (
@@ -300,6 +675,53 @@ CREATE TABLE IF NOT EXISTS blockchain_accounts (
 
 CREATE INDEX IF NOT EXISTS idx_accounts_balance ON blockchain_accounts(balance);
 CREATE INDEX IF NOT EXISTS idx_accounts_updated_at ON blockchain_accounts(updated_at);
+
+CREATE TABLE IF NOT EXISTS blockchain_account_history (
+	id BIGSERIAL PRIMARY KEY,
+	address TEXT NOT NULL,
+	balance TEXT NOT NULL,
+	nonce BIGINT NOT NULL,
+	block_number BIGINT NOT NULL,
+	timestamp TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_history_address_block ON blockchain_account_history(address, block_number);
+
+CREATE TABLE IF NOT EXISTS contract_events (
+	event_id TEXT PRIMARY KEY,
+	contract_id TEXT NOT NULL,
+	block_number BIGINT NOT NULL,
+	tx_id TEXT NOT NULL,
+	topic TEXT NOT NULL,
+	data JSONB NOT NULL,
+	timestamp TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_events_contract_block ON contract_events(contract_id, block_number);
+CREATE INDEX IF NOT EXISTS idx_contract_events_topic ON contract_events(contract_id, topic, block_number);
+
+CREATE TABLE IF NOT EXISTS contract_records (
+	contract_id TEXT PRIMARY KEY,
+	template_id TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	counterparty TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	created_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_records_owner ON contract_records(owner, status, created_at);
+
+CREATE TABLE IF NOT EXISTS saga_log (
+	id BIGSERIAL PRIMARY KEY,
+	saga_id TEXT NOT NULL,
+	saga TEXT NOT NULL,
+	step TEXT NOT NULL,
+	state TEXT NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	recorded_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_log_saga ON saga_log(saga_id, id);
 `
 
 const mysqlSchema = `
@@ -340,6 +762,53 @@ CREATE TABLE IF NOT EXISTS blockchain_accounts (
 
 CREATE INDEX idx_accounts_balance ON blockchain_accounts(balance);
 CREATE INDEX idx_accounts_updated_at ON blockchain_accounts(updated_at);
+
+CREATE TABLE IF NOT EXISTS blockchain_account_history (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	address VARCHAR(255) NOT NULL,
+	balance VARCHAR(255) NOT NULL,
+	nonce BIGINT UNSIGNED NOT NULL,
+	block_number BIGINT UNSIGNED NOT NULL,
+	timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_account_history_address_block ON blockchain_account_history(address, block_number);
+
+CREATE TABLE IF NOT EXISTS contract_events (
+	event_id VARCHAR(255) PRIMARY KEY,
+	contract_id VARCHAR(255) NOT NULL,
+	block_number BIGINT UNSIGNED NOT NULL,
+	tx_id VARCHAR(255) NOT NULL,
+	topic VARCHAR(255) NOT NULL,
+	data JSON NOT NULL,
+	timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_contract_events_contract_block ON contract_events(contract_id, block_number);
+CREATE INDEX idx_contract_events_topic ON contract_events(contract_id, topic, block_number);
+
+CREATE TABLE IF NOT EXISTS contract_records (
+	contract_id VARCHAR(255) PRIMARY KEY,
+	template_id VARCHAR(255) NOT NULL,
+	owner VARCHAR(255) NOT NULL,
+	counterparty VARCHAR(255) NOT NULL DEFAULT '',
+	status VARCHAR(50) NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_contract_records_owner ON contract_records(owner, status, created_at);
+
+CREATE TABLE IF NOT EXISTS saga_log (
+	id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
+	saga_id VARCHAR(64) NOT NULL,
+	saga VARCHAR(255) NOT NULL,
+	step VARCHAR(255) NOT NULL,
+	state VARCHAR(50) NOT NULL,
+	error TEXT NOT NULL,
+	recorded_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX idx_saga_log_saga ON saga_log(saga_id, id);
 `
 
 const sqliteSchema = `
@@ -347,8 +816,8 @@ CREATE TABLE IF NOT EXISTS blockchain_transactions (
 	id TEXT PRIMARY KEY,
 	submitter TEXT NOT NULL,
 	status TEXT NOT NULL,
-	created_at TEXT NOT NULL,
-	confirmed_at TEXT,
+	created_at TIMESTAMP NOT NULL,
+	confirmed_at TIMESTAMP,
 	block_number INTEGER,
 	block_hash TEXT,
 	data TEXT
@@ -363,7 +832,7 @@ CREATE TABLE IF NOT EXISTS blockchain_blocks (
 	number INTEGER PRIMARY KEY,
 	hash TEXT UNIQUE NOT NULL,
 	parent_hash TEXT NOT NULL,
-	timestamp TEXT NOT NULL,
+	timestamp TIMESTAMP NOT NULL,
 	transaction_count INTEGER NOT NULL,
 	data TEXT
 );
@@ -375,9 +844,56 @@ CREATE TABLE IF NOT EXISTS blockchain_accounts (
 	address TEXT PRIMARY KEY,
 	balance TEXT NOT NULL,
 	nonce INTEGER NOT NULL,
-	updated_at TEXT NOT NULL
+	updated_at TIMESTAMP NOT NULL
 );
 
 CREATE INDEX IF NOT EXISTS idx_accounts_balance ON blockchain_accounts(balance);
 CREATE INDEX IF NOT EXISTS idx_accounts_updated_at ON blockchain_accounts(updated_at);
+
+CREATE TABLE IF NOT EXISTS blockchain_account_history (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	address TEXT NOT NULL,
+	balance TEXT NOT NULL,
+	nonce INTEGER NOT NULL,
+	block_number INTEGER NOT NULL,
+	timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_account_history_address_block ON blockchain_account_history(address, block_number);
+
+CREATE TABLE IF NOT EXISTS contract_events (
+	event_id TEXT PRIMARY KEY,
+	contract_id TEXT NOT NULL,
+	block_number INTEGER NOT NULL,
+	tx_id TEXT NOT NULL,
+	topic TEXT NOT NULL,
+	data TEXT NOT NULL,
+	timestamp TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_events_contract_block ON contract_events(contract_id, block_number);
+CREATE INDEX IF NOT EXISTS idx_contract_events_topic ON contract_events(contract_id, topic, block_number);
+
+CREATE TABLE IF NOT EXISTS contract_records (
+	contract_id TEXT PRIMARY KEY,
+	template_id TEXT NOT NULL,
+	owner TEXT NOT NULL,
+	counterparty TEXT NOT NULL DEFAULT '',
+	status TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_records_owner ON contract_records(owner, status, created_at);
+
+CREATE TABLE IF NOT EXISTS saga_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	saga_id TEXT NOT NULL,
+	saga TEXT NOT NULL,
+	step TEXT NOT NULL,
+	state TEXT NOT NULL,
+	error TEXT NOT NULL DEFAULT '',
+	recorded_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_log_saga ON saga_log(saga_id, id);
 `
\ No newline at end of file
@@ -0,0 +1,248 @@
+//go:build cgo
+
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+)
+
+// newSQLiteDB returns a DBIntegration on a fresh in-memory SQLite database with the schema applied.
+func newSQLiteDB(t *testing.T) *DBIntegration {
+	t.Helper()
+	dbi, err := NewDBIntegration(Config{Driver: "sqlite3", DSN: "file:" + t.Name() + "?mode=memory&cache=shared", MaxConns: 1})
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	t.Cleanup(func() { dbi.Close() })
+	if err := dbi.InitializeSchema(context.Background()); err != nil {
+		t.Fatalf("initialize schema: %v", err)
+	}
+	return dbi
+}
+
+// TestDBIntegrationSQLite runs the driver-specific insert and upsert paths on SQLite,
+// where $N placeholders and Postgres-only upsert syntax used to fail.
+func TestDBIntegrationSQLite(t *testing.T) {
+	testInsertAndUpsert(t, newSQLiteDB(t))
+}
+
+// TestDBIntegrationMySQL runs the same paths on MySQL, where the upsert takes the
+// ON DUPLICATE KEY branch. It is skipped without Docker.
+func TestDBIntegrationMySQL(t *testing.T) {
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	ctr, err := tcmysql.Run(ctx, "mysql:8.4",
+		tcmysql.WithDatabase("garp"),
+		tcmysql.WithUsername("garp"),
+		tcmysql.WithPassword("garp"),
+	)
+	testcontainers.CleanupContainer(t, ctr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// InitializeSchema sends the whole schema in one Exec, and timestamps scan into time.Time
+	dsn, err := ctr.ConnectionString(ctx, "multiStatements=true", "parseTime=true")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dbi, err := NewDBIntegration(Config{Driver: "mysql", DSN: dsn, MaxConns: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { dbi.Close() })
+	if err := dbi.InitializeSchema(ctx); err != nil {
+		t.Fatalf("initialize schema: %v", err)
+	}
+	testInsertAndUpsert(t, dbi)
+}
+
+// testInsertAndUpsert inserts a block and a transaction, checks a duplicate transaction ID is
+// rejected, and updates an account balance twice so the second call takes the upsert branch.
+func testInsertAndUpsert(t *testing.T, dbi *DBIntegration) {
+	t.Helper()
+	ctx := context.Background()
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if err := dbi.InsertBlock(ctx, BlockRecord{Number: 7, Hash: "0x07", ParentHash: "0x06", Timestamp: now, TransactionCount: 1, Data: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+	block, err := dbi.GetBlockByNumber(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block == nil || block.Hash != "0x07" || block.ParentHash != "0x06" || !block.Timestamp.Equal(now) {
+		t.Fatalf("GetBlockByNumber(7) = %+v", block)
+	}
+
+	if err := dbi.InsertTransaction(ctx, TransactionRecord{ID: "tx-1", Submitter: "alice", Status: "pending", CreatedAt: now, ConfirmedAt: now, Data: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+	if err := dbi.InsertTransaction(ctx, TransactionRecord{ID: "tx-1", Submitter: "alice", Status: "pending", CreatedAt: now, ConfirmedAt: now, Data: `{}`}); err == nil {
+		t.Fatal("expected a duplicate transaction ID to be rejected")
+	}
+	tx, err := dbi.GetTransaction(ctx, "tx-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx == nil || tx.Submitter != "alice" || tx.Status != "pending" {
+		t.Fatalf("GetTransaction(tx-1) = %+v", tx)
+	}
+
+	// The second call takes the upsert branch
+	for _, balance := range []string{"100", "250"} {
+		if err := dbi.UpdateAccountBalance(ctx, "alice", balance); err != nil {
+			t.Fatalf("UpdateAccountBalance(%s): %v", balance, err)
+		}
+	}
+	account, err := dbi.GetAccount(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account == nil || account.Balance != "250" {
+		t.Fatalf("GetAccount(alice) = %+v, want balance 250", account)
+	}
+}
+
+func TestAccountHistorySQLite(t *testing.T) {
+	dbi := newSQLiteDB(t)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, balance := range []string{"100", "250", "75"} {
+		snap := AccountRecord{Address: "addr1", Balance: balance, Nonce: uint64(i), UpdatedAt: base.Add(time.Duration(i) * time.Minute)}
+		if err := dbi.InsertAccountSnapshot(ctx, snap, uint64(10+i)); err != nil {
+			t.Fatalf("InsertAccountSnapshot(%d): %v", i, err)
+		}
+	}
+	if err := dbi.InsertAccountSnapshot(ctx, AccountRecord{Address: "addr2", Balance: "1"}, 10); err != nil {
+		t.Fatalf("InsertAccountSnapshot(addr2): %v", err)
+	}
+
+	history, err := dbi.GetAccountHistory(ctx, "addr1", 2, 0)
+	if err != nil {
+		t.Fatalf("GetAccountHistory: %v", err)
+	}
+	if len(history) != 2 {
+		t.Fatalf("got %d snapshots, want 2", len(history))
+	}
+	if history[0].Balance != "75" || history[0].BlockNumber != 12 || history[1].Balance != "250" {
+		t.Fatalf("history not newest first: %+v", history)
+	}
+	if !history[0].UpdatedAt.Equal(base.Add(2 * time.Minute)) {
+		t.Fatalf("timestamp = %v, want %v", history[0].UpdatedAt, base.Add(2*time.Minute))
+	}
+
+	rest, err := dbi.GetAccountHistory(ctx, "addr1", 10, 2)
+	if err != nil {
+		t.Fatalf("GetAccountHistory offset: %v", err)
+	}
+	if len(rest) != 1 || rest[0].Balance != "100" {
+		t.Fatalf("offset page = %+v, want the oldest snapshot", rest)
+	}
+}
+
+func TestArchiveTransactionsSQLite(t *testing.T) {
+	dbi := newSQLiteDB(t)
+	ctx := context.Background()
+	cutoff := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, created := range []time.Time{cutoff.Add(-48 * time.Hour), cutoff.Add(-time.Hour), cutoff.Add(time.Hour)} {
+		tx := TransactionRecord{ID: fmt.Sprintf("tx-%d", i), Submitter: "alice", Status: "confirmed", CreatedAt: created, ConfirmedAt: created, BlockNumber: uint64(i), BlockHash: "0x", Data: `{}`}
+		if err := dbi.InsertTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archiveDSN := filepath.Join(t.TempDir(), "archive.db")
+	n, err := dbi.ArchiveTransactions(ctx, cutoff, archiveDSN)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("archived %d transactions, want 2", n)
+	}
+	// Repeating the run finds nothing left to move
+	if n, err := dbi.ArchiveTransactions(ctx, cutoff, archiveDSN); err != nil || n != 0 {
+		t.Fatalf("second run archived %d, %v; want 0", n, err)
+	}
+
+	left, err := dbi.ListTransactions(ctx, TransactionFilter{}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(left) != 1 || left[0].ID != "tx-2" {
+		t.Fatalf("primary holds %+v, want only tx-2", left)
+	}
+	archive, err := NewDBIntegration(Config{Driver: "sqlite3", DSN: archiveDSN, MaxConns: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer archive.Close()
+	moved, err := archive.ListTransactions(ctx, TransactionFilter{}, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(moved) != 2 {
+		t.Fatalf("archive holds %d transactions, want 2", len(moved))
+	}
+}
+
+func TestExportTransactionsSQLite(t *testing.T) {
+	dbi := newSQLiteDB(t)
+	ctx := context.Background()
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i, status := range []string{"confirmed", "pending", "confirmed"} {
+		created := base.Add(time.Duration(i) * time.Hour)
+		tx := TransactionRecord{ID: fmt.Sprintf("tx-%d", i), Submitter: "alice", Status: status, CreatedAt: created, ConfirmedAt: created, BlockNumber: uint64(i), BlockHash: "0x", Data: `{"a":1}`}
+		if err := dbi.InsertTransaction(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	filter := TransactionFilter{Status: "confirmed"}
+
+	var csvOut bytes.Buffer
+	if err := dbi.ExportTransactions(ctx, filter, ExportCSV, &csvOut); err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(&csvOut).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(transactionColumns, ",") {
+		t.Fatalf("csv export = %q, want header and 2 rows", records)
+	}
+	if records[1][0] != "tx-0" || records[2][0] != "tx-2" || records[2][3] != "2026-01-01T02:00:00Z" || records[2][7] != `{"a":1}` {
+		t.Fatalf("csv rows = %q", records[1:])
+	}
+
+	var jsonOut bytes.Buffer
+	if err := dbi.ExportTransactions(ctx, filter, ExportJSON, &jsonOut); err != nil {
+		t.Fatal(err)
+	}
+	var txs []TransactionRecord
+	if err := json.Unmarshal(jsonOut.Bytes(), &txs); err != nil {
+		t.Fatalf("json export %q: %v", jsonOut.String(), err)
+	}
+	if len(txs) != 2 || txs[0].ID != "tx-0" || txs[1].ID != "tx-2" || !txs[1].CreatedAt.Equal(base.Add(2*time.Hour)) {
+		t.Fatalf("json export = %+v", txs)
+	}
+
+	// An empty result is still a valid array
+	jsonOut.Reset()
+	if err := dbi.ExportTransactions(ctx, TransactionFilter{Status: "failed"}, ExportJSON, &jsonOut); err != nil || jsonOut.String() != "[]" {
+		t.Fatalf("empty export = %q, %v", jsonOut.String(), err)
+	}
+	if err := dbi.ExportTransactions(ctx, filter, "xml", &jsonOut); err == nil {
+		t.Fatal("expected error for unsupported format")
+	}
+}
\ No newline at end of file
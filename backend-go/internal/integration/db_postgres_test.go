@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"garp-backend/internal/storage/storagetest"
+)
+
+// TestChainSchemaAfterMigrations mirrors startup: the storage migrations run first and
+// InitializeSchema then adds the blockchain_* tables the block, archive and export
+// endpoints read.
+func TestChainSchemaAfterMigrations(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	defer cancel()
+	env, err := storagetest.Start(ctx)
+	if errors.Is(err, storagetest.ErrNoDocker) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer env.Close()
+
+	dbi, err := NewDBIntegration(Config{Driver: "postgres", DSN: env.PostgresURL, MaxConns: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dbi.Close()
+	for i := 0; i < 2; i++ {
+		if err := dbi.InitializeSchema(ctx); err != nil {
+			t.Fatalf("InitializeSchema run %d: %v", i+1, err)
+		}
+	}
+
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := dbi.InsertBlock(ctx, BlockRecord{Number: 1, Hash: "0x01", ParentHash: "0x00", Timestamp: now, TransactionCount: 1, Data: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+	blocks, err := dbi.ListBlocks(ctx, 0, 10, "asc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blocks) != 1 || blocks[0].Hash != "0x01" {
+		t.Fatalf("ListBlocks = %+v, want block 0x01", blocks)
+	}
+
+	if err := dbi.InsertTransaction(ctx, TransactionRecord{ID: "tx-1", Submitter: "alice", Status: "confirmed", CreatedAt: now, ConfirmedAt: now, BlockNumber: 1, BlockHash: "0x01", Data: `{}`}); err != nil {
+		t.Fatal(err)
+	}
+	var out bytes.Buffer
+	if err := dbi.ExportTransactions(ctx, TransactionFilter{}, ExportCSV, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "tx-1") {
+		t.Fatalf("export does not contain tx-1:\n%s", out.String())
+	}
+}
\ No newline at end of file
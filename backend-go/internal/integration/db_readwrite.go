@@ -0,0 +1,79 @@
+package integration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// ReadWriteDBIntegration sends lookups to a read replica and everything else to the primary.
+// Writes, schema setup and the methods not overridden here come from the embedded DBIntegration.
+// Replica reads can lag the primary, so read-after-write callers should use Primary().
+type ReadWriteDBIntegration struct {
+	*DBIntegration
+	read *sql.DB
+}
+
+// NewReadWriteDBIntegration connects to the primary (writeCfg) and the replica (readCfg).
+// Both must use the same driver because queries are built once for that dialect.
+func NewReadWriteDBIntegration(writeCfg, readCfg Config) (*ReadWriteDBIntegration, error) {
+	if readCfg.Driver != writeCfg.Driver {
+		return nil, fmt.Errorf("read replica driver %q does not match primary driver %q", readCfg.Driver, writeCfg.Driver)
+	}
+	primary, err := NewDBIntegration(writeCfg)
+	if err != nil {
+		return nil, fmt.Errorf("primary: %w", err)
+	}
+	read, err := openDB(readCfg)
+	if err != nil {
+		primary.Close()
+		return nil, fmt.Errorf("read replica: %w", err)
+	}
+	return &ReadWriteDBIntegration{DBIntegration: primary, read: read}, nil
+}
+
+// Primary returns the primary-only integration, for reads that must see the latest writes
+func (rw *ReadWriteDBIntegration) Primary() *DBIntegration {
+	return rw.DBIntegration
+}
+
+// Close closes the replica and then the primary (draining search indexing first)
+func (rw *ReadWriteDBIntegration) Close() error {
+	return errors.Join(rw.read.Close(), rw.DBIntegration.Close())
+}
+
+// GetTransaction retrieves a transaction by ID from the replica
+func (rw *ReadWriteDBIntegration) GetTransaction(ctx context.Context, txID string) (*TransactionRecord, error) {
+	return rw.getTransaction(ctx, rw.read, txID)
+}
+
+// ListTransactions lists transactions matching the filter from the replica, newest first
+func (rw *ReadWriteDBIntegration) ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]TransactionRecord, error) {
+	return rw.listTransactions(ctx, rw.read, filter, limit, offset)
+}
+
+// GetBlockByNumber retrieves a block by number from the replica
+func (rw *ReadWriteDBIntegration) GetBlockByNumber(ctx context.Context, number uint64) (*BlockRecord, error) {
+	return rw.getBlockByNumber(ctx, rw.read, number)
+}
+
+// GetBlockByHash retrieves a block by hash from the replica
+func (rw *ReadWriteDBIntegration) GetBlockByHash(ctx context.Context, hash string) (*BlockRecord, error) {
+	return rw.getBlockByHash(ctx, rw.read, hash)
+}
+
+// ListBlocks pages through blocks by number on the replica
+func (rw *ReadWriteDBIntegration) ListBlocks(ctx context.Context, fromSlot uint64, limit int, order string) ([]BlockRecord, error) {
+	return rw.listBlocks(ctx, rw.read, fromSlot, limit, order)
+}
+
+// ListContractsByOwner lists an owner's contracts from the replica, newest first
+func (rw *ReadWriteDBIntegration) ListContractsByOwner(ctx context.Context, owner, status string, limit, offset int) ([]ContractRecord, error) {
+	return rw.listContractsByOwner(ctx, rw.read, owner, status, limit, offset)
+}
+
+// GetAccount retrieves an account by address from the replica
+func (rw *ReadWriteDBIntegration) GetAccount(ctx context.Context, address string) (*AccountRecord, error) {
+	return rw.getAccount(ctx, rw.read, address)
+}
\ No newline at end of file
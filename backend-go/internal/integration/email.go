@@ -0,0 +1,144 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"embed"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+//go:embed templates/*.txt
+var emailTemplateFS embed.FS
+
+var emailTemplates = template.Must(template.ParseFS(emailTemplateFS, "templates/*.txt"))
+
+// EmailConfig holds the SMTP relay settings for EmailNotifier.
+type EmailConfig struct {
+	Host     string
+	Port     int    // defaults to 587
+	Username string // leave empty for relays that accept unauthenticated mail
+	Password string
+	From     string
+}
+
+// EmailNotifier sends plain-text notification emails through an SMTP relay. The connection
+// is upgraded with STARTTLS whenever the server offers it, and credentials are never sent
+// over an unencrypted connection.
+type EmailNotifier struct {
+	config EmailConfig
+}
+
+// NewEmailNotifier creates a notifier for the given relay. No connection is made until Send.
+func NewEmailNotifier(config EmailConfig) *EmailNotifier {
+	if config.Port == 0 {
+		config.Port = 587
+	}
+	return &EmailNotifier{config: config}
+}
+
+// Send delivers a plain-text message to every address in to over a single SMTP session.
+func (n *EmailNotifier) Send(ctx context.Context, to []string, subject, body string) error {
+	if len(to) == 0 {
+		return errors.New("email has no recipients")
+	}
+	if strings.ContainsAny(subject, "\r\n") {
+		return errors.New("email subject must be a single line")
+	}
+	// The headers keep display names; the SMTP envelope needs the bare addresses
+	from, err := mail.ParseAddress(n.config.From)
+	if err != nil {
+		return fmt.Errorf("invalid sender address %q", n.config.From)
+	}
+	rcpts := make([]string, len(to))
+	for i, addr := range to {
+		a, err := mail.ParseAddress(addr)
+		if err != nil || strings.ContainsAny(addr, "\r\n") {
+			return fmt.Errorf("invalid email address %q", addr)
+		}
+		rcpts[i] = a.Address
+	}
+
+	addr := net.JoinHostPort(n.config.Host, strconv.Itoa(n.config.Port))
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server %s: %w", addr, err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	c, err := smtp.NewClient(conn, n.config.Host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("SMTP handshake with %s failed: %w", addr, err)
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: n.config.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return fmt.Errorf("STARTTLS with %s failed: %w", addr, err)
+		}
+	}
+	if n.config.Username != "" {
+		// PlainAuth itself refuses to send credentials without TLS unless the server is localhost
+		if err := c.Auth(smtp.PlainAuth("", n.config.Username, n.config.Password, n.config.Host)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("SMTP MAIL FROM rejected: %w", err)
+	}
+	for _, rcpt := range rcpts {
+		if err := c.Rcpt(rcpt); err != nil {
+			return fmt.Errorf("SMTP recipient %s rejected: %w", rcpt, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA rejected: %w", err)
+	}
+	if _, err := w.Write(n.compose(to, subject, body)); err != nil {
+		w.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("SMTP server rejected the message: %w", err)
+	}
+	return c.Quit()
+}
+
+// compose renders the RFC 5322 message with CRLF line endings.
+func (n *EmailNotifier) compose(to []string, subject, body string) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", n.config.From)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\nContent-Type: text/plain; charset=UTF-8\r\nContent-Transfer-Encoding: 8bit\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	return b.Bytes()
+}
+
+// NotifyTransactionFailed emails recipients that transaction txID failed with errMsg.
+func (n *EmailNotifier) NotifyTransactionFailed(ctx context.Context, txID string, errMsg string, recipients []string) error {
+	var body bytes.Buffer
+	err := emailTemplates.ExecuteTemplate(&body, "transaction_failed.txt", struct {
+		TxID  string
+		Error string
+		Time  time.Time
+	}{txID, errMsg, time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("failed to render email: %w", err)
+	}
+	return n.Send(ctx, recipients, "Transaction "+txID+" failed", body.String())
+}
\ No newline at end of file
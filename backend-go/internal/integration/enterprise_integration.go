@@ -6,11 +6,19 @@ import (
     "crypto/tls"
     "encoding/json"
     "fmt"
+    "log/slog"
     "net/http"
+    "net/url"
+    "sync"
+    "sync/atomic"
     "time"
 
+    "github.com/nats-io/nats.go"
     "github.com/streadway/amqp"
     "github.com/go-ldap/ldap/v3"
+    "golang.org/x/sync/errgroup"
+
+    "garp-backend/internal/otel"
 )
 
 // EnterpriseIntegration provides integration with enterprise systems
@@ -36,7 +44,7 @@ func NewEnterpriseIntegration(config EnterpriseConfig) *EnterpriseIntegration {
 	}
 	
 	return &EnterpriseIntegration{
-		httpClient: httpClient,
+		httpClient: otel.HTTPInjectMiddleware(httpClient),
 	}
 }
 
@@ -139,6 +147,42 @@ func (erp *ERPSystem) GetERPTransaction(ctx context.Context, transactionID strin
 	return &transaction, nil
 }
 
+// ERPTransactionUpdate carries the fields UpdateERPTransaction changes on an ERP transaction.
+type ERPTransactionUpdate struct {
+	Status      string `json:"status"`
+	BlockNumber uint64 `json:"block_number,omitempty"`
+	BlockHash   string `json:"block_hash,omitempty"`
+}
+
+// UpdateERPTransaction records a transaction's on-chain status in the ERP system.
+// Zero blockNumber and empty blockHash are omitted so the ERP keeps its current values.
+func (erp *ERPSystem) UpdateERPTransaction(ctx context.Context, id string, status string, blockNumber uint64, blockHash string) error {
+	url := fmt.Sprintf("%s/api/transactions/%s", erp.baseURL, id)
+
+	data, err := json.Marshal(ERPTransactionUpdate{Status: status, BlockNumber: blockNumber, BlockHash: blockHash})
+	if err != nil {
+		return fmt.Errorf("failed to marshal transaction update: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "PATCH", url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+erp.apiKey)
+
+	resp, err := erp.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("ERP API failed with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
 // CRMSystem provides integration with CRM systems
 type CRMSystem struct {
 	baseURL    string
@@ -239,6 +283,104 @@ func (crm *CRMSystem) GetCRMContact(ctx context.Context, contactID string) (*CRM
 	return &contact, nil
 }
 
+// crmSearchLimit caps how many contacts SearchCRMContacts returns.
+const crmSearchLimit = 100
+
+// CRMContactFilter narrows SearchCRMContacts; empty fields are not sent.
+type CRMContactFilter struct {
+	Email   string
+	Company string
+	Name    string
+}
+
+// SearchCRMContacts lists contacts matching filter. An empty filter lists all
+// contacts; either way at most crmSearchLimit are returned.
+func (crm *CRMSystem) SearchCRMContacts(ctx context.Context, filter CRMContactFilter) ([]CRMContact, error) {
+	q := url.Values{}
+	if filter.Email != "" {
+		q.Set("email", filter.Email)
+	}
+	if filter.Company != "" {
+		q.Set("company", filter.Company)
+	}
+	if filter.Name != "" {
+		q.Set("name", filter.Name)
+	}
+	endpoint := fmt.Sprintf("%s/api/contacts", crm.baseURL)
+	if len(q) > 0 {
+		endpoint += "?" + q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+crm.apiKey)
+
+	resp, err := crm.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("CRM API failed with status: %d", resp.StatusCode)
+	}
+
+	var contacts []CRMContact
+	if err := json.NewDecoder(resp.Body).Decode(&contacts); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if len(contacts) > crmSearchLimit {
+		contacts = contacts[:crmSearchLimit]
+	}
+	return contacts, nil
+}
+
+// crmBulkConcurrency bounds the CreateCRMContact calls BulkCreateCRMContacts keeps in flight.
+const crmBulkConcurrency = 5
+
+// BulkCreateResult summarises a BulkCreateCRMContacts run.
+type BulkCreateResult struct {
+	Created int      `json:"created"`
+	Failed  int      `json:"failed"`
+	Errors  []string `json:"errors,omitempty"`
+}
+
+// BulkCreateCRMContacts creates each contact independently, so one failure does not
+// abort the others. Per-contact failures are reported in the result; the returned
+// error is non-nil only when ctx ends before every contact was attempted.
+func (crm *CRMSystem) BulkCreateCRMContacts(ctx context.Context, contacts []CRMContact) (BulkCreateResult, error) {
+	var (
+		mu     sync.Mutex
+		result BulkCreateResult
+	)
+	var g errgroup.Group
+	g.SetLimit(crmBulkConcurrency)
+	for i, contact := range contacts {
+		if ctx.Err() != nil {
+			break
+		}
+		g.Go(func() error {
+			err := crm.CreateCRMContact(ctx, contact)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("contact %d (%s): %v", i, contact.Email, err))
+			} else {
+				result.Created++
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	if result.Created+result.Failed < len(contacts) {
+		return result, ctx.Err()
+	}
+	return result, nil
+}
+
 // LDAPDirectory provides integration with LDAP directories
 type LDAPDirectory struct {
 	server   string
@@ -326,12 +468,24 @@ func (ldapDir *LDAPDirectory) AuthenticateUser(username, password string) (*LDAP
 	return user, nil
 }
 
-// RabbitMQIntegration provides integration with RabbitMQ
+// RabbitMQIntegration provides integration with RabbitMQ. If the broker drops the
+// connection it redials in the background and re-registers active consumers.
 type RabbitMQIntegration struct {
+	channel atomic.Pointer[amqp.Channel]
+
+	mu         sync.Mutex
 	connection *amqp.Connection
-	channel    *amqp.Channel
+	consumers  map[string]func([]byte) error
+	done       chan struct{}
+	closeOnce  sync.Once
 }
 
+// RabbitMQ reconnect backoff bounds.
+const (
+	rabbitMQMinBackoff = time.Second
+	rabbitMQMaxBackoff = 30 * time.Second
+)
+
 // NewRabbitMQIntegration creates a new RabbitMQ integration instance
 func (ei *EnterpriseIntegration) NewRabbitMQIntegration(amqpURI string) (*RabbitMQIntegration, error) {
 	// Connect to RabbitMQ
@@ -347,18 +501,88 @@ func (ei *EnterpriseIntegration) NewRabbitMQIntegration(amqpURI string) (*Rabbit
 		return nil, fmt.Errorf("failed to open channel: %w", err)
 	}
 	
-	return &RabbitMQIntegration{
+	rmq := &RabbitMQIntegration{
 		connection: conn,
-		channel:    ch,
-	}, nil
+		consumers:  make(map[string]func([]byte) error),
+		done:       make(chan struct{}),
+	}
+	rmq.channel.Store(ch)
+	go rmq.startReconnectLoop(amqpURI)
+	return rmq, nil
+}
+
+// startReconnectLoop waits for the current connection to drop and redials with
+// exponential backoff, then swaps in a fresh channel and restarts every consumer.
+// It returns once Close is called.
+func (rmq *RabbitMQIntegration) startReconnectLoop(amqpURI string) {
+	for {
+		rmq.mu.Lock()
+		closed := rmq.connection.NotifyClose(make(chan *amqp.Error, 1))
+		rmq.mu.Unlock()
+
+		select {
+		case <-rmq.done:
+			return
+		case reason := <-closed:
+			if reason == nil {
+				return // closed by us
+			}
+			slog.Warn("RabbitMQ connection closed; reconnecting", "reason", reason)
+		}
+
+		backoff := rabbitMQMinBackoff
+		for {
+			conn, ch, err := dialRabbitMQ(amqpURI)
+			if err == nil {
+				rmq.mu.Lock()
+				rmq.connection = conn
+				rmq.channel.Store(ch)
+				for queue, handler := range rmq.consumers {
+					if err := rmq.consume(ch, queue, handler); err != nil {
+						slog.Error("failed to restart RabbitMQ consumer", "queue", queue, "error", err)
+					}
+				}
+				rmq.mu.Unlock()
+				slog.Info("RabbitMQ connection restored")
+				break
+			}
+			slog.Warn("RabbitMQ reconnect failed", "error", err, "retry_in", backoff)
+			select {
+			case <-rmq.done:
+				return
+			case <-time.After(backoff):
+			}
+			if backoff *= 2; backoff > rabbitMQMaxBackoff {
+				backoff = rabbitMQMaxBackoff
+			}
+		}
+	}
 }
 
-// Close closes the RabbitMQ connection
+// dialRabbitMQ opens a connection and a channel on it.
+func dialRabbitMQ(amqpURI string) (*amqp.Connection, *amqp.Channel, error) {
+	conn, err := amqp.Dial(amqpURI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	ch, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	return conn, ch, nil
+}
+
+// Close closes the RabbitMQ connection and stops reconnecting
 func (rmq *RabbitMQIntegration) Close() error {
-	if err := rmq.channel.Close(); err != nil {
+	rmq.closeOnce.Do(func() { close(rmq.done) })
+
+	if err := rmq.channel.Load().Close(); err != nil {
 		return fmt.Errorf("failed to close channel: %w", err)
 	}
 	
+	rmq.mu.Lock()
+	defer rmq.mu.Unlock()
 	if err := rmq.connection.Close(); err != nil {
 		return fmt.Errorf("failed to close connection: %w", err)
 	}
@@ -366,10 +590,11 @@ func (rmq *RabbitMQIntegration) Close() error {
 	return nil
 }
 
-// PublishMessage publishes a message to a RabbitMQ exchange
+// PublishMessage publishes a message to a RabbitMQ exchange on the current channel.
+// While a reconnect is in progress it fails rather than blocking.
 func (rmq *RabbitMQIntegration) PublishMessage(exchange, routingKey string, message []byte) error {
 	// Publish the message
-	err := rmq.channel.Publish(
+	err := rmq.channel.Load().Publish(
 		exchange,   // exchange
 		routingKey, // routing key
 		false,      // mandatory
@@ -386,10 +611,27 @@ func (rmq *RabbitMQIntegration) PublishMessage(exchange, routingKey string, mess
 	return nil
 }
 
-// ConsumeMessages consumes messages from a RabbitMQ queue
+// ConsumeMessages consumes messages from a RabbitMQ queue. The consumer is restarted
+// after a reconnect; the call blocks until Close.
 func (rmq *RabbitMQIntegration) ConsumeMessages(queueName string, handler func([]byte) error) error {
+	rmq.mu.Lock()
+	err := rmq.consume(rmq.channel.Load(), queueName, handler)
+	if err == nil {
+		rmq.consumers[queueName] = handler
+	}
+	rmq.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	
+	<-rmq.done
+	return nil
+}
+
+// consume declares queueName on ch and hands its deliveries to handler until ch closes.
+func (rmq *RabbitMQIntegration) consume(ch *amqp.Channel, queueName string, handler func([]byte) error) error {
 	// Declare the queue
-	q, err := rmq.channel.QueueDeclare(
+	q, err := ch.QueueDeclare(
 		queueName, // name
 		true,      // durable
 		false,     // delete when unused
@@ -402,7 +644,7 @@ func (rmq *RabbitMQIntegration) ConsumeMessages(queueName string, handler func([
 	}
 	
 	// Start consuming messages
-	msgs, err := rmq.channel.Consume(
+	msgs, err := ch.Consume(
 		q.Name, // queue
 		"",     // consumer
 		true,   // auto-ack
@@ -416,22 +658,87 @@ func (rmq *RabbitMQIntegration) ConsumeMessages(queueName string, handler func([
 	}
 	
 	// Process messages
-	forever := make(chan bool)
 	go func() {
 		for d := range msgs {
 			if err := handler(d.Body); err != nil {
 				// Log the error but continue processing
-				fmt.Printf("Error processing message: %v\n", err)
+				slog.Error("failed to process message", "error", err)
 			}
 		}
 	}()
 	
-	// Wait forever
-	<-forever
-	
 	return nil
 }
 
+// NATSIntegration is a lightweight alternative to RabbitMQ for internal service eventing
+type NATSIntegration struct {
+	conn *nats.Conn
+}
+
+// SubjectBlockFinalized is the NATS subject carrying block_finalized events
+const SubjectBlockFinalized = "garp.blocks.finalized"
+
+// NewNATSIntegration connects to a NATS server, reconnecting automatically on failure
+func NewNATSIntegration(url string) (*NATSIntegration, error) {
+	conn, err := nats.Connect(url, nats.Name("garp-backend"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to NATS: %w", err)
+	}
+	return &NATSIntegration{conn: conn}, nil
+}
+
+// Close flushes pending messages and closes the connection
+func (n *NATSIntegration) Close() error {
+	return n.conn.Drain()
+}
+
+// Publish sends data to a subject
+func (n *NATSIntegration) Publish(subject string, data []byte) error {
+	if err := n.conn.Publish(subject, data); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", subject, err)
+	}
+	return nil
+}
+
+// PublishEvent implements EventBus; destination is the NATS subject
+func (n *NATSIntegration) PublishEvent(ctx context.Context, destination string, event BlockchainToEnterpriseEvent) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+	return n.Publish(destination, data)
+}
+
+// Subscribe calls handler for each message on subject until ctx is cancelled. Handler
+// errors are logged and do not stop the subscription; if the message expects a reply
+// the error text is sent back.
+func (n *NATSIntegration) Subscribe(ctx context.Context, subject string, handler func([]byte) error) error {
+	sub, err := n.conn.Subscribe(subject, func(msg *nats.Msg) {
+		if err := handler(msg.Data); err != nil {
+			slog.Error("failed to process NATS message", "subject", msg.Subject, "error", err)
+			if msg.Reply != "" {
+				_ = msg.Respond([]byte(err.Error()))
+			}
+		}
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", subject, err)
+	}
+	<-ctx.Done()
+	return sub.Unsubscribe()
+}
+
+// RequestReply sends data to subject and waits up to timeout (or until ctx is done) for a reply
+func (n *NATSIntegration) RequestReply(ctx context.Context, subject string, data []byte, timeout time.Duration) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	msg, err := n.conn.RequestWithContext(ctx, subject, data)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %w", subject, err)
+	}
+	return msg.Data, nil
+}
+
 // BlockchainToEnterpriseEvent represents an event for enterprise system integration
 type BlockchainToEnterpriseEvent struct {
 	ID          string      `json:"id"`
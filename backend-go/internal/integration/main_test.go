@@ -0,0 +1,18 @@
+package integration
+
+import (
+	"testing"
+
+	"go.uber.org/goleak"
+
+	"garp-backend/internal/storage/storagetest"
+)
+
+// TestMain fails the package if a test leaves goroutines behind, such as a delivery
+// worker or a database pool that was never closed.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m, append(storagetest.LeakOptions(),
+		// Started by an init func of opencensus, which the GCP clients pull in
+		goleak.IgnoreTopFunction("go.opencensus.io/stats/view.(*worker).start"),
+	)...)
+}
\ No newline at end of file
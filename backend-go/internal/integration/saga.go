@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// Saga step states recorded in the saga log.
+const (
+	SagaStepStarted            = "started"
+	SagaStepCompleted          = "completed"
+	SagaStepFailed             = "failed"
+	SagaStepCompensated        = "compensated"
+	SagaStepCompensationFailed = "compensation_failed"
+)
+
+// SagaLog records saga step transitions for auditing. DBIntegration implements it.
+type SagaLog interface {
+	RecordSagaStep(ctx context.Context, sagaID, saga, step, state, errMsg string) error
+}
+
+type sagaStep struct {
+	name       string
+	execute    func(ctx context.Context) error
+	compensate func(ctx context.Context) error
+}
+
+// Saga runs a sequence of calls against independent services as one unit: when a step
+// fails, the steps that already succeeded are undone by their compensations, newest first.
+type Saga struct {
+	ID    string
+	name  string
+	log   SagaLog
+	steps []sagaStep
+}
+
+// NewSaga creates a saga with a random ID. log may be nil to skip auditing.
+func NewSaga(name string, log SagaLog) *Saga {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return &Saga{ID: hex.EncodeToString(b), name: name, log: log}
+}
+
+// AddStep appends a step. compensate may be nil for steps with nothing to undo.
+func (s *Saga) AddStep(name string, execute func(ctx context.Context) error, compensate func(ctx context.Context) error) *Saga {
+	s.steps = append(s.steps, sagaStep{name: name, execute: execute, compensate: compensate})
+	return s
+}
+
+// Execute runs the steps in order. If step N fails, compensations for steps N-1 through 0
+// run in reverse order and the returned error wraps the step failure together with any
+// compensation failures. Compensations run even if ctx has been cancelled.
+func (s *Saga) Execute(ctx context.Context) error {
+	for i, step := range s.steps {
+		s.record(ctx, step.name, SagaStepStarted, nil)
+		err := step.execute(ctx)
+		if err == nil {
+			s.record(ctx, step.name, SagaStepCompleted, nil)
+			continue
+		}
+		s.record(ctx, step.name, SagaStepFailed, err)
+
+		errs := []error{fmt.Errorf("saga %s step %q failed: %w", s.name, step.name, err)}
+		cctx := context.WithoutCancel(ctx)
+		for j := i - 1; j >= 0; j-- {
+			done := s.steps[j]
+			if done.compensate == nil {
+				continue
+			}
+			if cerr := done.compensate(cctx); cerr != nil {
+				s.record(cctx, done.name, SagaStepCompensationFailed, cerr)
+				errs = append(errs, fmt.Errorf("compensating step %q: %w", done.name, cerr))
+				continue
+			}
+			s.record(cctx, done.name, SagaStepCompensated, nil)
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// record is best effort: a saga log outage must not change the saga's outcome.
+func (s *Saga) record(ctx context.Context, step, state string, stepErr error) {
+	var msg string
+	if stepErr != nil {
+		msg = stepErr.Error()
+	}
+	slog.Info("saga step", "saga", s.name, "saga_id", s.ID, "step", step, "state", state, "error", msg)
+	if s.log == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+	defer cancel()
+	if err := s.log.RecordSagaStep(ctx, s.ID, s.name, step, state, msg); err != nil {
+		slog.Warn("failed to record saga step", "saga_id", s.ID, "step", step, "state", state, "error", err)
+	}
+}
\ No newline at end of file
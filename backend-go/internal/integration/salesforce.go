@@ -0,0 +1,258 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// salesforceAPIVersion is used when SalesforceConfig.APIVersion is empty.
+	salesforceAPIVersion = "v59.0"
+	// salesforceSessionTTL is assumed for access tokens, matching the default org
+	// session timeout; the password grant does not return an expiry.
+	salesforceSessionTTL = 2 * time.Hour
+	// salesforceRefreshBuffer renews the token this long before it is assumed to expire.
+	salesforceRefreshBuffer = 2 * time.Minute
+)
+
+// SalesforceConfig holds the connected-app credentials for the OAuth2 username-password flow.
+type SalesforceConfig struct {
+	LoginURL     string // e.g. https://login.salesforce.com or https://test.salesforce.com
+	ClientID     string
+	ClientSecret string
+	Username     string
+	Password     string        // password with the security token appended, if the org requires one
+	APIVersion   string        // defaults to salesforceAPIVersion
+	SessionTTL   time.Duration // defaults to salesforceSessionTTL
+}
+
+// SalesforceSystem provides integration with Salesforce through its REST SObject API
+type SalesforceSystem struct {
+	config     SalesforceConfig
+	httpClient *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	instanceURL string
+	expiresAt   time.Time
+}
+
+// NewSalesforceSystem creates a new Salesforce integration instance. No request is made
+// until the first call, which authenticates.
+func (ei *EnterpriseIntegration) NewSalesforceSystem(config SalesforceConfig) *SalesforceSystem {
+	if config.APIVersion == "" {
+		config.APIVersion = salesforceAPIVersion
+	}
+	if config.SessionTTL <= 0 {
+		config.SessionTTL = salesforceSessionTTL
+	}
+	return &SalesforceSystem{config: config, httpClient: ei.httpClient}
+}
+
+// Authenticate obtains a new access token from the Salesforce OAuth2 token endpoint.
+func (sf *SalesforceSystem) Authenticate(ctx context.Context) error {
+	form := url.Values{
+		"grant_type":    {"password"},
+		"client_id":     {sf.config.ClientID},
+		"client_secret": {sf.config.ClientSecret},
+		"username":      {sf.config.Username},
+		"password":      {sf.config.Password},
+	}
+	endpoint := strings.TrimRight(sf.config.LoginURL, "/") + "/services/oauth2/token"
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := sf.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var oauthErr struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&oauthErr)
+		return fmt.Errorf("Salesforce authentication failed with status %d: %s %s", resp.StatusCode, oauthErr.Error, oauthErr.Description)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		InstanceURL string `json:"instance_url"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("failed to decode token response: %w", err)
+	}
+	if token.AccessToken == "" || token.InstanceURL == "" {
+		return fmt.Errorf("Salesforce token response is missing access_token or instance_url")
+	}
+
+	sf.mu.Lock()
+	sf.accessToken = token.AccessToken
+	sf.instanceURL = strings.TrimRight(token.InstanceURL, "/")
+	sf.expiresAt = time.Now().Add(sf.config.SessionTTL)
+	sf.mu.Unlock()
+	return nil
+}
+
+// session returns a valid access token and instance URL, authenticating first when
+// there is none or it is within salesforceRefreshBuffer of expiring.
+func (sf *SalesforceSystem) session(ctx context.Context) (string, string, error) {
+	sf.mu.Lock()
+	token, instance, fresh := sf.accessToken, sf.instanceURL, time.Now().Add(salesforceRefreshBuffer).Before(sf.expiresAt)
+	sf.mu.Unlock()
+	if token != "" && fresh {
+		return token, instance, nil
+	}
+	if err := sf.Authenticate(ctx); err != nil {
+		return "", "", err
+	}
+	sf.mu.Lock()
+	defer sf.mu.Unlock()
+	return sf.accessToken, sf.instanceURL, nil
+}
+
+// do sends an authenticated request to path (relative to the instance URL) and decodes
+// the JSON response into out when it is non-nil. A 401 means the session was revoked
+// early, so it re-authenticates and retries once.
+func (sf *SalesforceSystem) do(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		token, instance, err := sf.session(ctx)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, instance+path, bytes.NewReader(payload))
+		if err != nil {
+			return fmt.Errorf("failed to create HTTP request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Accept", "application/json")
+		if body != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+
+		resp, err := sf.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send request: %w", err)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			resp.Body.Close()
+			sf.mu.Lock()
+			sf.accessToken = ""
+			sf.mu.Unlock()
+			continue
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("Salesforce API failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		return nil
+	}
+}
+
+// salesforceContact is the subset of the Contact SObject mapped onto CRMContact.
+type salesforceContact struct {
+	ID          string `json:"Id,omitempty"`
+	FirstName   string `json:"FirstName,omitempty"`
+	LastName    string `json:"LastName"`
+	Email       string `json:"Email,omitempty"`
+	Phone       string `json:"Phone,omitempty"`
+	Department  string `json:"Department,omitempty"`
+	CreatedDate string `json:"CreatedDate,omitempty"`
+}
+
+// CreateContact creates a Contact and returns its Salesforce ID. Contacts have no
+// company field of their own, so Company is stored in Department.
+func (sf *SalesforceSystem) CreateContact(ctx context.Context, contact CRMContact) (string, error) {
+	in := salesforceContact{
+		FirstName:  contact.FirstName,
+		LastName:   contact.LastName,
+		Email:      contact.Email,
+		Phone:      contact.Phone,
+		Department: contact.Company,
+	}
+	var result struct {
+		ID      string `json:"id"`
+		Success bool   `json:"success"`
+	}
+	if err := sf.do(ctx, "POST", sf.sobjectPath("Contact"), in, &result); err != nil {
+		return "", err
+	}
+	if !result.Success {
+		return "", fmt.Errorf("Salesforce did not create the contact")
+	}
+	return result.ID, nil
+}
+
+// GetContact retrieves a Contact by Salesforce ID
+func (sf *SalesforceSystem) GetContact(ctx context.Context, contactID string) (*CRMContact, error) {
+	var out salesforceContact
+	path := sf.sobjectPath("Contact") + "/" + url.PathEscape(contactID) + "?fields=Id,FirstName,LastName,Email,Phone,Department,CreatedDate"
+	if err := sf.do(ctx, "GET", path, nil, &out); err != nil {
+		return nil, err
+	}
+	return &CRMContact{
+		ID:        out.ID,
+		FirstName: out.FirstName,
+		LastName:  out.LastName,
+		Email:     out.Email,
+		Phone:     out.Phone,
+		Company:   out.Department,
+		CreatedAt: out.CreatedDate,
+	}, nil
+}
+
+// QuerySOQL runs a SOQL query and returns every matching record, following
+// nextRecordsUrl until the result set is exhausted.
+func (sf *SalesforceSystem) QuerySOQL(ctx context.Context, soql string) ([]map[string]interface{}, error) {
+	var records []map[string]interface{}
+	path := fmt.Sprintf("/services/data/%s/query?q=%s", sf.config.APIVersion, url.QueryEscape(soql))
+	for path != "" {
+		var page struct {
+			Records        []map[string]interface{} `json:"records"`
+			Done           bool                     `json:"done"`
+			NextRecordsURL string                   `json:"nextRecordsUrl"`
+		}
+		if err := sf.do(ctx, "GET", path, nil, &page); err != nil {
+			return nil, err
+		}
+		records = append(records, page.Records...)
+		path = ""
+		if !page.Done {
+			path = page.NextRecordsURL
+		}
+	}
+	return records, nil
+}
+
+func (sf *SalesforceSystem) sobjectPath(object string) string {
+	return fmt.Sprintf("/services/data/%s/sobjects/%s", sf.config.APIVersion, object)
+}
\ No newline at end of file
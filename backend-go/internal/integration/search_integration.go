@@ -0,0 +1,156 @@
+package integration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ElasticsearchIntegration indexes blockchain records in Elasticsearch for full-text search.
+// It talks to the Elasticsearch REST API (document and _search endpoints) directly.
+type ElasticsearchIntegration struct {
+	baseURL    string
+	username   string
+	password   string
+	apiKey     string
+	txIndex    string
+	blockIndex string
+	httpClient *http.Client
+}
+
+// ElasticsearchConfig holds Elasticsearch connection settings
+type ElasticsearchConfig struct {
+	URL              string // e.g. http://elasticsearch:9200
+	Username         string // basic auth (optional)
+	Password         string
+	APIKey           string // base64 API key (optional, takes precedence over basic auth)
+	TransactionIndex string // defaults to "garp-transactions"
+	BlockIndex       string // defaults to "garp-blocks"
+	HTTPTimeout      time.Duration
+}
+
+// NewElasticsearchIntegration creates a client and checks that the cluster is reachable
+func NewElasticsearchIntegration(config ElasticsearchConfig) (*ElasticsearchIntegration, error) {
+	if config.URL == "" {
+		return nil, fmt.Errorf("elasticsearch URL is required")
+	}
+	if config.TransactionIndex == "" {
+		config.TransactionIndex = "garp-transactions"
+	}
+	if config.BlockIndex == "" {
+		config.BlockIndex = "garp-blocks"
+	}
+	if config.HTTPTimeout == 0 {
+		config.HTTPTimeout = 10 * time.Second
+	}
+	es := &ElasticsearchIntegration{
+		baseURL:    strings.TrimRight(config.URL, "/"),
+		username:   config.Username,
+		password:   config.Password,
+		apiKey:     config.APIKey,
+		txIndex:    config.TransactionIndex,
+		blockIndex: config.BlockIndex,
+		httpClient: &http.Client{Timeout: config.HTTPTimeout},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := es.do(ctx, http.MethodGet, "/", nil, nil); err != nil {
+		return nil, fmt.Errorf("failed to reach elasticsearch: %w", err)
+	}
+	return es, nil
+}
+
+// IndexTransaction creates or replaces the search document for a transaction
+func (es *ElasticsearchIntegration) IndexTransaction(ctx context.Context, tx TransactionRecord) error {
+	path := "/" + es.txIndex + "/_doc/" + url.PathEscape(tx.ID)
+	if err := es.do(ctx, http.MethodPut, path, tx, nil); err != nil {
+		return fmt.Errorf("failed to index transaction %s: %w", tx.ID, err)
+	}
+	return nil
+}
+
+// IndexBlock creates or replaces the search document for a block
+func (es *ElasticsearchIntegration) IndexBlock(ctx context.Context, b BlockRecord) error {
+	path := "/" + es.blockIndex + "/_doc/" + strconv.FormatUint(b.Number, 10)
+	if err := es.do(ctx, http.MethodPut, path, b, nil); err != nil {
+		return fmt.Errorf("failed to index block %d: %w", b.Number, err)
+	}
+	return nil
+}
+
+// SearchTransactions runs a multi_match query over submitter, status and data
+func (es *ElasticsearchIntegration) SearchTransactions(ctx context.Context, query string, from, size int) ([]TransactionRecord, error) {
+	body := map[string]interface{}{
+		"from": from,
+		"size": size,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":  query,
+				"fields": []string{"submitter", "status", "data"},
+			},
+		},
+	}
+	var result struct {
+		Hits struct {
+			Hits []struct {
+				Source TransactionRecord `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := es.do(ctx, http.MethodPost, "/"+es.txIndex+"/_search", body, &result); err != nil {
+		return nil, fmt.Errorf("failed to search transactions: %w", err)
+	}
+
+	txs := make([]TransactionRecord, 0, len(result.Hits.Hits))
+	for _, hit := range result.Hits.Hits {
+		txs = append(txs, hit.Source)
+	}
+	return txs, nil
+}
+
+func (es *ElasticsearchIntegration) do(ctx context.Context, method, path string, in interface{}, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, es.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	switch {
+	case es.apiKey != "":
+		req.Header.Set("Authorization", "ApiKey "+es.apiKey)
+	case es.username != "":
+		req.SetBasicAuth(es.username, es.password)
+	}
+
+	resp, err := es.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("elasticsearch returned %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out != nil {
+		return json.NewDecoder(resp.Body).Decode(out)
+	}
+	return nil
+}
\ No newline at end of file
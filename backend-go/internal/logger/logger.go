@@ -0,0 +1,44 @@
+package logger
+
+import (
+    "context"
+    "log/slog"
+    "os"
+    "strings"
+)
+
+type ctxKey struct{}
+
+// New builds a logger writing to stderr. format is "json" or "text" (default).
+func New(level slog.Level, format string) *slog.Logger {
+    opts := &slog.HandlerOptions{Level: level}
+    if strings.EqualFold(format, "json") {
+        return slog.New(slog.NewJSONHandler(os.Stderr, opts))
+    }
+    return slog.New(slog.NewTextHandler(os.Stderr, opts))
+}
+
+// ParseLevel maps "debug", "info", "warn" and "error" to a slog.Level, defaulting to info.
+func ParseLevel(s string) slog.Level {
+    var l slog.Level
+    if err := l.UnmarshalText([]byte(s)); err != nil { return slog.LevelInfo }
+    return l
+}
+
+// WithRequestID returns a context carrying a logger tagged with request_id.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+    return WithLogger(ctx, FromContext(ctx).With("request_id", requestID))
+}
+
+// WithLogger stores l in ctx.
+func WithLogger(ctx context.Context, l *slog.Logger) context.Context {
+    return context.WithValue(ctx, ctxKey{}, l)
+}
+
+// FromContext returns the request-scoped logger, or slog.Default() if none is set.
+func FromContext(ctx context.Context) *slog.Logger {
+    if ctx != nil {
+        if l, ok := ctx.Value(ctxKey{}).(*slog.Logger); ok { return l }
+    }
+    return slog.Default()
+}
\ No newline at end of file
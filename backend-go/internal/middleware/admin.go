@@ -0,0 +1,29 @@
+package middleware
+
+import (
+    "crypto/subtle"
+    "net"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// AdminAuth guards operational endpoints. With a token, requests must send
+// "Authorization: Bearer <token>"; without one, only loopback connections are accepted.
+// The loopback check uses the socket address, never X-Forwarded-For.
+func AdminAuth(token string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if token != "" {
+            got, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+            if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+                c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+                return
+            }
+        } else if ip := net.ParseIP(c.RemoteIP()); ip == nil || !ip.IsLoopback() {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+            return
+        }
+        c.Next()
+    }
+}
\ No newline at end of file
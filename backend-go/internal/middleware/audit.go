@@ -0,0 +1,77 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+
+    "garp-backend/internal/logger"
+    "garp-backend/internal/otel"
+)
+
+// auditTimeout bounds the audit write made after each response.
+const auditTimeout = 3 * time.Second
+
+// auditMaxBody caps the request body AuditLog buffers. Larger bodies, such as uploads, are
+// hashed as the handler reads them instead.
+const auditMaxBody = 1 << 20
+
+// AuditEntry records one state-changing request.
+type AuditEntry struct {
+    Timestamp       time.Time `json:"timestamp"`
+    UserID          string    `json:"user_id,omitempty"`
+    Method          string    `json:"method"`
+    Path            string    `json:"path"`
+    RequestBodyHash string    `json:"request_body_hash"` // hex SHA-256 of the raw request body
+    StatusCode      int       `json:"status_code"`
+    IPAddress       string    `json:"ip_address"`
+}
+
+// AuditStore persists audit entries. Implementations should be append-only.
+type AuditStore interface {
+    Record(ctx context.Context, entry AuditEntry) error
+}
+
+// AuditLog records every POST, PUT, PATCH and DELETE request once the handler has run.
+// Reads are not audited. A failed audit write is logged but does not change the response.
+func AuditLog(store AuditStore) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        switch c.Request.Method {
+        case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+        default:
+            c.Next()
+            return
+        }
+        entry := AuditEntry{
+            Timestamp: time.Now().UTC(),
+            Method:    c.Request.Method,
+            Path:      c.Request.URL.Path,
+            IPAddress: c.ClientIP(),
+        }
+        body := captureBody(c, auditMaxBody)
+        if body == nil {
+            // Rejected before the handler (e.g. 413); still worth an entry
+            entry.StatusCode = c.Writer.Status()
+            recordAudit(c, store, entry)
+            return
+        }
+
+        c.Next()
+
+        entry.RequestBodyHash = body.Sum()
+        entry.UserID = c.GetString(otel.UserIDKey)
+        entry.StatusCode = c.Writer.Status()
+        recordAudit(c, store, entry)
+    }
+}
+
+func recordAudit(c *gin.Context, store AuditStore, entry AuditEntry) {
+    // Record even if the client has gone away
+    ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditTimeout)
+    defer cancel()
+    if err := store.Record(ctx, entry); err != nil {
+        logger.FromContext(ctx).Error("failed to record audit entry", "method", entry.Method, "path", entry.Path, "error", err)
+    }
+}
\ No newline at end of file
@@ -0,0 +1,110 @@
+package middleware
+
+import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+)
+
+type memAuditStore struct {
+    mu      sync.Mutex
+    entries []AuditEntry
+}
+
+func (m *memAuditStore) Record(_ context.Context, e AuditEntry) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.entries = append(m.entries, e)
+    return nil
+}
+
+func TestAuditLogBodies(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    const limit = 2 << 20
+    large := bytes.Repeat([]byte("x"), auditMaxBody+recordingMaxBody+123) // streamed, not buffered
+    tests := []struct {
+        name     string
+        body     []byte
+        chunked  bool // no Content-Length, so only the body reader can enforce the limit
+        wantCode int
+        audited  bool
+        limit    int64 // route body limit; zero uses limit
+    }{
+        {name: "small body", body: []byte(`{"amount":1}`), wantCode: http.StatusOK, audited: true},
+        {name: "body over the capture limit", body: large, wantCode: http.StatusOK, audited: true},
+        // Refused by DynamicBodyLimit before AuditLog runs
+        {name: "content length over the route limit", body: bytes.Repeat([]byte("x"), limit+1), wantCode: http.StatusRequestEntityTooLarge},
+        // Over the limit while AuditLog buffers it
+        {name: "chunked body over a small route limit", body: bytes.Repeat([]byte("x"), 1025), chunked: true, limit: 1024, wantCode: http.StatusRequestEntityTooLarge, audited: true},
+        // Over the limit only once the handler streams past what AuditLog buffers
+        {name: "chunked body over the route limit", body: bytes.Repeat([]byte("x"), limit+1), chunked: true, wantCode: http.StatusRequestEntityTooLarge, audited: true},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            audits := &memAuditStore{}
+            recordings := &memRecordingStore{recs: map[string]RecordedRequest{}}
+            var seen []byte
+            r := gin.New()
+            routeLimit := tt.limit
+            if routeLimit == 0 {
+                routeLimit = limit
+            }
+            r.Use(RequestID(), DynamicBodyLimit(nil, routeLimit), AuditLog(audits), RequestRecorder(recordings, true))
+            r.POST("/enterprise/cloud/upload", func(c *gin.Context) {
+                b, err := io.ReadAll(c.Request.Body)
+                if err != nil {
+                    abortBodyReadError(c, err)
+                    return
+                }
+                seen = b
+                c.Status(http.StatusOK)
+            })
+
+            var body io.Reader = bytes.NewReader(tt.body)
+            if tt.chunked {
+                body = io.MultiReader(body) // hides the length from httptest
+            }
+            req := httptest.NewRequest(http.MethodPost, "/enterprise/cloud/upload", body)
+            w := httptest.NewRecorder()
+            r.ServeHTTP(w, req)
+            if w.Code != tt.wantCode {
+                t.Fatalf("status = %d, want %d", w.Code, tt.wantCode)
+            }
+            if !tt.audited {
+                if len(audits.entries) != 0 {
+                    t.Fatalf("audit entries = %+v, want none", audits.entries)
+                }
+                return
+            }
+            if len(audits.entries) != 1 || audits.entries[0].StatusCode != tt.wantCode {
+                t.Fatalf("audit entries = %+v, want one with status %d", audits.entries, tt.wantCode)
+            }
+            if tt.wantCode != http.StatusOK {
+                return
+            }
+            if !bytes.Equal(seen, tt.body) {
+                t.Errorf("handler read %d bytes, want the full %d", len(seen), len(tt.body))
+            }
+            sum := sha256.Sum256(tt.body)
+            want := hex.EncodeToString(sum[:])
+            if got := audits.entries[0].RequestBodyHash; got != want {
+                t.Errorf("audit body hash = %s, want %s", got, want)
+            }
+            rec, err := recordings.Recording(context.Background(), w.Header().Get(RequestIDHeader))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if rec.BodyHash != want || rec.BodyTruncated != (len(tt.body) > recordingMaxBody) {
+                t.Errorf("recording hash %s truncated %v, want %s truncated %v", rec.BodyHash, rec.BodyTruncated, want, len(tt.body) > recordingMaxBody)
+            }
+        })
+    }
+}
\ No newline at end of file
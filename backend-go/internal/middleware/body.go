@@ -0,0 +1,68 @@
+package middleware
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "hash"
+    "io"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+// capturedBody is a request body read by a middleware that must leave it intact for the handler.
+type capturedBody struct {
+    Data      []byte // the whole body, or nil when it was longer than the capture limit
+    Truncated bool   // the body was longer than the capture limit and was not buffered
+
+    h    hash.Hash
+    rest io.Reader // unread remainder when Truncated
+}
+
+// Sum returns the hex SHA-256 of the whole body. For a truncated body it first reads
+// whatever the handler left unread, so call it only after the handler has run.
+func (b *capturedBody) Sum() string {
+    if b.rest != nil { _, _ = io.Copy(io.Discard, b.rest) }
+    return hex.EncodeToString(b.h.Sum(nil))
+}
+
+// captureBody buffers up to limit bytes of the request body and puts an equivalent body back
+// for the handler. Longer bodies are not buffered: the remainder streams through to the
+// handler and is only hashed. If reading fails, captureBody aborts the request (413 when the
+// body exceeds the limit set by DynamicBodyLimit) and returns nil.
+func captureBody(c *gin.Context, limit int64) *capturedBody {
+    b := &capturedBody{h: sha256.New()}
+    if c.Request.Body == nil || c.Request.Body == http.NoBody { return b }
+    body := c.Request.Body
+    prefix, err := io.ReadAll(io.LimitReader(body, limit+1))
+    if err != nil {
+        abortBodyReadError(c, err)
+        return nil
+    }
+    b.h.Write(prefix)
+    if int64(len(prefix)) <= limit {
+        b.Data = prefix
+        c.Request.Body = io.NopCloser(bytes.NewReader(prefix))
+        return b
+    }
+    b.Truncated = true
+    b.rest = io.TeeReader(body, b.h)
+    c.Request.Body = struct {
+        io.Reader
+        io.Closer
+    }{io.MultiReader(bytes.NewReader(prefix), b.rest), body}
+    return b
+}
+
+// abortBodyReadError answers a failed request body read: 413 when the body is over the
+// limit set by DynamicBodyLimit, 400 otherwise.
+func abortBodyReadError(c *gin.Context, err error) {
+    var tooLarge *http.MaxBytesError
+    if errors.As(err, &tooLarge) {
+        c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+        return
+    }
+    c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "failed to read body"})
+}
\ No newline at end of file
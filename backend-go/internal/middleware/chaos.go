@@ -0,0 +1,157 @@
+package middleware
+
+import (
+    "context"
+    "encoding/json"
+    "errors"
+    "log/slog"
+    "math"
+    "math/rand/v2"
+    "net/http"
+    "os"
+    "path"
+    "sync/atomic"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    redis "github.com/redis/go-redis/v9"
+)
+
+// ChaosConfigKey is the Redis key ChaosMiddlewareDynamic reads its ChaosConfig from, e.g.
+// `redis-cli SET chaos:config '{"error_rate":0.05,"latency_p50":"100ms","latency_p99":"2s","enabled_routes":["/api/v1/*"]}'`.
+// `DEL chaos:config` turns fault injection off again.
+const ChaosConfigKey = "chaos:config"
+
+// chaosRefresh is how often ChaosMiddlewareDynamic re-reads its Redis key.
+const chaosRefresh = 5 * time.Second
+
+// ChaosConfig describes the faults injected by ChaosMiddleware. Latency follows a log-normal
+// distribution with the given median and 99th percentile; with LatencyP99 at or below
+// LatencyP50 every request is delayed by LatencyP50.
+type ChaosConfig struct {
+    ErrorRate     float64       // share of requests (0.0-1.0) answered with 503
+    LatencyP50    time.Duration // median added latency; 0 adds none
+    LatencyP99    time.Duration
+    EnabledRoutes []string // path.Match globs on the request path, e.g. "/api/v1/*"; empty matches every route
+}
+
+// UnmarshalJSON reads the snake_case form stored in Redis, with latencies as Go duration
+// strings ("250ms").
+func (c *ChaosConfig) UnmarshalJSON(b []byte) error {
+    var raw struct {
+        ErrorRate     float64  `json:"error_rate"`
+        LatencyP50    string   `json:"latency_p50"`
+        LatencyP99    string   `json:"latency_p99"`
+        EnabledRoutes []string `json:"enabled_routes"`
+    }
+    if err := json.Unmarshal(b, &raw); err != nil { return err }
+    if raw.ErrorRate < 0 || raw.ErrorRate > 1 { return errors.New("error_rate must be between 0 and 1") }
+    out := ChaosConfig{ErrorRate: raw.ErrorRate, EnabledRoutes: raw.EnabledRoutes}
+    for _, d := range []struct{ s string; dst *time.Duration }{{raw.LatencyP50, &out.LatencyP50}, {raw.LatencyP99, &out.LatencyP99}} {
+        if d.s == "" { continue }
+        v, err := time.ParseDuration(d.s)
+        if err != nil || v < 0 { return errors.New("latencies must be non-negative durations, e.g. \"250ms\"") }
+        *d.dst = v
+    }
+    for _, p := range out.EnabledRoutes {
+        if _, err := path.Match(p, "/"); err != nil { return errors.New("invalid route pattern " + p) }
+    }
+    *c = out
+    return nil
+}
+
+// ChaosEnabled reports whether fault injection may run at all: CHAOS_ENABLED must be "true".
+func ChaosEnabled() bool { return os.Getenv("CHAOS_ENABLED") == "true" }
+
+// ChaosMiddleware injects the errors and latency described by cfg. Unless ChaosEnabled it
+// returns a handler that does nothing, so it is safe to register unconditionally.
+func ChaosMiddleware(cfg ChaosConfig) gin.HandlerFunc {
+    if !ChaosEnabled() { return func(c *gin.Context) { c.Next() } }
+    slog.Warn("chaos middleware enabled", "error_rate", cfg.ErrorRate, "latency_p50", cfg.LatencyP50, "latency_p99", cfg.LatencyP99)
+    return chaosHandler(func() *ChaosConfig { return &cfg })
+}
+
+// ChaosMiddlewareDynamic is ChaosMiddleware with the config read from the Redis key
+// configKey every few seconds, in the background, so faults can be switched on and off
+// without a restart. While the key is unset or invalid, fallback applies; if Redis is
+// unreachable the last config read stays. It also does nothing unless ChaosEnabled.
+func ChaosMiddlewareDynamic(rdb *redis.Client, configKey string, fallback ChaosConfig) gin.HandlerFunc {
+    if rdb == nil { return ChaosMiddleware(fallback) }
+    if !ChaosEnabled() { return func(c *gin.Context) { c.Next() } }
+    slog.Warn("chaos middleware enabled", "key", configKey)
+    var current atomic.Pointer[ChaosConfig]
+    current.Store(&fallback)
+    var nextRefresh atomic.Int64 // unix nanoseconds
+    refresh := func() {
+        ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+        defer cancel()
+        b, err := rdb.Get(ctx, configKey).Bytes()
+        switch {
+        case errors.Is(err, redis.Nil):
+            current.Store(&fallback)
+            return
+        case err != nil:
+            return // keep the last config until Redis is back
+        }
+        var cfg ChaosConfig
+        if err := json.Unmarshal(b, &cfg); err != nil {
+            slog.Warn("ignoring invalid chaos config", "key", configKey, "error", err)
+            current.Store(&fallback)
+            return
+        }
+        current.Store(&cfg)
+    }
+    refresh()
+    nextRefresh.Store(time.Now().Add(chaosRefresh).UnixNano())
+    return chaosHandler(func() *ChaosConfig {
+        // Only the request that wins the swap triggers a refresh
+        if next, now := nextRefresh.Load(), time.Now().UnixNano(); now >= next &&
+            nextRefresh.CompareAndSwap(next, now+int64(chaosRefresh)) {
+            go refresh()
+        }
+        return current.Load()
+    })
+}
+
+func chaosHandler(config func() *ChaosConfig) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        cfg := config()
+        if !cfg.matches(c.Request.URL.Path) {
+            c.Next()
+            return
+        }
+        if d := cfg.latency(); d > 0 {
+            c.Header("X-Chaos-Latency", d.String())
+            t := time.NewTimer(d)
+            select {
+            case <-t.C:
+            case <-c.Request.Context().Done():
+                t.Stop()
+            }
+        }
+        if cfg.ErrorRate > 0 && rand.Float64() < cfg.ErrorRate {
+            c.Header("X-Chaos-Injected", "error")
+            c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "service unavailable (chaos fault injection)"})
+            return
+        }
+        c.Next()
+    }
+}
+
+func (cfg *ChaosConfig) matches(p string) bool {
+    if cfg.ErrorRate <= 0 && cfg.LatencyP50 <= 0 { return false }
+    if len(cfg.EnabledRoutes) == 0 { return true }
+    for _, pattern := range cfg.EnabledRoutes {
+        if ok, _ := path.Match(pattern, p); ok { return true }
+    }
+    return false
+}
+
+// latency samples a log-normal delay whose median is LatencyP50 and whose 99th percentile
+// is LatencyP99 (z = 2.326 at the 99th percentile).
+func (cfg *ChaosConfig) latency() time.Duration {
+    if cfg.LatencyP50 <= 0 { return 0 }
+    if cfg.LatencyP99 <= cfg.LatencyP50 { return cfg.LatencyP50 }
+    sigma := math.Log(float64(cfg.LatencyP99)/float64(cfg.LatencyP50)) / 2.326
+    return time.Duration(float64(cfg.LatencyP50) * math.Exp(sigma*rand.NormFloat64()))
+}
\ No newline at end of file
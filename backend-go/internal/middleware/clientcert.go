@@ -0,0 +1,34 @@
+package middleware
+
+import (
+    "crypto/x509"
+    "net/http"
+
+    "github.com/gin-gonic/gin"
+)
+
+// RequireClientCert rejects requests that did not present a client certificate chaining
+// to caPool. It is checked here rather than only in the TLS handshake so that routes
+// outside the group can still be served to clients without certificates.
+func RequireClientCert(caPool *x509.CertPool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if c.Request.TLS == nil || len(c.Request.TLS.PeerCertificates) == 0 {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "client certificate required"})
+            return
+        }
+        certs := c.Request.TLS.PeerCertificates
+        opts := x509.VerifyOptions{
+            Roots:         caPool,
+            Intermediates: x509.NewCertPool(),
+            KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+        }
+        for _, ic := range certs[1:] {
+            opts.Intermediates.AddCert(ic)
+        }
+        if _, err := certs[0].Verify(opts); err != nil {
+            c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "invalid client certificate"})
+            return
+        }
+        c.Next()
+    }
+}
\ No newline at end of file
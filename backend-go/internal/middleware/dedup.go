@@ -0,0 +1,139 @@
+package middleware
+
+import (
+    "bytes"
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "io"
+    "net/http"
+    "time"
+
+    "github.com/gin-gonic/gin"
+    redis "github.com/redis/go-redis/v9"
+
+    "garp-backend/internal/logger"
+    "garp-backend/internal/otel"
+)
+
+// BodyHashHeader carries the SHA-256 of the request body on responses from DeduplicateMiddleware.
+const BodyHashHeader = "X-Body-Hash"
+
+// dedupTTL is how long a body hash is remembered; retries after this are treated as new requests.
+const dedupTTL = 5 * time.Minute
+
+const dedupPending = "pending"
+
+// storedResponse is the Redis value replayed for duplicate requests.
+type storedResponse struct {
+    Status      int    `json:"status"`
+    ContentType string `json:"content_type"`
+    Body        []byte `json:"body"`
+}
+
+// captureWriter tees the response body so it can be stored for replay.
+type captureWriter struct {
+    gin.ResponseWriter
+    buf bytes.Buffer
+}
+
+func (w *captureWriter) Write(b []byte) (int, error) {
+    w.buf.Write(b)
+    return w.ResponseWriter.Write(b)
+}
+
+func (w *captureWriter) WriteString(s string) (int, error) {
+    w.buf.WriteString(s)
+    return w.ResponseWriter.WriteString(s)
+}
+
+// DeduplicateMiddleware makes retried POSTs safe: the SHA-256 of the body (per route and caller) is kept in
+// Redis for five minutes, and an identical body within that window gets the first response replayed
+// instead of running the handler again. While the first request is still in flight, duplicates get 409.
+// 5xx responses are not remembered so a retry after a server error is processed normally.
+// The hash is returned in bodyHashHeader (BodyHashHeader if empty). Redis errors fail open.
+func DeduplicateMiddleware(rdb *redis.Client, bodyHashHeader string) gin.HandlerFunc {
+    if bodyHashHeader == "" { bodyHashHeader = BodyHashHeader }
+    return func(c *gin.Context) {
+        if rdb == nil {
+            c.Next()
+            return
+        }
+        body, err := io.ReadAll(c.Request.Body)
+        if err != nil {
+            abortBodyReadError(c, err)
+            return
+        }
+        c.Request.Body = io.NopCloser(bytes.NewReader(body))
+        sum := sha256.Sum256(body)
+        hash := hex.EncodeToString(sum[:])
+        c.Header(bodyHashHeader, hash)
+
+        ctx := c.Request.Context()
+        log := logger.FromContext(ctx)
+        key := "dedup:" + c.Request.Method + ":" + c.FullPath() + ":" + dedupCaller(c) + ":" + hash
+        fresh, err := rdb.SetNX(ctx, key, dedupPending, dedupTTL).Result()
+        if err != nil {
+            log.Warn("dedup: redis unavailable, processing request", "error", err)
+            c.Next()
+            return
+        }
+        if !fresh {
+            replayStored(c, rdb, key)
+            return
+        }
+
+        w := &captureWriter{ResponseWriter: c.Writer}
+        c.Writer = w
+        c.Next()
+
+        // Store with a fresh context: the request context may already be cancelled
+        storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+        defer cancel()
+        if w.Status() >= 500 {
+            _ = rdb.Del(storeCtx, key).Err()
+            return
+        }
+        val, _ := json.Marshal(storedResponse{Status: w.Status(), ContentType: w.Header().Get("Content-Type"), Body: w.buf.Bytes()})
+        if err := rdb.Set(storeCtx, key, val, dedupTTL).Err(); err != nil {
+            log.Warn("dedup: failed to store response", "error", err)
+        }
+    }
+}
+
+// dedupCaller scopes a body hash to whoever sent it, so one caller never gets another's
+// stored response: the subject set by JWTAuth, else a hash of the Authorization header,
+// else "anon" for unauthenticated routes.
+func dedupCaller(c *gin.Context) string {
+    if sub := c.GetString(otel.UserIDKey); sub != "" {
+        sum := sha256.Sum256([]byte(sub))
+        return "sub-" + hex.EncodeToString(sum[:8])
+    }
+    if auth := c.GetHeader("Authorization"); auth != "" {
+        sum := sha256.Sum256([]byte(auth))
+        return "auth-" + hex.EncodeToString(sum[:8])
+    }
+    return "anon"
+}
+
+func replayStored(c *gin.Context, rdb *redis.Client, key string) {
+    raw, err := rdb.Get(c.Request.Context(), key).Bytes()
+    if err == redis.Nil {
+        // Expired between SETNX and GET; let the request through
+        c.Next()
+        return
+    }
+    if err == nil && string(raw) == dedupPending {
+        c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "duplicate request is still being processed"})
+        return
+    }
+    var resp storedResponse
+    if err != nil || json.Unmarshal(raw, &resp) != nil {
+        c.Next()
+        return
+    }
+    c.Header("X-Deduplicated", "true")
+    c.Data(resp.Status, resp.ContentType, resp.Body)
+    c.Abort()
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "testing"
+
+    "github.com/alicebob/miniredis/v2"
+    "github.com/gin-gonic/gin"
+    "github.com/golang-jwt/jwt/v5"
+    "github.com/redis/go-redis/v9"
+
+    "garp-backend/internal/otel"
+)
+
+func TestDeduplicateMiddlewarePerCaller(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    rdb := redis.NewClient(&redis.Options{Addr: miniredis.RunT(t).Addr()})
+    defer rdb.Close()
+
+    const secret = "test-secret"
+    var runs []string
+    r := gin.New()
+    r.POST("/staking/stake", JWTAuth(secret), DeduplicateMiddleware(rdb, ""), func(c *gin.Context) {
+        staker := c.GetString(otel.UserIDKey)
+        runs = append(runs, staker)
+        c.JSON(http.StatusOK, gin.H{"staker": staker})
+    })
+    token := func(sub string) string {
+        s, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": sub}).SignedString([]byte(secret))
+        if err != nil {
+            t.Fatal(err)
+        }
+        return s
+    }
+
+    const body = `{"amount":100,"validator":"val-1"}`
+    stake := func(sub string) *httptest.ResponseRecorder {
+        req := httptest.NewRequest(http.MethodPost, "/staking/stake", strings.NewReader(body))
+        req.Header.Set("Authorization", "Bearer "+token(sub))
+        w := httptest.NewRecorder()
+        r.ServeHTTP(w, req)
+        return w
+    }
+
+    for _, step := range []struct {
+        sub          string
+        wantStaker   string
+        deduplicated bool
+    }{
+        {sub: "alice", wantStaker: "alice"},
+        {sub: "bob", wantStaker: "bob"}, // same body, different caller: runs and sees only bob's response
+        {sub: "alice", wantStaker: "alice", deduplicated: true},
+    } {
+        w := stake(step.sub)
+        if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"staker":"`+step.wantStaker+`"`) {
+            t.Fatalf("%s got %d %s, want the response for %s", step.sub, w.Code, w.Body.String(), step.wantStaker)
+        }
+        if got := w.Header().Get("X-Deduplicated") == "true"; got != step.deduplicated {
+            t.Errorf("%s: deduplicated = %v, want %v", step.sub, got, step.deduplicated)
+        }
+    }
+    if strings.Join(runs, ",") != "alice,bob" {
+        t.Errorf("handler ran for %v, want alice then bob", runs)
+    }
+}
\ No newline at end of file
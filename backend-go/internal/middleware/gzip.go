@@ -0,0 +1,108 @@
+package middleware
+
+import (
+    "compress/gzip"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// minGzipSize is one Ethernet MTU of payload; smaller responses are sent uncompressed.
+const minGzipSize = 1400
+
+// gzipWriter buffers the first minGzipSize bytes so small responses can skip compression,
+// then switches to gzip or passthrough once the size and content type are known.
+type gzipWriter struct {
+    gin.ResponseWriter
+    level   int
+    buf     []byte
+    gz      *gzip.Writer
+    decided bool
+}
+
+func (w *gzipWriter) Write(b []byte) (int, error) {
+    if w.decided {
+        if w.gz != nil { return w.gz.Write(b) }
+        return w.ResponseWriter.Write(b)
+    }
+    w.buf = append(w.buf, b...)
+    if len(w.buf) >= minGzipSize {
+        if err := w.decide(true); err != nil { return 0, err }
+    }
+    return len(b), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) { return w.Write([]byte(s)) }
+
+// Flush sends whatever is buffered; a response flushed before reaching minGzipSize
+// (e.g. a stream) is decided on its content type alone.
+func (w *gzipWriter) Flush() {
+    if !w.decided { _ = w.decide(true) }
+    if w.gz != nil { _ = w.gz.Flush() }
+    w.ResponseWriter.Flush()
+}
+
+// decide picks gzip or passthrough and writes out the buffered bytes.
+func (w *gzipWriter) decide(allowGzip bool) error {
+    w.decided = true
+    h := w.Header()
+    if allowGzip && h.Get("Content-Encoding") == "" && compressible(h.Get("Content-Type")) {
+        gz, err := gzip.NewWriterLevel(w.ResponseWriter, w.level)
+        if err != nil { return err }
+        h.Set("Content-Encoding", "gzip")
+        h.Del("Content-Length")
+        w.gz = gz
+        _, err = gz.Write(w.buf)
+        w.buf = nil
+        return err
+    }
+    if len(w.buf) == 0 { return nil }
+    _, err := w.ResponseWriter.Write(w.buf)
+    w.buf = nil
+    return err
+}
+
+func (w *gzipWriter) close() {
+    if !w.decided { _ = w.decide(false) }
+    if w.gz != nil { _ = w.gz.Close() }
+}
+
+// compressible skips media and archives that are already compressed, and event streams.
+func compressible(contentType string) bool {
+    ct := strings.ToLower(contentType)
+    for _, p := range []string{"image/", "video/", "audio/", "text/event-stream", "application/zip", "application/gzip", "application/x-gzip", "application/octet-stream"} {
+        if strings.HasPrefix(ct, p) { return false }
+    }
+    return true
+}
+
+// GzipResponse compresses responses of at least 1400 bytes for clients that send
+// Accept-Encoding: gzip. level is a compress/gzip level; invalid levels use the default.
+func GzipResponse(level int) gin.HandlerFunc {
+    if level < gzip.HuffmanOnly || level > gzip.BestCompression { level = gzip.DefaultCompression }
+    return func(c *gin.Context) {
+        if c.Request.Method == http.MethodHead || c.GetHeader("Upgrade") != "" || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+            c.Next()
+            return
+        }
+        c.Header("Vary", "Accept-Encoding")
+        w := &gzipWriter{ResponseWriter: c.Writer, level: level}
+        c.Writer = w
+        defer func() {
+            w.close()
+            c.Writer = w.ResponseWriter
+        }()
+        c.Next()
+    }
+}
+
+func acceptsGzip(header string) bool {
+    for _, part := range strings.Split(header, ",") {
+        enc, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+        if !strings.EqualFold(strings.TrimSpace(enc), "gzip") && strings.TrimSpace(enc) != "*" { continue }
+        // "gzip;q=0" explicitly refuses gzip
+        return strings.ReplaceAll(strings.TrimSpace(params), " ", "") != "q=0"
+    }
+    return false
+}
\ No newline at end of file
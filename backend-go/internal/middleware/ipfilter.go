@@ -0,0 +1,73 @@
+package middleware
+
+import (
+    "fmt"
+    "net"
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// IPAllowList rejects requests whose client IP is not inside one of cidrs with 403.
+// An empty list allows everyone. The client IP comes from X-Forwarded-For only when the
+// engine trusts the sending proxy (gin.Engine.SetTrustedProxies).
+// It panics on an invalid entry, so a mistyped allowlist cannot silently allow everyone;
+// config.Validate reports such entries before this is reached.
+func IPAllowList(cidrs []string) gin.HandlerFunc {
+    nets := parseCIDRs(cidrs)
+    return func(c *gin.Context) {
+        if len(nets) > 0 && !containsIP(nets, c.ClientIP()) {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+            return
+        }
+        c.Next()
+    }
+}
+
+// IPBlockList rejects requests whose client IP is inside one of cidrs with 403.
+// Like IPAllowList it panics on an invalid entry.
+func IPBlockList(cidrs []string) gin.HandlerFunc {
+    nets := parseCIDRs(cidrs)
+    return func(c *gin.Context) {
+        if containsIP(nets, c.ClientIP()) {
+            c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "forbidden"})
+            return
+        }
+        c.Next()
+    }
+}
+
+// parseCIDR parses s as a CIDR; a bare IP is treated as a single-host network.
+func parseCIDR(s string) (*net.IPNet, error) {
+    s = strings.TrimSpace(s)
+    if !strings.Contains(s, "/") {
+        if ip := net.ParseIP(s); ip != nil {
+            if ip.To4() != nil { s += "/32" } else { s += "/128" }
+        }
+    }
+    _, n, err := net.ParseCIDR(s)
+    return n, err
+}
+
+func parseCIDRs(cidrs []string) []*net.IPNet {
+    var nets []*net.IPNet
+    for _, s := range cidrs {
+        if strings.TrimSpace(s) == "" { continue }
+        n, err := parseCIDR(s)
+        if err != nil {
+            panic(fmt.Sprintf("ip filter: invalid CIDR %q: %v", s, err))
+        }
+        nets = append(nets, n)
+    }
+    return nets
+}
+
+func containsIP(nets []*net.IPNet, addr string) bool {
+    ip := net.ParseIP(addr)
+    if ip == nil { return false }
+    for _, n := range nets {
+        if n.Contains(ip) { return true }
+    }
+    return false
+}
\ No newline at end of file
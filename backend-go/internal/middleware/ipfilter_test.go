@@ -0,0 +1,56 @@
+package middleware
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+)
+
+func TestIPFilterIgnoresUntrustedForwardedFor(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    tests := []struct {
+        name    string
+        trusted []string
+        remote  string
+        xff     string
+        want    int
+    }{
+        {name: "allowed remote", remote: "10.1.2.3:4000", want: http.StatusOK},
+        {name: "blocked remote", remote: "192.0.2.7:4000", want: http.StatusForbidden},
+        {name: "spoofed allowed ip", remote: "203.0.113.9:4000", xff: "10.1.2.3", want: http.StatusForbidden},
+        {name: "spoof dodging blocklist", remote: "192.0.2.7:4000", xff: "10.1.2.3", want: http.StatusForbidden},
+        {name: "trusted proxy", trusted: []string{"172.16.0.1"}, remote: "172.16.0.1:4000", xff: "10.1.2.3", want: http.StatusOK},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            r := gin.New()
+            if err := r.SetTrustedProxies(tt.trusted); err != nil {
+                t.Fatal(err)
+            }
+            r.Use(IPBlockList([]string{"192.0.2.0/24"}), IPAllowList([]string{"10.0.0.0/8", "192.0.2.7", "172.16.0.1"}))
+            r.GET("/", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+            req := httptest.NewRequest(http.MethodGet, "/", nil)
+            req.RemoteAddr = tt.remote
+            if tt.xff != "" {
+                req.Header.Set("X-Forwarded-For", tt.xff)
+            }
+            w := httptest.NewRecorder()
+            r.ServeHTTP(w, req)
+            if w.Code != tt.want {
+                t.Fatalf("status = %d, want %d", w.Code, tt.want)
+            }
+        })
+    }
+}
+
+func TestIPAllowListRejectsInvalidCIDR(t *testing.T) {
+    defer func() {
+        if recover() == nil {
+            t.Fatal("IPAllowList accepted an invalid CIDR")
+        }
+    }()
+    IPAllowList([]string{"10.0.0.0/33"})
+}
\ No newline at end of file
@@ -1,10 +1,14 @@
 package middleware
 
 import (
+    "context"
+    "fmt"
+    "strings"
     "time"
     "github.com/gin-gonic/gin"
     prom "github.com/prometheus/client_golang/prometheus"
     promhttp "github.com/prometheus/client_golang/prometheus/promhttp"
+    "github.com/prometheus/client_golang/prometheus/push"
 )
 
 var (
@@ -16,11 +20,75 @@ var (
         prom.HistogramOpts{Name: "backend_http_request_duration_seconds", Help: "Request latency", Buckets: prom.DefBuckets},
         []string{"path","method"},
     )
+
+    // Business metrics
+    txSubmitted = prom.NewCounterVec(
+        prom.CounterOpts{Name: "backend_transactions_submitted_total", Help: "Transactions submitted to the participant"},
+        []string{"submitter","status"},
+    )
+    txValue = prom.NewHistogram(
+        prom.HistogramOpts{Name: "backend_transaction_value", Help: "Value moved per transaction", Buckets: prom.ExponentialBuckets(1, 10, 12)},
+    )
+    bridgeTransfers = prom.NewCounterVec(
+        prom.CounterOpts{Name: "backend_bridge_transfers_total", Help: "Cross-chain bridge transfers initiated"},
+        []string{"source_chain","target_chain"},
+    )
+    bridgeAmount = prom.NewHistogramVec(
+        prom.HistogramOpts{Name: "backend_bridge_transfer_amount", Help: "Amount per bridge transfer", Buckets: prom.ExponentialBuckets(1, 10, 12)},
+        []string{"source_chain","target_chain"},
+    )
+
+    // Batch job metrics
+    migrationDuration = prom.NewHistogramVec(
+        prom.HistogramOpts{Name: "backend_migration_duration_seconds", Help: "Time spent applying each migration file", Buckets: prom.DefBuckets},
+        []string{"file","status"},
+    )
 )
 
 func init() {
     prom.MustRegister(httpRequests)
     prom.MustRegister(httpLatency)
+    prom.MustRegister(txSubmitted, txValue, bridgeTransfers, bridgeAmount)
+    prom.MustRegister(migrationDuration)
+}
+
+// RecordTransactionSubmitted counts a transaction accepted by the participant.
+// Submitters are expected to be a bounded set of party IDs; empty values are recorded as "unknown".
+func RecordTransactionSubmitted(submitter string, status string) {
+    if submitter == "" { submitter = "unknown" }
+    if status == "" { status = "pending" }
+    txSubmitted.WithLabelValues(submitter, status).Inc()
+}
+
+// RecordTransactionValue observes the value moved by one transaction; negative values are ignored.
+func RecordTransactionValue(value int64) {
+    if value < 0 { return }
+    txValue.Observe(float64(value))
+}
+
+// RecordBridgeTransfer counts a bridge transfer and observes its amount.
+func RecordBridgeTransfer(sourceChain, targetChain string, amount int64) {
+    bridgeTransfers.WithLabelValues(sourceChain, targetChain).Inc()
+    if amount >= 0 { bridgeAmount.WithLabelValues(sourceChain, targetChain).Observe(float64(amount)) }
+}
+
+// RecordMigration observes how long one migration file took and whether it applied.
+func RecordMigration(file string, d time.Duration, err error) {
+    status := "ok"
+    if err != nil { status = "error" }
+    migrationDuration.WithLabelValues(file, status).Observe(d.Seconds())
+}
+
+// PushMetrics pushes the default registry to a Prometheus Pushgateway, replacing any
+// metrics previously pushed under the same job and instance. Use it for runs too
+// short-lived to be scraped.
+func PushMetrics(ctx context.Context, pushgatewayURL, jobName, instanceLabel string) error {
+    p := push.New(pushgatewayURL, jobName).Gatherer(prom.DefaultGatherer)
+    if instanceLabel != "" { p = p.Grouping("instance", instanceLabel) }
+    if err := p.PushContext(ctx); err != nil {
+        return fmt.Errorf("failed to push metrics to %s: %w", pushgatewayURL, err)
+    }
+    return nil
 }
 
 func MetricsMiddleware() gin.HandlerFunc {
@@ -41,6 +109,54 @@ func MetricsHandler() gin.HandlerFunc {
     return func(c *gin.Context) { h.ServeHTTP(c.Writer, c.Request) }
 }
 
+// MetricSample is one labelled series in a MetricsSnapshot.
+type MetricSample struct {
+    Labels map[string]string `json:"labels,omitempty"`
+    Value  float64           `json:"value"`          // counter, gauge or untyped value
+    Count  uint64            `json:"count,omitempty"` // histogram and summary observations
+    Sum    float64           `json:"sum,omitempty"`
+}
+
+// MetricFamilySnapshot is a JSON-friendly view of a Prometheus metric family.
+type MetricFamilySnapshot struct {
+    Name    string         `json:"name"`
+    Help    string         `json:"help"`
+    Type    string         `json:"type"`
+    Samples []MetricSample `json:"samples"`
+}
+
+// MetricsSnapshot gathers the default Prometheus registry into a JSON-friendly form.
+func MetricsSnapshot() ([]MetricFamilySnapshot, error) {
+    mfs, err := prom.DefaultGatherer.Gather()
+    if err != nil { return nil, err }
+    out := make([]MetricFamilySnapshot, 0, len(mfs))
+    for _, mf := range mfs {
+        fam := MetricFamilySnapshot{Name: mf.GetName(), Help: mf.GetHelp(), Type: strings.ToLower(mf.GetType().String())}
+        for _, m := range mf.GetMetric() {
+            var s MetricSample
+            if len(m.GetLabel()) > 0 {
+                s.Labels = make(map[string]string, len(m.GetLabel()))
+                for _, l := range m.GetLabel() { s.Labels[l.GetName()] = l.GetValue() }
+            }
+            switch {
+            case m.Counter != nil:
+                s.Value = m.GetCounter().GetValue()
+            case m.Gauge != nil:
+                s.Value = m.GetGauge().GetValue()
+            case m.Untyped != nil:
+                s.Value = m.GetUntyped().GetValue()
+            case m.Histogram != nil:
+                s.Count, s.Sum = m.GetHistogram().GetSampleCount(), m.GetHistogram().GetSampleSum()
+            case m.Summary != nil:
+                s.Count, s.Sum = m.GetSummary().GetSampleCount(), m.GetSummary().GetSampleSum()
+            }
+            fam.Samples = append(fam.Samples, s)
+        }
+        out = append(out, fam)
+    }
+    return out, nil
+}
+
 func itoa(i int) string {
     if i == 0 { return "0" }
     b := [12]byte{}
@@ -0,0 +1,158 @@
+package middleware
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "errors"
+    "net/http"
+    "strings"
+    "time"
+
+    "github.com/gin-gonic/gin"
+
+    "garp-backend/internal/logger"
+)
+
+// RecordingTTL is how long recorded requests are kept for replay.
+const RecordingTTL = time.Hour
+
+// recordingMaxBody caps the request body kept for replay; larger bodies keep only their hash.
+const recordingMaxBody = 64 << 10
+
+// ReplayOfHeader marks a request replayed from a recording; its value is the recording ID.
+const ReplayOfHeader = "X-Replay-Of"
+
+// ReplayAuthorizationHeader carries the admin's own Authorization value (e.g. "Bearer <jwt>")
+// to use for a replay; the recorded caller's credentials are never stored.
+const ReplayAuthorizationHeader = "X-Replay-Authorization"
+
+// ErrRecordingNotFound is returned by RecordingStore.Recording for unknown or expired IDs.
+var ErrRecordingNotFound = errors.New("recording not found")
+
+// RecordedRequest is one incoming request as kept by RequestRecorder, keyed by its request ID.
+// Credentials (Authorization, Proxy-Authorization, Cookie) are never stored.
+type RecordedRequest struct {
+    ID            string              `json:"id"`
+    Timestamp     time.Time           `json:"timestamp"`
+    Method        string              `json:"method"`
+    Path          string              `json:"path"`
+    Route         string              `json:"route,omitempty"` // matched route pattern, e.g. "/api/v1/transactions/:id"
+    Query         map[string][]string `json:"query,omitempty"`
+    Headers       map[string][]string `json:"headers,omitempty"`
+    Body          []byte              `json:"body,omitempty"` // omitted when BodyTruncated or BodyOmitted
+    BodyHash      string              `json:"body_hash"`      // hex SHA-256 of the full body
+    BodyTruncated bool                `json:"body_truncated,omitempty"`
+    BodyOmitted   bool                `json:"body_omitted,omitempty"` // body capture is off; only BodyHash was kept
+    StatusCode    int                 `json:"status_code"`
+    Streaming     bool                `json:"streaming,omitempty"` // the response was an event stream or a protocol upgrade
+    Replays       []ReplayResult      `json:"replays,omitempty"`
+}
+
+// Replayable reports why rec cannot be replayed, or "" if it can. A replay runs in-process
+// and waits for the full response, so streamed responses are refused, as are requests
+// whose body was not kept.
+func (rec *RecordedRequest) Replayable() string {
+    switch {
+    case rec.Streaming:
+        return "streaming responses cannot be replayed"
+    case rec.BodyTruncated:
+        return "request body was too large to record; it cannot be replayed"
+    case rec.BodyOmitted:
+        return "request body was not recorded; it cannot be replayed"
+    }
+    return ""
+}
+
+// ReplayResult is the response to one replay of a RecordedRequest.
+type ReplayResult struct {
+    Timestamp  time.Time           `json:"timestamp"`
+    StatusCode int                 `json:"status_code"`
+    Headers    map[string][]string `json:"headers,omitempty"`
+    Body          []byte              `json:"body,omitempty"` // omitted when BodyTruncated
+    BodyHash      string              `json:"body_hash"`
+    BodyTruncated bool                `json:"body_truncated,omitempty"`
+}
+
+// NewReplayResult captures a replayed response, with credentials removed from its headers
+// and the body kept under the same size cap as recorded requests.
+func NewReplayResult(status int, header http.Header, body []byte) ReplayResult {
+    sum := sha256.Sum256(body)
+    res := ReplayResult{Timestamp: time.Now().UTC(), StatusCode: status, Headers: RedactedHeaders(header), BodyHash: hex.EncodeToString(sum[:])}
+    if len(body) <= recordingMaxBody {
+        res.Body = body
+    } else {
+        res.BodyTruncated = true
+    }
+    return res
+}
+
+// RecordingStore keeps recorded requests for RecordingTTL.
+type RecordingStore interface {
+    // SaveRecording stores rec unless a recording with the same ID exists.
+    SaveRecording(ctx context.Context, rec RecordedRequest) error
+    Recording(ctx context.Context, id string) (*RecordedRequest, error)
+    // AddReplay appends a replay result to an existing recording.
+    AddReplay(ctx context.Context, id string, result ReplayResult) error
+}
+
+// RequestRecorder records every request except /admin and /health ones, under the ID set
+// by RequestID, so it can be inspected and replayed from the admin API. The write happens
+// after the response; a failed write is logged and does not change the response.
+// Request bodies, which include signed transaction payloads, are kept only when
+// captureBodies is set; otherwise just their hash is, and POSTs cannot be replayed.
+func RequestRecorder(store RecordingStore, captureBodies bool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        p := c.Request.URL.Path
+        if strings.HasPrefix(p, "/admin/") || strings.HasPrefix(p, "/health") {
+            c.Next()
+            return
+        }
+        body := captureBody(c, recordingMaxBody)
+        if body == nil { return } // aborted, e.g. 413
+        rec := RecordedRequest{
+            ID:        c.Writer.Header().Get(RequestIDHeader),
+            Timestamp: time.Now().UTC(),
+            Method:    c.Request.Method,
+            Path:      p,
+            Route:     c.FullPath(),
+            Query:     c.Request.URL.Query(),
+            Headers:   RedactedHeaders(c.Request.Header),
+        }
+        switch {
+        case len(body.Data) == 0 && !body.Truncated:
+        case !captureBodies:
+            rec.BodyOmitted = true
+        case body.Truncated:
+            rec.BodyTruncated = true
+        default:
+            rec.Body = body.Data
+        }
+
+        c.Next()
+
+        rec.BodyHash = body.Sum()
+        if rec.ID == "" { return }
+        rec.StatusCode = c.Writer.Status()
+        rec.Streaming = rec.StatusCode == http.StatusSwitchingProtocols ||
+            strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream")
+        ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), auditTimeout)
+        defer cancel()
+        if err := store.SaveRecording(ctx, rec); err != nil {
+            logger.FromContext(ctx).Warn("failed to record request", "id", rec.ID, "error", err)
+        }
+    }
+}
+
+// RedactedHeaders copies h without credentials.
+func RedactedHeaders(h http.Header) map[string][]string {
+    out := make(map[string][]string, len(h))
+    for k, v := range h {
+        switch http.CanonicalHeaderKey(k) {
+        case "Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", ReplayAuthorizationHeader:
+            continue
+        }
+        out[k] = append([]string(nil), v...)
+    }
+    return out
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+package middleware
+
+import (
+    "context"
+    "net/http"
+    "net/http/httptest"
+    "strings"
+    "sync"
+    "testing"
+
+    "github.com/gin-gonic/gin"
+)
+
+type memRecordingStore struct {
+    mu   sync.Mutex
+    recs map[string]RecordedRequest
+}
+
+func (m *memRecordingStore) SaveRecording(_ context.Context, rec RecordedRequest) error {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    m.recs[rec.ID] = rec
+    return nil
+}
+
+func (m *memRecordingStore) Recording(_ context.Context, id string) (*RecordedRequest, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    rec, ok := m.recs[id]
+    if !ok { return nil, ErrRecordingNotFound }
+    return &rec, nil
+}
+
+func (m *memRecordingStore) AddReplay(context.Context, string, ReplayResult) error { return nil }
+
+func TestRequestRecorder(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    const body = `{"submitter":"alice","signature":"3045..."}`
+    tests := []struct {
+        name          string
+        captureBodies bool
+        method, path  string
+        body          string
+        wantBody      string
+        wantReason    string // from Replayable; "" = replayable
+    }{
+        {name: "body captured", captureBodies: true, method: http.MethodPost, path: "/api/v1/transactions", body: body, wantBody: body},
+        {name: "body capture off", method: http.MethodPost, path: "/api/v1/transactions", body: body, wantReason: "not recorded"},
+        {name: "get without body", method: http.MethodGet, path: "/api/v1/transactions/tx-1"},
+        {name: "event stream", captureBodies: true, method: http.MethodGet, path: "/api/v1/transactions/tx-1/events", wantReason: "streaming"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            store := &memRecordingStore{recs: map[string]RecordedRequest{}}
+            r := gin.New()
+            r.Use(RequestID(), RequestRecorder(store, tt.captureBodies))
+            r.POST("/api/v1/transactions", func(c *gin.Context) { c.Status(http.StatusOK) })
+            r.GET("/api/v1/transactions/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+            r.GET("/api/v1/transactions/:id/events", func(c *gin.Context) {
+                c.Header("Content-Type", "text/event-stream")
+                c.String(http.StatusOK, "data: pending\n\n")
+            })
+
+            req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+            w := httptest.NewRecorder()
+            r.ServeHTTP(w, req)
+            rec, err := store.Recording(context.Background(), w.Header().Get(RequestIDHeader))
+            if err != nil {
+                t.Fatal(err)
+            }
+            if string(rec.Body) != tt.wantBody || rec.BodyHash == "" {
+                t.Errorf("recorded body %q (hash %q), want %q", rec.Body, rec.BodyHash, tt.wantBody)
+            }
+            if reason := rec.Replayable(); (tt.wantReason == "") != (reason == "") || !strings.Contains(reason, tt.wantReason) {
+                t.Errorf("Replayable() = %q, want it to mention %q", reason, tt.wantReason)
+            }
+        })
+    }
+}
\ No newline at end of file
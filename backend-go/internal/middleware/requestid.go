@@ -0,0 +1,28 @@
+package middleware
+
+import (
+    "crypto/rand"
+    "encoding/hex"
+
+    "github.com/gin-gonic/gin"
+
+    "garp-backend/internal/logger"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+// RequestID propagates (or generates) an X-Request-ID and attaches a request-scoped
+// logger to the request context; handlers retrieve it with logger.FromContext.
+func RequestID() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := c.GetHeader(RequestIDHeader)
+        if id == "" || len(id) > 128 {
+            var b [16]byte
+            _, _ = rand.Read(b[:])
+            id = hex.EncodeToString(b[:])
+        }
+        c.Header(RequestIDHeader, id)
+        c.Request = c.Request.WithContext(logger.WithRequestID(c.Request.Context(), id))
+        c.Next()
+    }
+}
\ No newline at end of file
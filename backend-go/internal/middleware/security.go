@@ -2,14 +2,38 @@ package middleware
 
 import (
     "context"
+    "errors"
+    "log/slog"
+    "math/rand/v2"
     "net/http"
     "time"
     "strconv"
+    "sync"
+    "sync/atomic"
 
     "github.com/gin-gonic/gin"
     redis "github.com/redis/go-redis/v9"
 )
 
+// DynamicBodyLimit caps request bodies per route: the limit for c.FullPath() in limits,
+// or defaultLimit for routes not listed. A limit of 0 or less leaves the body uncapped.
+// A request declaring a longer Content-Length gets 413 straight away; otherwise handlers
+// see an *http.MaxBytesError from the body reader once the limit is passed.
+func DynamicBodyLimit(limits map[string]int64, defaultLimit int64) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        limit, ok := limits[c.FullPath()]
+        if !ok { limit = defaultLimit }
+        if limit > 0 {
+            if c.Request.ContentLength > limit {
+                c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "request body too large"})
+                return
+            }
+            c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+        }
+        c.Next()
+    }
+}
+
 func MaxBodyBytes(limit int64) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
@@ -23,26 +47,41 @@ type bucket struct {
 	last   int64
 }
 
-var limiter = make(map[string]*bucket)
+// limiter is shared by every RateLimit handler. Buckets idle for a full window are swept
+// inline at most once a minute, so the map stays bounded without a background goroutine.
+var limiter = struct {
+    sync.Mutex
+    buckets   map[string]*bucket
+    lastSweep int64
+}{buckets: make(map[string]*bucket)}
 
 func RateLimit(reqPerMin int) gin.HandlerFunc {
     return func(c *gin.Context) {
         key := c.FullPath() + "|" + c.ClientIP()
-        b := limiter[key]
         now := time.Now().Unix()
+        limiter.Lock()
+        if now-limiter.lastSweep >= 60 {
+            for k, b := range limiter.buckets {
+                if now-b.last >= 60 { delete(limiter.buckets, k) }
+            }
+            limiter.lastSweep = now
+        }
+        b := limiter.buckets[key]
         if b == nil {
             b = &bucket{tokens: reqPerMin, last: now}
-            limiter[key] = b
+            limiter.buckets[key] = b
         }
         if now-b.last >= 60 {
             b.tokens = reqPerMin
             b.last = now
         }
-        if b.tokens <= 0 {
+        allowed := b.tokens > 0
+        if allowed { b.tokens-- }
+        limiter.Unlock()
+        if !allowed {
             c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
             return
         }
-        b.tokens--
         c.Next()
     }
 }
@@ -50,27 +89,188 @@ func RateLimit(reqPerMin int) gin.HandlerFunc {
 // RateLimitRedis provides distributed rate limiting using Redis per route+IP.
 func RateLimitRedis(reqPerMin int, rdb *redis.Client) gin.HandlerFunc {
     if rdb == nil || reqPerMin <= 0 { return RateLimit(reqPerMin) }
-    // Lua script to atomically increment and set TTL
-    script := redis.NewScript(`
-        local count = redis.call('INCR', KEYS[1])
-        if count == 1 then redis.call('EXPIRE', KEYS[1], 60) end
-        return count
-    `)
+    return RateLimitRedisFunc(func() int { return reqPerMin }, rdb)
+}
+
+// RateLimitRedisFunc is RateLimitRedis with the limit read on every request,
+// so it can be changed at runtime (e.g. on config reload).
+func RateLimitRedisFunc(reqPerMin func() int, rdb *redis.Client) gin.HandlerFunc {
+    if rdb == nil { return RateLimit(reqPerMin()) }
+    return rateLimitRedis(rdb, func(*gin.Context) int { return reqPerMin() })
+}
+
+// rateLimitRedis counts requests per route, client IP and minute in Redis and rejects
+// those over limit(c); a limit of 0 or less disables the check.
+func rateLimitRedis(rdb *redis.Client, limit func(c *gin.Context) int) gin.HandlerFunc {
     return func(c *gin.Context) {
         bucket := time.Now().Unix() / 60
         key := "rl:" + c.FullPath() + ":" + c.ClientIP() + ":" + strconv.FormatInt(bucket, 10)
         ctx := context.Background()
-        res, err := script.Run(ctx, rdb, []string{key}).Result()
+        // INCR and EXPIRE go out as one MULTI/EXEC frame: a single round trip, and no
+        // script cache miss (EVALSHA -> EVAL) after a Redis restart or failover
+        var incr *redis.IntCmd
+        _, err := rdb.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+            incr = pipe.Incr(ctx, key)
+            pipe.Expire(ctx, key, time.Minute)
+            return nil
+        })
         if err != nil {
             // Allow request on Redis error
             c.Next()
             return
         }
-        count, _ := res.(int64)
-        if int(count) > reqPerMin {
+        count := incr.Val()
+        if limit := limit(c); limit > 0 && int(count) > limit {
             c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
             return
         }
         c.Next()
     }
 }
+
+// RateLimitConfigKey is the Redis key operators set to change the rate limit at runtime,
+// e.g. `redis-cli SET rate_limit_rpm 500`; `DEL rate_limit_rpm` reverts to the configured limit.
+const RateLimitConfigKey = "rate_limit_rpm"
+
+// rateLimitRefresh is how often RateLimitDynamic re-reads its Redis key.
+const rateLimitRefresh = 10 * time.Second
+
+// RateLimitDynamic is RateLimitRedis with the limit taken from the Redis key configKey,
+// so it can be changed with a plain SET and no restart. The key is read at most every
+// 10 seconds, in the background, and cached in between; while it is unset or not a
+// positive integer defaultRPM applies. If Redis is unreachable the last value read stays.
+func RateLimitDynamic(rdb *redis.Client, configKey string, defaultRPM int) gin.HandlerFunc {
+    return RateLimitDynamicFunc(rdb, configKey, func() int { return defaultRPM })
+}
+
+// RateLimitDynamicFunc is RateLimitDynamic with the fallback limit read on every request,
+// so a limit from a reloaded config file still applies when no Redis override is set.
+func RateLimitDynamicFunc(rdb *redis.Client, configKey string, defaultRPM func() int) gin.HandlerFunc {
+    if rdb == nil { return RateLimit(defaultRPM()) }
+    return RateLimitRedisFunc(DynamicRPM(rdb, configKey, defaultRPM), rdb)
+}
+
+// DynamicRPM returns the limit source behind RateLimitDynamicFunc, for limiters that scale
+// it further (see AdaptiveRateLimiter). With a nil client it is defaultRPM.
+func DynamicRPM(rdb *redis.Client, configKey string, defaultRPM func() int) func() int {
+    if rdb == nil { return defaultRPM }
+    var override atomic.Int64    // 0 = no override
+    var nextRefresh atomic.Int64 // unix nanoseconds
+    refresh := func() {
+        ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+        defer cancel()
+        v, err := rdb.Get(ctx, configKey).Int64()
+        var numErr *strconv.NumError
+        switch {
+        case errors.Is(err, redis.Nil):
+            v = 0
+        case errors.As(err, &numErr) || (err == nil && v <= 0):
+            slog.Warn("ignoring invalid rate limit override", "key", configKey)
+            v = 0
+        case err != nil:
+            return // keep the last value until Redis is back
+        }
+        if prev := override.Swap(v); prev != v {
+            slog.Info("rate limit override changed", "key", configKey, "from_rpm", prev, "to_rpm", v)
+        }
+    }
+    refresh()
+    nextRefresh.Store(time.Now().Add(rateLimitRefresh).UnixNano())
+    return func() int {
+        // Only the request that wins the swap triggers a refresh
+        if next, now := nextRefresh.Load(), time.Now().UnixNano(); now >= next &&
+            nextRefresh.CompareAndSwap(next, now+int64(rateLimitRefresh)) {
+            go refresh()
+        }
+        if v := override.Load(); v > 0 { return int(v) }
+        return defaultRPM()
+    }
+}
+
+const (
+    adaptiveWindow     = 5 * time.Minute  // rolling window error rates are measured over
+    adaptiveCacheTTL   = 30 * time.Second // how long a client's multiplier is reused
+    adaptiveMinSamples = 20               // fewer requests than this in the window keep the base limit
+)
+
+// AdaptiveRateLimiter scales a base per-minute limit by each client's recent 4xx rate:
+// clients under 1% errors over the last 5 minutes get twice the base limit, clients over
+// 10% get half. Requests and errors are kept per client IP in Redis sorted sets scored by
+// time, so every replica sees the same history; the resulting multiplier is cached in
+// Redis for 30 seconds. 429s from the limiter itself are not counted as errors, otherwise
+// a throttled client could never recover.
+type AdaptiveRateLimiter struct {
+    rdb     *redis.Client
+    baseRPM func() int
+}
+
+// NewAdaptiveRateLimiter returns a limiter whose base limit is read from baseRPM on every
+// request, e.g. DynamicRPM so the Redis override still applies.
+func NewAdaptiveRateLimiter(rdb *redis.Client, baseRPM func() int) *AdaptiveRateLimiter {
+    return &AdaptiveRateLimiter{rdb: rdb, baseRPM: baseRPM}
+}
+
+// Middleware enforces the adaptive limit and records each response for the client's
+// error rate. Without Redis it falls back to the in-memory limiter at the base limit.
+func (a *AdaptiveRateLimiter) Middleware() gin.HandlerFunc {
+    if a.rdb == nil { return RateLimit(a.baseRPM()) }
+    limit := rateLimitRedis(a.rdb, func(c *gin.Context) int {
+        base := a.baseRPM()
+        if base <= 0 { return base }
+        return max(1, int(float64(base)*a.Multiplier(c.Request.Context(), c.ClientIP())))
+    })
+    return func(c *gin.Context) {
+        limit(c)
+        if status := c.Writer.Status(); status != http.StatusTooManyRequests {
+            a.record(c.ClientIP(), status >= 400 && status < 500)
+        }
+    }
+}
+
+// Multiplier returns the factor applied to the base limit for client: 2 below a 1% error
+// rate, 0.5 above 10%, otherwise 1. It is 1 on Redis errors and for clients with too few
+// requests in the window to judge.
+func (a *AdaptiveRateLimiter) Multiplier(ctx context.Context, client string) float64 {
+    cacheKey := "arl:mult:" + client
+    if v, err := a.rdb.Get(ctx, cacheKey).Float64(); err == nil { return v }
+    since := strconv.FormatInt(time.Now().Add(-adaptiveWindow).UnixNano(), 10)
+    var reqs, errs *redis.IntCmd
+    _, err := a.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+        reqs = pipe.ZCount(ctx, "arl:req:"+client, since, "+inf")
+        errs = pipe.ZCount(ctx, "arl:err:"+client, since, "+inf")
+        return nil
+    })
+    if err != nil { return 1 }
+    m := 1.0
+    if n := reqs.Val(); n >= adaptiveMinSamples {
+        switch rate := float64(errs.Val()) / float64(n); {
+        case rate < 0.01:
+            m = 2
+        case rate > 0.10:
+            m = 0.5
+        }
+    }
+    a.rdb.Set(ctx, cacheKey, m, adaptiveCacheTTL)
+    return m
+}
+
+// record adds one request, and one error if isErr, to client's sorted sets and trims
+// entries that have left the window.
+func (a *AdaptiveRateLimiter) record(client string, isErr bool) {
+    ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+    defer cancel()
+    now := time.Now()
+    member := redis.Z{Score: float64(now.UnixNano()), Member: strconv.FormatInt(now.UnixNano(), 36) + ":" + strconv.FormatUint(rand.Uint64(), 36)}
+    cutoff := strconv.FormatInt(now.Add(-adaptiveWindow).UnixNano(), 10)
+    keys := []string{"arl:req:" + client}
+    if isErr { keys = append(keys, "arl:err:"+client) }
+    _, err := a.rdb.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+        for _, key := range keys {
+            pipe.ZAdd(ctx, key, member)
+            pipe.ZRemRangeByScore(ctx, key, "-inf", "("+cutoff)
+            pipe.Expire(ctx, key, adaptiveWindow)
+        }
+        return nil
+    })
+    if err != nil { slog.Debug("adaptive rate limit: failed to record request", "client", client, "error", err) }
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+    "fmt"
+    "log/slog"
+    "net/http"
+    "time"
+
+    "github.com/getsentry/sentry-go"
+    "github.com/gin-gonic/gin"
+    "go.opentelemetry.io/otel/trace"
+
+    "garp-backend/internal/otel"
+)
+
+// SentryRecovery recovers panics like gin.Recovery and reports them, plus any errors handlers
+// attached with c.Error, to Sentry. Events carry the request (Authorization redacted), the user
+// from otel.UserIDKey, the OTEL trace and span IDs as tags, and the Go stack trace. Register it
+// before otel.Middleware so the span is available. With an empty dsn it is plain gin.Recovery.
+func SentryRecovery(dsn string) gin.HandlerFunc {
+    if dsn == "" { return gin.Recovery() }
+    if err := sentry.Init(sentry.ClientOptions{Dsn: dsn, AttachStacktrace: true}); err != nil {
+        slog.Error("sentry init failed, falling back to plain recovery", "error", err)
+        return gin.Recovery()
+    }
+    return func(c *gin.Context) {
+        defer func() {
+            if r := recover(); r != nil {
+                slog.Error("panic recovered", "path", c.Request.URL.Path, "panic", fmt.Sprint(r))
+                sentryHub(c).RecoverWithContext(c.Request.Context(), r)
+                c.AbortWithStatus(http.StatusInternalServerError)
+            }
+        }()
+        c.Next()
+        if len(c.Errors) > 0 {
+            hub := sentryHub(c)
+            for _, e := range c.Errors { hub.CaptureException(e.Err) }
+        }
+    }
+}
+
+// FlushSentry waits up to timeout for queued events to be sent; call it before exiting.
+func FlushSentry(timeout time.Duration) { sentry.Flush(timeout) }
+
+// sentryHub returns a per-request hub whose scope describes c.
+func sentryHub(c *gin.Context) *sentry.Hub {
+    hub := sentry.CurrentHub().Clone()
+    scope := hub.Scope()
+    req := c.Request.Clone(c.Request.Context())
+    if req.Header.Get("Authorization") != "" { req.Header.Set("Authorization", "[Filtered]") }
+    scope.SetRequest(req)
+    if uid := c.GetString(otel.UserIDKey); uid != "" { scope.SetUser(sentry.User{ID: uid}) }
+    if sc := trace.SpanContextFromContext(c.Request.Context()); sc.IsValid() {
+        scope.SetTag("trace_id", sc.TraceID().String())
+        scope.SetTag("span_id", sc.SpanID().String())
+    }
+    if id := c.Writer.Header().Get(RequestIDHeader); id != "" { scope.SetTag("request_id", id) }
+    scope.SetTag("route", c.FullPath())
+    return hub
+}
\ No newline at end of file
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+    "net/http"
+    "regexp"
+
+    "github.com/gin-gonic/gin"
+
+    "garp-backend/internal/logger"
+    "garp-backend/internal/tenant"
+)
+
+// validTenantID bounds tenant IDs to characters that are safe inside Redis keys and logs.
+var validTenantID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// TenantExtractor reads the tenant ID from headerName and stores it in the request context
+// with tenant.WithID. Requests without the header use the default tenant; malformed IDs are
+// rejected with 400.
+func TenantExtractor(headerName string) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := c.GetHeader(headerName)
+        if id == "" {
+            c.Next()
+            return
+        }
+        if !validTenantID.MatchString(id) {
+            c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "invalid " + headerName + " header"})
+            return
+        }
+        ctx := tenant.WithID(c.Request.Context(), id)
+        ctx = logger.WithLogger(ctx, logger.FromContext(ctx).With("tenant_id", id))
+        c.Request = c.Request.WithContext(ctx)
+        c.Next()
+    }
+}
\ No newline at end of file
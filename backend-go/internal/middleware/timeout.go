@@ -0,0 +1,165 @@
+package middleware
+
+import (
+    "bufio"
+    "context"
+    "errors"
+    "net"
+    "net/http"
+    "strconv"
+    "sync"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+// timeoutBody is the response RequestTimeout sends once the deadline passes.
+const timeoutBody = `{"error":"request timed out"}`
+
+// RequestTimeout bounds each request with a context deadline of d. Handlers see the
+// deadline through c.Request.Context(), so database and upstream calls are cancelled when
+// it passes. If the response has not started by then, the client gets 503 at the deadline
+// and whatever the handler writes afterwards is silently discarded. A response already under way is
+// left to the handler. Routes in exempt, matched against c.FullPath(), are long-lived
+// streams and get no deadline.
+func RequestTimeout(d time.Duration, exempt map[string]bool) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        if d <= 0 || exempt[c.FullPath()] {
+            c.Next()
+            return
+        }
+        ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+        defer cancel()
+        c.Request = c.Request.WithContext(ctx)
+
+        tw := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx, header: c.Writer.Header().Clone()}
+        c.Writer = tw
+        fired := make(chan struct{})
+        stop := context.AfterFunc(ctx, func() {
+            defer close(fired)
+            if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+                tw.mu.Lock()
+                tw.begin() // sends the 503 unless the response has started
+                tw.mu.Unlock()
+            }
+        })
+
+        defer func() {
+            if !stop() { <-fired }
+            c.Writer = tw.ResponseWriter
+            if tw.finish() { c.Abort() }
+        }()
+        c.Next()
+    }
+}
+
+// timeoutWriter lets RequestTimeout answer from another goroutine while the handler is
+// still running. The handler's headers are kept apart until the response starts, and
+// every write is serialised with the timeout.
+type timeoutWriter struct {
+    gin.ResponseWriter
+    ctx      context.Context
+    mu       sync.Mutex
+    header   http.Header
+    started  bool
+    timedOut bool
+}
+
+// begin starts the response and reports whether the handler may write it. Past the
+// deadline a response that has not started becomes the 503, whichever goroutine gets
+// here first; otherwise the handler's headers are copied into the real response. The
+// caller holds mu.
+func (w *timeoutWriter) begin() bool {
+    if w.timedOut { return false }
+    if !w.started && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+        w.timeout()
+        return false
+    }
+    if !w.started {
+        dst := w.ResponseWriter.Header()
+        for k := range dst { delete(dst, k) }
+        for k, v := range w.header { dst[k] = v }
+        w.started = true
+    }
+    return true
+}
+
+// timeout sends the 503. The caller holds mu and has checked the response has not started.
+func (w *timeoutWriter) timeout() {
+    w.timedOut = true
+    // The real header map still holds only what earlier middleware set, e.g. the request ID
+    h := w.ResponseWriter.Header()
+    h.Set("Content-Type", "application/json; charset=utf-8")
+    // With a length the client has the whole response now, though the handler still holds the connection
+    h.Set("Content-Length", strconv.Itoa(len(timeoutBody)))
+    w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+    _, _ = w.ResponseWriter.WriteString(timeoutBody)
+    w.ResponseWriter.Flush()
+}
+
+// finish hands the response back after the handler returns and reports whether it timed out.
+func (w *timeoutWriter) finish() bool {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    w.begin()
+    return w.timedOut
+}
+
+func (w *timeoutWriter) Header() http.Header { return w.header }
+
+func (w *timeoutWriter) WriteHeader(code int) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if !w.timedOut { w.ResponseWriter.WriteHeader(code) }
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if w.begin() { w.ResponseWriter.WriteHeaderNow() }
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if !w.begin() { return len(b), nil } // discarded; gin's renderers panic on write errors
+    return w.ResponseWriter.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if !w.begin() { return len(s), nil }
+    return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) Flush() {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if w.begin() { w.ResponseWriter.Flush() }
+}
+
+func (w *timeoutWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    if !w.begin() { return nil, nil, http.ErrHandlerTimeout }
+    return w.ResponseWriter.Hijack()
+}
+
+func (w *timeoutWriter) Status() int {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    return w.ResponseWriter.Status()
+}
+
+func (w *timeoutWriter) Size() int {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    return w.ResponseWriter.Size()
+}
+
+func (w *timeoutWriter) Written() bool {
+    w.mu.Lock()
+    defer w.mu.Unlock()
+    return w.timedOut || w.ResponseWriter.Written()
+}
\ No newline at end of file
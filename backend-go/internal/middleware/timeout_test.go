@@ -0,0 +1,83 @@
+package middleware
+
+import (
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "testing"
+    "time"
+
+    "github.com/gin-gonic/gin"
+)
+
+func TestRequestTimeout(t *testing.T) {
+    gin.SetMode(gin.TestMode)
+    const deadline = 50 * time.Millisecond
+    release := make(chan struct{})
+
+    r := gin.New()
+    r.Use(RequestID(), RequestTimeout(deadline, map[string]bool{"/events": true}))
+    // Answers 502 once its upstream call is cancelled, then lingers like a slow cleanup
+    r.GET("/slow", func(c *gin.Context) {
+        <-c.Request.Context().Done()
+        c.JSON(http.StatusBadGateway, gin.H{"error": "upstream failed"})
+        <-release
+    })
+    r.GET("/fast", func(c *gin.Context) {
+        c.Header("X-Handler", "fast")
+        c.JSON(http.StatusCreated, gin.H{"ok": true})
+    })
+    r.GET("/events", func(c *gin.Context) {
+        time.Sleep(2 * deadline)
+        c.String(http.StatusOK, "data: done\n\n")
+    })
+    // The Accept header no longer opts a route out of the deadline
+    r.GET("/slow-sse", func(c *gin.Context) {
+        <-c.Request.Context().Done()
+        c.Status(http.StatusOK)
+    })
+    srv := httptest.NewServer(r)
+    defer srv.Close()
+    defer close(release) // before Close, which waits for /slow's handler
+
+    tests := []struct {
+        path     string
+        accept   string
+        wantCode int
+        wantBody string
+    }{
+        {path: "/slow", wantCode: http.StatusServiceUnavailable, wantBody: timeoutBody},
+        {path: "/fast", wantCode: http.StatusCreated, wantBody: `{"ok":true}`},
+        {path: "/events", wantCode: http.StatusOK, wantBody: "data: done\n\n"},
+        {path: "/slow-sse", accept: "text/event-stream", wantCode: http.StatusServiceUnavailable, wantBody: timeoutBody},
+    }
+    for _, tt := range tests {
+        t.Run(tt.path, func(t *testing.T) {
+            req, _ := http.NewRequest(http.MethodGet, srv.URL+tt.path, nil)
+            req.Close = true // /slow's handler keeps its connection until the test ends
+            if tt.accept != "" {
+                req.Header.Set("Accept", tt.accept)
+            }
+            start := time.Now()
+            resp, err := http.DefaultClient.Do(req)
+            if err != nil {
+                t.Fatal(err)
+            }
+            defer resp.Body.Close()
+            body, _ := io.ReadAll(resp.Body)
+            if resp.StatusCode != tt.wantCode || string(body) != tt.wantBody {
+                t.Fatalf("got %d %q, want %d %q", resp.StatusCode, body, tt.wantCode, tt.wantBody)
+            }
+            if resp.Header.Get(RequestIDHeader) == "" {
+                t.Error("response lost the request ID header set before the timeout middleware")
+            }
+            // /slow's handler never returns during the test, so its 503 must come from the deadline
+            if tt.wantCode == http.StatusServiceUnavailable && time.Since(start) > time.Second {
+                t.Errorf("503 took %v, want it at the %v deadline", time.Since(start), deadline)
+            }
+            if tt.path == "/fast" && resp.Header.Get("X-Handler") != "fast" {
+                t.Error("handler header missing from the response")
+            }
+        })
+    }
+}
\ No newline at end of file
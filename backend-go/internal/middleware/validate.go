@@ -0,0 +1,92 @@
+package middleware
+
+import (
+    "bytes"
+    "encoding/json"
+    "errors"
+    "io"
+    "net/http"
+    "path"
+
+    "github.com/gin-gonic/gin"
+    "github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+// ValidationFailure is one failed schema keyword, reported in the 422 response body.
+type ValidationFailure struct {
+    Keyword string `json:"keyword"` // e.g. "required", "minimum"
+    Path    string `json:"path"`    // JSON pointer into the request body
+    Message string `json:"message"`
+}
+
+// ErrInvalidJSON is returned by BodySchema.Check for a body that is not a JSON document.
+var ErrInvalidJSON = errors.New("invalid JSON body")
+
+// BodySchema is a compiled JSON Schema (draft-07) document for request bodies.
+type BodySchema struct {
+    sch *jsonschema.Schema
+}
+
+// CompileBodySchema compiles schema and panics if it is invalid, since schemas are
+// embedded at build time.
+func CompileBodySchema(schema []byte) *BodySchema {
+    compiler := jsonschema.NewCompiler()
+    compiler.Draft = jsonschema.Draft7
+    if err := compiler.AddResource("body.json", bytes.NewReader(schema)); err != nil { panic(err) }
+    return &BodySchema{sch: compiler.MustCompile("body.json")}
+}
+
+// Check validates body and returns every failed keyword, or nil if body is valid.
+// It returns ErrInvalidJSON if body does not parse.
+func (s *BodySchema) Check(body []byte) ([]ValidationFailure, error) {
+    dec := json.NewDecoder(bytes.NewReader(body))
+    dec.UseNumber()
+    var doc interface{}
+    if err := dec.Decode(&doc); err != nil { return nil, ErrInvalidJSON }
+    if err := s.sch.Validate(doc); err != nil {
+        var ve *jsonschema.ValidationError
+        if !errors.As(err, &ve) { return nil, err }
+        return validationFailures(ve, nil), nil
+    }
+    return nil, nil
+}
+
+// ValidateBody checks request bodies against a JSON Schema (draft-07) document and
+// rejects invalid ones with 422 listing every failed keyword. The body is restored afterwards so handlers can bind it as usual.
+func ValidateBody(schema []byte) gin.HandlerFunc {
+    sch := CompileBodySchema(schema)
+
+    return func(c *gin.Context) {
+        body, err := io.ReadAll(c.Request.Body)
+        if err != nil {
+            abortBodyReadError(c, err)
+            return
+        }
+        c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+        failures, err := sch.Check(body)
+        switch {
+        case errors.Is(err, ErrInvalidJSON):
+            c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+            return
+        case err != nil:
+            c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+            return
+        case len(failures) > 0:
+            c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "request body failed validation", "details": failures})
+            return
+        }
+        c.Next()
+    }
+}
+
+// validationFailures flattens the error tree into its leaves, which are the keywords that actually failed.
+func validationFailures(ve *jsonschema.ValidationError, out []ValidationFailure) []ValidationFailure {
+    if len(ve.Causes) == 0 {
+        loc := ve.InstanceLocation
+        if loc == "" { loc = "/" }
+        return append(out, ValidationFailure{Keyword: path.Base(ve.KeywordLocation), Path: loc, Message: ve.Message})
+    }
+    for _, cause := range ve.Causes { out = validationFailures(cause, out) }
+    return out
+}
\ No newline at end of file
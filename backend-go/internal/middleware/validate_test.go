@@ -0,0 +1,44 @@
+package middleware
+
+import (
+    "errors"
+    "testing"
+
+    "garp-backend/schema"
+)
+
+func TestBodySchemaCheck(t *testing.T) {
+    sch := CompileBodySchema(schema.Transaction)
+    tests := []struct {
+        name        string
+        body        string
+        wantErr     error
+        wantKeyword string
+    }{
+        {name: "valid", body: `{"submitter":"alice","commands":[{"type":"transfer"}]}`},
+        {name: "not JSON", body: `{"submitter":`, wantErr: ErrInvalidJSON},
+        {name: "missing commands", body: `{"submitter":"alice"}`, wantKeyword: "required"},
+        {name: "empty commands", body: `{"submitter":"alice","commands":[]}`, wantKeyword: "minItems"},
+        {name: "negative nonce", body: `{"submitter":"alice","commands":[{"type":"t"}],"nonce":-1}`, wantKeyword: "minimum"},
+    }
+    for _, tt := range tests {
+        t.Run(tt.name, func(t *testing.T) {
+            failures, err := sch.Check([]byte(tt.body))
+            if !errors.Is(err, tt.wantErr) {
+                t.Fatalf("err = %v, want %v", err, tt.wantErr)
+            }
+            if tt.wantKeyword == "" {
+                if len(failures) != 0 {
+                    t.Fatalf("failures = %+v, want none", failures)
+                }
+                return
+            }
+            for _, f := range failures {
+                if f.Keyword == tt.wantKeyword {
+                    return
+                }
+            }
+            t.Fatalf("failures = %+v, want keyword %q", failures, tt.wantKeyword)
+        })
+    }
+}
\ No newline at end of file
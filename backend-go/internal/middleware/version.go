@@ -0,0 +1,35 @@
+package middleware
+
+import (
+    "net/http"
+    "strings"
+
+    "github.com/gin-gonic/gin"
+)
+
+// MediaTypeV2 is the Accept media type that selects version 2 of the API.
+const MediaTypeV2 = "application/vnd.garp.v2+json"
+
+// APIVersionNegotiation sends /api/v1 requests whose Accept header asks for MediaTypeV2 to
+// v2 as /api/v2 with the rest of the path unchanged, e.g. GET /api/v1/blocks/7 becomes
+// GET /api/v2/blocks/7. The request is handed to v2 in process rather than redirected, so
+// it works behind the gateway's /backend prefix and keeps POST bodies. Other requests
+// continue down the chain.
+func APIVersionNegotiation(v2 http.Handler) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        rest, ok := strings.CutPrefix(c.Request.URL.Path, "/api/v1/")
+        if !ok {
+            c.Next()
+            return
+        }
+        c.Writer.Header().Add("Vary", "Accept")
+        if !strings.Contains(c.GetHeader("Accept"), MediaTypeV2) {
+            c.Next()
+            return
+        }
+        c.Request.URL.Path = "/api/v2/" + rest
+        c.Request.URL.RawPath = ""
+        v2.ServeHTTP(c.Writer, c.Request)
+        c.Abort()
+    }
+}
\ No newline at end of file
@@ -2,9 +2,17 @@ package otel
 
 import (
     "context"
-    "log"
+    "fmt"
+    "log/slog"
+    "net/http"
+    "strings"
     "github.com/gin-gonic/gin"
     "go.opentelemetry.io/otel"
+    "go.opentelemetry.io/otel/attribute"
+    "go.opentelemetry.io/otel/codes"
+    "go.opentelemetry.io/otel/propagation"
+    "go.opentelemetry.io/otel/trace"
+    "garp-backend/internal/logger"
     sdktrace "go.opentelemetry.io/otel/sdk/trace"
     "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 )
@@ -12,18 +20,105 @@ import (
 func Init(endpoint string, serviceName string) func(context.Context) error {
     if endpoint == "" { return func(ctx context.Context) error { return nil } }
     exp, err := otlptracehttp.New(context.Background(), otlptracehttp.WithEndpoint(endpoint), otlptracehttp.WithInsecure())
-    if err != nil { log.Printf("otel exporter init failed: %v", err); return func(ctx context.Context) error { return nil } }
+    if err != nil { slog.Error("otel exporter init failed", "endpoint", endpoint, "error", err); return func(ctx context.Context) error { return nil } }
     tp := sdktrace.NewTracerProvider(sdktrace.WithBatcher(exp))
     otel.SetTracerProvider(tp)
     return tp.Shutdown
 }
 
+// UserIDKey is the Gin context key auth middleware uses for the authenticated user ID.
+const UserIDKey = "user_id"
+
 func Middleware(serviceName string) gin.HandlerFunc {
-    tracer := otel.Tracer(serviceName)
     return func(c *gin.Context) {
+        // Look the tracer up per request so a provider swapped in by Init (e.g. on config reload) is used
+        tracer := otel.Tracer(serviceName)
         ctx, span := tracer.Start(c.Request.Context(), c.FullPath())
         defer span.End()
         c.Request = c.Request.WithContext(ctx)
+        if c.Request.ContentLength >= 0 {
+            span.SetAttributes(attribute.Int64("http.request_content_length", c.Request.ContentLength))
+        }
+        if strings.HasPrefix(c.FullPath(), "/api/v1/transactions/:id") {
+            span.SetAttributes(attribute.String("garp.transaction_id", c.Param("id")))
+        }
+        c.Next()
+        // Auth runs further down the chain, so the user is only known afterwards
+        if uid := c.GetString(UserIDKey); uid != "" {
+            span.SetAttributes(attribute.String("enduser.id", uid))
+        }
+        span.SetAttributes(attribute.Int("http.status_code", c.Writer.Status()))
+        if c.Writer.Status() >= 500 {
+            span.SetStatus(codes.Error, http.StatusText(c.Writer.Status()))
+        }
+    }
+}
+
+// AddSpanAttr annotates the span in ctx, if any, with key=value.
+// Values that are not bool, integer, float or string are recorded with fmt.Sprint.
+func AddSpanAttr(ctx context.Context, key string, value interface{}) {
+    span := trace.SpanFromContext(ctx)
+    if !span.IsRecording() { return }
+    var kv attribute.KeyValue
+    switch v := value.(type) {
+    case bool:
+        kv = attribute.Bool(key, v)
+    case int:
+        kv = attribute.Int(key, v)
+    case int64:
+        kv = attribute.Int64(key, v)
+    case uint64:
+        kv = attribute.Int64(key, int64(v))
+    case float64:
+        kv = attribute.Float64(key, v)
+    case string:
+        kv = attribute.String(key, v)
+    case []string:
+        kv = attribute.StringSlice(key, v)
+    default:
+        kv = attribute.String(key, fmt.Sprint(v))
+    }
+    span.SetAttributes(kv)
+}
+
+// LoggerKey is the Gin context key under which TraceLogMiddleware stores the request logger.
+const LoggerKey = "logger"
+
+// TraceLogMiddleware derives a logger carrying trace_id and span_id from the current span and
+// stores it in both the Gin context and the request context (see logger.FromContext), so log
+// lines can be joined with traces. It must run after Middleware.
+func TraceLogMiddleware(l *slog.Logger) gin.HandlerFunc {
+    return func(c *gin.Context) {
+        sc := trace.SpanFromContext(c.Request.Context()).SpanContext()
+        child := l
+        if sc.IsValid() {
+            child = l.With(slog.String("trace_id", sc.TraceID().String()), slog.String("span_id", sc.SpanID().String()))
+        }
+        c.Set(LoggerKey, child)
+        c.Request = c.Request.WithContext(logger.WithLogger(c.Request.Context(), child))
         c.Next()
     }
+}
+
+// traceContextTransport injects W3C trace context into every outgoing request.
+type traceContextTransport struct {
+    base http.RoundTripper
+}
+
+func (t traceContextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+    // RoundTrippers must not modify the caller's request
+    req = req.Clone(req.Context())
+    propagation.TraceContext{}.Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+    return t.base.RoundTrip(req)
+}
+
+// HTTPInjectMiddleware returns a copy of client whose requests carry the traceparent and
+// tracestate headers of the span in their context, so downstream services that are also
+// instrumented join the same trace. Requests without a span are sent unchanged.
+func HTTPInjectMiddleware(client *http.Client) *http.Client {
+    out := *client
+    base := out.Transport
+    if base == nil { base = http.DefaultTransport }
+    out.Transport = traceContextTransport{base: base}
+    return &out
 }
\ No newline at end of file
@@ -0,0 +1,40 @@
+package state
+
+import "container/list"
+
+// DefaultBlockCacheCapacity is the number of recent blocks NewStore keeps in memory.
+const DefaultBlockCacheCapacity = 1024
+
+// blockCache is a fixed-size LRU of blocks keyed by number. It is not safe for
+// concurrent use on its own; Store guards it with its mutex.
+type blockCache struct {
+    capacity int
+    order    *list.List               // front = most recently used
+    items    map[uint64]*list.Element // value is BlockInfo
+}
+
+func newBlockCache(capacity int) *blockCache {
+    if capacity <= 0 { capacity = DefaultBlockCacheCapacity }
+    return &blockCache{capacity: capacity, order: list.New(), items: make(map[uint64]*list.Element, capacity)}
+}
+
+func (c *blockCache) put(b BlockInfo) {
+    if el, ok := c.items[b.Number]; ok {
+        el.Value = b
+        c.order.MoveToFront(el)
+        return
+    }
+    c.items[b.Number] = c.order.PushFront(b)
+    if c.order.Len() > c.capacity {
+        oldest := c.order.Back()
+        c.order.Remove(oldest)
+        delete(c.items, oldest.Value.(BlockInfo).Number)
+    }
+}
+
+func (c *blockCache) get(number uint64) (BlockInfo, bool) {
+    el, ok := c.items[number]
+    if !ok { return BlockInfo{}, false }
+    c.order.MoveToFront(el)
+    return el.Value.(BlockInfo), true
+}
\ No newline at end of file
@@ -1,6 +1,10 @@
 package state
 
 import (
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
     "sync"
     "time"
 )
@@ -36,13 +40,19 @@ type Store struct {
     txs map[string]Transaction
     accounts map[string]Account
     latest BlockInfo
+    blocks *blockCache
 }
 
-func NewStore() *Store {
+func NewStore() *Store { return NewStoreWithCache(DefaultBlockCacheCapacity) }
+
+// NewStoreWithCache creates a store that keeps the last capacity blocks passed to
+// SetLatestBlock in an LRU cache, so recently seen blocks are served without a database round trip.
+func NewStoreWithCache(capacity int) *Store {
     return &Store{
         txs: make(map[string]Transaction),
         accounts: make(map[string]Account),
         latest: BlockInfo{Number: 1, Hash: "0xgenesis", ParentHash: "0x0", Timestamp: time.Now().UTC(), TransactionCount: 0, Size: 1024, GasUsed: 0, GasLimit: 1000000},
+        blocks: newBlockCache(capacity),
     }
 }
 
@@ -53,4 +63,72 @@ func (s *Store) CreateAccount(a Account) { s.mu.Lock(); defer s.mu.Unlock(); s.a
 func (s *Store) GetAccount(addr string) (Account, bool) { s.mu.RLock(); defer s.mu.RUnlock(); v, ok := s.accounts[addr]; return v, ok }
 
 func (s *Store) LatestBlock() BlockInfo { s.mu.RLock(); defer s.mu.RUnlock(); return s.latest }
-func (s *Store) SetLatestBlock(b BlockInfo) { s.mu.Lock(); defer s.mu.Unlock(); s.latest = b }
\ No newline at end of file
+func (s *Store) SetLatestBlock(b BlockInfo) { s.mu.Lock(); defer s.mu.Unlock(); s.latest = b; s.blocks.put(b) }
+
+// GetCachedBlock returns a recently seen block by number. It takes the write lock
+// because a hit refreshes the block's LRU position.
+func (s *Store) GetCachedBlock(number uint64) (*BlockInfo, bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    b, ok := s.blocks.get(number)
+    if !ok { return nil, false }
+    return &b, true
+}
+
+// snapshot is the serialized form of a Store written by Snapshot.
+type snapshot struct {
+    Transactions map[string]Transaction `json:"transactions"`
+    Accounts     map[string]Account     `json:"accounts"`
+    Latest       BlockInfo              `json:"latest_block"`
+}
+
+// Snapshot writes the store's transactions, accounts and latest block to w as JSON. The
+// store is read-locked while encoding, so the snapshot is consistent.
+func (s *Store) Snapshot(ctx context.Context, w io.Writer) error {
+    if err := ctx.Err(); err != nil { return err }
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    if err := json.NewEncoder(w).Encode(snapshot{Transactions: s.txs, Accounts: s.accounts, Latest: s.latest}); err != nil {
+        return fmt.Errorf("failed to write state snapshot: %w", err)
+    }
+    return nil
+}
+
+// RestoreSnapshot replaces the store's transactions, accounts and latest block with those
+// read from r. The snapshot is decoded fully before anything is replaced, so a truncated or
+// invalid snapshot leaves the store untouched.
+func (s *Store) RestoreSnapshot(ctx context.Context, r io.Reader) error {
+    var snap snapshot
+    if err := json.NewDecoder(r).Decode(&snap); err != nil {
+        return fmt.Errorf("failed to read state snapshot: %w", err)
+    }
+    if err := ctx.Err(); err != nil { return err }
+    if snap.Transactions == nil { snap.Transactions = make(map[string]Transaction) }
+    if snap.Accounts == nil { snap.Accounts = make(map[string]Account) }
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.txs, s.accounts = snap.Transactions, snap.Accounts
+    if snap.Latest.Hash != "" {
+        s.latest = snap.Latest
+        s.blocks.put(snap.Latest)
+    }
+    return nil
+}
+
+// Transactions returns a snapshot of every transaction held in memory.
+func (s *Store) Transactions() []Transaction {
+    s.mu.RLock()
+    defer s.mu.RUnlock()
+    out := make([]Transaction, 0, len(s.txs))
+    for _, tx := range s.txs { out = append(out, tx) }
+    return out
+}
+
+// DeleteTx evicts a transaction and reports whether it was present.
+func (s *Store) DeleteTx(hash string) bool {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    _, ok := s.txs[hash]
+    delete(s.txs, hash)
+    return ok
+}
\ No newline at end of file
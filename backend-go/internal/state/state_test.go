@@ -0,0 +1,42 @@
+package state
+
+import "testing"
+
+func BenchmarkGetCachedBlock(b *testing.B) {
+    s := NewStoreWithCache(DefaultBlockCacheCapacity)
+    for n := uint64(0); n < DefaultBlockCacheCapacity; n++ {
+        s.SetLatestBlock(BlockInfo{Number: n, Hash: "h"})
+    }
+
+    b.Run("hit", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            if _, ok := s.GetCachedBlock(uint64(i) % DefaultBlockCacheCapacity); !ok {
+                b.Fatal("expected a cache hit")
+            }
+        }
+    })
+    b.Run("miss", func(b *testing.B) {
+        for i := 0; i < b.N; i++ {
+            if _, ok := s.GetCachedBlock(DefaultBlockCacheCapacity + uint64(i)); ok {
+                b.Fatal("expected a cache miss")
+            }
+        }
+    })
+}
+
+func TestBlockCacheEvictsLeastRecentlyUsed(t *testing.T) {
+    s := NewStoreWithCache(2)
+    s.SetLatestBlock(BlockInfo{Number: 1})
+    s.SetLatestBlock(BlockInfo{Number: 2})
+    s.GetCachedBlock(1)
+    s.SetLatestBlock(BlockInfo{Number: 3})
+
+    if _, ok := s.GetCachedBlock(2); ok {
+        t.Error("block 2 should have been evicted")
+    }
+    for _, n := range []uint64{1, 3} {
+        if b, ok := s.GetCachedBlock(n); !ok || b.Number != n {
+            t.Errorf("GetCachedBlock(%d) = %v, %v", n, b, ok)
+        }
+    }
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package state
+
+import (
+    "container/heap"
+    "errors"
+    "sync"
+)
+
+var ErrDuplicateTx = errors.New("transaction already in pool")
+
+// TxPool holds submitted transactions until they are processed, handing them out
+// oldest first by CreatedAt. It is safe for concurrent use.
+type TxPool struct {
+    mu     sync.Mutex
+    byHash map[string]Transaction
+    queue  txQueue
+}
+
+func NewTxPool() *TxPool { return &TxPool{byHash: make(map[string]Transaction)} }
+
+// Add queues tx, rejecting a hash that is already pooled with ErrDuplicateTx.
+func (p *TxPool) Add(tx Transaction) error {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if _, ok := p.byHash[tx.TxHash]; ok { return ErrDuplicateTx }
+    p.byHash[tx.TxHash] = tx
+    heap.Push(&p.queue, tx)
+    return nil
+}
+
+// Pop removes and returns up to n of the oldest transactions, oldest first.
+func (p *TxPool) Pop(n int) []Transaction {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    n = min(n, p.queue.Len())
+    if n <= 0 { return nil }
+    out := make([]Transaction, 0, n)
+    for range n {
+        tx := heap.Pop(&p.queue).(Transaction)
+        delete(p.byHash, tx.TxHash)
+        out = append(out, tx)
+    }
+    return out
+}
+
+func (p *TxPool) Size() int { p.mu.Lock(); defer p.mu.Unlock(); return len(p.byHash) }
+func (p *TxPool) Contains(hash string) bool { p.mu.Lock(); defer p.mu.Unlock(); _, ok := p.byHash[hash]; return ok }
+func (p *TxPool) Get(hash string) (Transaction, bool) { p.mu.Lock(); defer p.mu.Unlock(); v, ok := p.byHash[hash]; return v, ok }
+
+// txQueue is a min-heap of transactions by CreatedAt, for use with container/heap.
+type txQueue []Transaction
+
+func (q txQueue) Len() int           { return len(q) }
+func (q txQueue) Less(i, j int) bool { return q[i].CreatedAt.Before(q[j].CreatedAt) }
+func (q txQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+func (q *txQueue) Push(x any)        { *q = append(*q, x.(Transaction)) }
+func (q *txQueue) Pop() any {
+    old := *q
+    tx := old[len(old)-1]
+    *q = old[:len(old)-1]
+    return tx
+}
\ No newline at end of file
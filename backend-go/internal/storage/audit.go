@@ -0,0 +1,35 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+
+    "github.com/jackc/pgx/v5/pgxpool"
+
+    "garp-backend/internal/middleware"
+)
+
+// PostgresAuditStore appends audit entries to the audit_log table. The table rejects
+// UPDATE and DELETE (see migrations/0004_audit_log.sql), so pg should connect as a role
+// holding only INSERT and SELECT on it.
+type PostgresAuditStore struct {
+    pg *pgxpool.Pool
+}
+
+var _ middleware.AuditStore = (*PostgresAuditStore)(nil)
+
+func NewPostgresAuditStore(pg *pgxpool.Pool) *PostgresAuditStore {
+    return &PostgresAuditStore{pg: pg}
+}
+
+// Record inserts one entry.
+func (a *PostgresAuditStore) Record(ctx context.Context, e middleware.AuditEntry) error {
+    _, err := a.pg.Exec(ctx,
+        `INSERT INTO audit_log (ts, user_id, method, path, request_body_hash, status_code, ip_address)
+         VALUES ($1, NULLIF($2, ''), $3, $4, $5, $6, $7)`,
+        e.Timestamp, e.UserID, e.Method, e.Path, e.RequestBodyHash, e.StatusCode, e.IPAddress)
+    if err != nil {
+        return fmt.Errorf("failed to insert audit entry: %w", err)
+    }
+    return nil
+}
\ No newline at end of file
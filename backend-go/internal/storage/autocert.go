@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// autocertKeyPrefix namespaces ACME account keys and certificates in Redis.
+const autocertKeyPrefix = "autocert:"
+
+// RedisCache is an autocert.Cache backed by Redis, so certificates survive restarts and
+// are shared by every replica serving the same domain.
+type RedisCache struct{ rdb *redis.Client }
+
+// NewRedisCache returns a certificate cache stored in rdb.
+func NewRedisCache(rdb *redis.Client) *RedisCache { return &RedisCache{rdb: rdb} }
+
+// Get returns the cached data for key, or autocert.ErrCacheMiss.
+func (c *RedisCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := c.rdb.Get(ctx, autocertKeyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, autocert.ErrCacheMiss
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate cache %s: %w", key, err)
+	}
+	return data, nil
+}
+
+// Put stores data under key with no expiry; autocert renews certificates before they lapse.
+func (c *RedisCache) Put(ctx context.Context, key string, data []byte) error {
+	if err := c.rdb.Set(ctx, autocertKeyPrefix+key, data, 0).Err(); err != nil {
+		return fmt.Errorf("failed to write certificate cache %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete removes key; a missing key is not an error.
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	if err := c.rdb.Del(ctx, autocertKeyPrefix+key).Err(); err != nil {
+		return fmt.Errorf("failed to delete certificate cache %s: %w", key, err)
+	}
+	return nil
+}
\ No newline at end of file
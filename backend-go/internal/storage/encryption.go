@@ -0,0 +1,182 @@
+package storage
+
+import (
+    "context"
+    "crypto/aes"
+    "crypto/cipher"
+    "crypto/rand"
+    "encoding/base64"
+    "errors"
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    vault "github.com/hashicorp/vault/api"
+
+    "garp-backend/internal/tenant"
+)
+
+// dekCacheTTL is how long an unwrapped tenant DEK is kept in process before it is read again.
+const dekCacheTTL = 5 * time.Minute
+
+// KEKSource returns the key encryption key (AES-256, 32 bytes) that wraps tenant DEKs.
+type KEKSource interface {
+    KEK(ctx context.Context) ([]byte, error)
+}
+
+// KEKFunc adapts a function to KEKSource.
+type KEKFunc func(ctx context.Context) ([]byte, error)
+
+func (f KEKFunc) KEK(ctx context.Context) ([]byte, error) { return f(ctx) }
+
+// SecretReader fetches the latest version of a named secret; integration.GCPSecretManager
+// implements it.
+type SecretReader interface {
+    GetSecretLatest(ctx context.Context, secretName string) (string, error)
+}
+
+// SecretManagerKEK reads the KEK from secretName, whose payload is the base64-encoded key.
+func SecretManagerKEK(src SecretReader, secretName string) KEKSource {
+    return KEKFunc(func(ctx context.Context) ([]byte, error) {
+        payload, err := src.GetSecretLatest(ctx, secretName)
+        if err != nil { return nil, fmt.Errorf("read KEK: %w", err) }
+        return decodeKEK(payload)
+    })
+}
+
+// VaultKEK reads the KEK from field of the KV v2 secret mount/path, as a base64-encoded key.
+// client must already be authenticated.
+func VaultKEK(client *vault.Client, mount, path, field string) KEKSource {
+    return KEKFunc(func(ctx context.Context) ([]byte, error) {
+        secret, err := client.KVv2(mount).Get(ctx, path)
+        if err != nil { return nil, fmt.Errorf("read KEK from vault %s/%s: %w", mount, path, err) }
+        v, ok := secret.Data[field].(string)
+        if !ok { return nil, fmt.Errorf("vault %s/%s has no %q field", mount, path, field) }
+        return decodeKEK(v)
+    })
+}
+
+func decodeKEK(s string) ([]byte, error) {
+    kek, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+    if err != nil { return nil, fmt.Errorf("KEK is not base64: %w", err) }
+    if len(kek) != 32 { return nil, fmt.Errorf("KEK must be 32 bytes, got %d", len(kek)) }
+    return kek, nil
+}
+
+type cachedDEK struct {
+    aead    cipher.AEAD
+    expires time.Time
+}
+
+// EncryptedStorage adds envelope encryption of message content on top of Storage, so that
+// database access alone does not reveal it. Each tenant has a random data encryption key
+// (DEK) stored in tenant_keys wrapped by the KEK; content is sealed with the DEK using
+// AES-GCM and stored as nonce || ciphertext. Messages stored through it are only readable
+// through ListMessages here; Storage's own reads return the sealed content.
+type EncryptedStorage struct {
+    *Storage
+    kek  KEKSource
+    deks sync.Map // tenant ID -> cachedDEK
+}
+
+func NewEncryptedStorage(s *Storage, kek KEKSource) *EncryptedStorage {
+    return &EncryptedStorage{Storage: s, kek: kek}
+}
+
+// CreateEncryptedMessage stores a message for tenantID with its content sealed by the
+// tenant's DEK, creating the DEK on the tenant's first message. The returned message holds
+// the content as given.
+func (e *EncryptedStorage) CreateEncryptedMessage(ctx context.Context, tenantID, sender, recipient string, ciphertext, nonce []byte) (Message, error) {
+    ctx = tenant.WithID(ctx, tenantID)
+    in, err := e.sealInput(ctx, tenantID, MessageInput{Sender: sender, Recipient: recipient, Ciphertext: ciphertext, Nonce: nonce})
+    if err != nil { return Message{}, err }
+    m, err := e.createMessageInput(ctx, in)
+    if err != nil { return Message{}, err }
+    // An existing message with the same hash may have been returned instead
+    return m, e.open(ctx, tenantID, &m)
+}
+
+// sealInput returns in with its content sealed by the tenant's DEK and hashed as given.
+func (e *EncryptedStorage) sealInput(ctx context.Context, tenantID string, in MessageInput) (MessageInput, error) {
+    dek, err := e.dek(tenant.WithID(ctx, tenantID), tenantID)
+    if err != nil { return MessageInput{}, err }
+    sealed, err := seal(dek, in.Ciphertext, []byte(tenantID))
+    if err != nil { return MessageInput{}, err }
+    in.hash, in.envelope = hashMessage(in.Ciphertext, in.Nonce), true
+    in.Ciphertext = sealed
+    return in, nil
+}
+
+// ListMessages is Storage.ListMessages for tenantID with sealed content decrypted.
+func (e *EncryptedStorage) ListMessages(ctx context.Context, tenantID, a, b string, since *time.Time, limit int) ([]Message, error) {
+    ctx = tenant.WithID(ctx, tenantID)
+    msgs, err := e.Storage.ListMessages(ctx, a, b, since, limit)
+    if err != nil { return nil, err }
+    for i := range msgs {
+        if err := e.open(ctx, tenantID, &msgs[i]); err != nil { return nil, err }
+    }
+    return msgs, nil
+}
+
+// open decrypts m's content in place if it is sealed.
+func (e *EncryptedStorage) open(ctx context.Context, tenantID string, m *Message) error {
+    if !m.envelope { return nil }
+    dek, err := e.dek(ctx, tenantID)
+    if err != nil { return err }
+    plain, err := unseal(dek, m.ContentCiphertext, []byte(tenantID))
+    if err != nil { return fmt.Errorf("failed to decrypt message %d: %w", m.ID, err) }
+    m.ContentCiphertext = plain
+    m.envelope = false
+    return nil
+}
+
+// dek returns the tenant's DEK, from the cache if it was read in the last dekCacheTTL.
+func (e *EncryptedStorage) dek(ctx context.Context, tenantID string) (cipher.AEAD, error) {
+    if v, ok := e.deks.Load(tenantID); ok {
+        if c := v.(cachedDEK); time.Now().Before(c.expires) { return c.aead, nil }
+    }
+    kek, err := e.kek.KEK(ctx)
+    if err != nil { return nil, err }
+    kekAEAD, err := newGCM(kek)
+    if err != nil { return nil, err }
+    aad := []byte("tenant-dek:" + tenantID)
+
+    // Offer a fresh DEK and read back whichever one is stored, so instances racing on a
+    // tenant's first message agree on the key
+    fresh := make([]byte, 32)
+    if _, err := rand.Read(fresh); err != nil { return nil, err }
+    offered, err := seal(kekAEAD, fresh, aad)
+    if err != nil { return nil, err }
+    var wrapped []byte
+    err = e.PG.QueryRow(ctx,
+        `INSERT INTO tenant_keys(tenant_id, wrapped_dek) VALUES ($1,$2)
+         ON CONFLICT (tenant_id) DO UPDATE SET tenant_id = EXCLUDED.tenant_id
+         RETURNING wrapped_dek`, tenantID, offered).Scan(&wrapped)
+    if err != nil { return nil, fmt.Errorf("failed to load DEK for tenant %q: %w", tenantID, err) }
+    key, err := unseal(kekAEAD, wrapped, aad)
+    if err != nil { return nil, fmt.Errorf("failed to unwrap DEK for tenant %q: %w", tenantID, err) }
+    aead, err := newGCM(key)
+    if err != nil { return nil, err }
+    e.deks.Store(tenantID, cachedDEK{aead: aead, expires: time.Now().Add(dekCacheTTL)})
+    return aead, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+    block, err := aes.NewCipher(key)
+    if err != nil { return nil, err }
+    return cipher.NewGCM(block)
+}
+
+// seal returns nonce || ciphertext.
+func seal(aead cipher.AEAD, plain, aad []byte) ([]byte, error) {
+    nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(plain)+aead.Overhead())
+    if _, err := rand.Read(nonce); err != nil { return nil, err }
+    return aead.Seal(nonce, nonce, plain, aad), nil
+}
+
+func unseal(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+    if len(sealed) < aead.NonceSize() { return nil, errors.New("sealed data too short") }
+    n := aead.NonceSize()
+    return aead.Open(nil, sealed[:n], sealed[n:], aad)
+}
\ No newline at end of file
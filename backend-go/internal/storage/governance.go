@@ -0,0 +1,138 @@
+package storage
+
+import (
+    "context"
+    "crypto/rand"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/jackc/pgx/v5"
+)
+
+// Governance proposal states
+const (
+    ProposalOpen     = "open"
+    ProposalExecuted = "executed"
+    ProposalRejected = "rejected"
+)
+
+var (
+    ErrProposalNotFound = errors.New("proposal not found")
+    ErrProposalClosed   = errors.New("proposal is no longer open for voting")
+    ErrAlreadyVoted     = errors.New("voter has already voted on this proposal")
+)
+
+// ProposalTally counts the votes cast on a proposal.
+type ProposalTally struct {
+    Yes     int64 `json:"yes"`
+    No      int64 `json:"no"`
+    Abstain int64 `json:"abstain"`
+}
+
+// Passed reports whether yes votes outnumber no votes; abstentions count for neither.
+func (t ProposalTally) Passed() bool { return t.Yes > t.No }
+
+// Proposal is a governance proposal. When ContractID is set, Choice is exercised on that
+// contract with Arguments once the proposal passes.
+type Proposal struct {
+    ID            string          `json:"id"`
+    Proposer      string          `json:"proposer"`
+    Description   string          `json:"description"`
+    VotingEndSlot int64           `json:"voting_end_slot"`
+    ContractID    *string         `json:"contract_id,omitempty"`
+    Choice        *string         `json:"choice,omitempty"`
+    Arguments     json.RawMessage `json:"arguments,omitempty" swaggertype:"object"`
+    Status        string          `json:"status"`
+    Tally         ProposalTally   `json:"tally"`
+    CreatedAt     time.Time       `json:"created_at"`
+}
+
+const proposalColumns = `p.id, p.proposer, p.description, p.voting_end_slot, p.contract_id, p.choice, p.arguments, p.status, p.created_at,
+    COUNT(v.vote) FILTER (WHERE v.vote = 'yes'), COUNT(v.vote) FILTER (WHERE v.vote = 'no'), COUNT(v.vote) FILTER (WHERE v.vote = 'abstain')`
+
+func scanProposal(row pgx.Row) (Proposal, error) {
+    var p Proposal
+    err := row.Scan(&p.ID, &p.Proposer, &p.Description, &p.VotingEndSlot, &p.ContractID, &p.Choice, &p.Arguments, &p.Status, &p.CreatedAt,
+        &p.Tally.Yes, &p.Tally.No, &p.Tally.Abstain)
+    return p, err
+}
+
+// CreateProposal stores a new open proposal, assigning its ID.
+func (s *Storage) CreateProposal(ctx context.Context, p Proposal) (Proposal, error) {
+    var id [16]byte
+    if _, err := rand.Read(id[:]); err != nil { return p, err }
+    p.ID, p.Status = hex.EncodeToString(id[:]), ProposalOpen
+    var args interface{}
+    if len(p.Arguments) > 0 { args = p.Arguments }
+    err := s.PG.QueryRow(ctx,
+        `INSERT INTO governance_proposals (id, proposer, description, voting_end_slot, contract_id, choice, arguments, status)
+         VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING created_at`,
+        p.ID, p.Proposer, p.Description, p.VotingEndSlot, p.ContractID, p.Choice, args, p.Status).Scan(&p.CreatedAt)
+    if err != nil {
+        return p, fmt.Errorf("failed to create proposal: %w", err)
+    }
+    return p, nil
+}
+
+// GetProposal loads a proposal with its current tally.
+func (s *Storage) GetProposal(ctx context.Context, id string) (Proposal, error) {
+    p, err := scanProposal(s.PG.QueryRow(ctx,
+        `SELECT `+proposalColumns+` FROM governance_proposals p LEFT JOIN governance_votes v ON v.proposal_id = p.id
+         WHERE p.id = $1 GROUP BY p.id`, id))
+    if errors.Is(err, pgx.ErrNoRows) { return p, ErrProposalNotFound }
+    if err != nil { return p, fmt.Errorf("failed to load proposal: %w", err) }
+    return p, nil
+}
+
+// CastVote records voter's vote on an open proposal. Each voter votes once.
+func (s *Storage) CastVote(ctx context.Context, proposalID, voter, vote string) error {
+    tag, err := s.PG.Exec(ctx,
+        `INSERT INTO governance_votes (proposal_id, voter, vote)
+         SELECT id, $2, $3 FROM governance_proposals WHERE id = $1 AND status = $4
+         ON CONFLICT (proposal_id, voter) DO NOTHING`, proposalID, voter, vote, ProposalOpen)
+    if err != nil { return fmt.Errorf("failed to record vote: %w", err) }
+    if tag.RowsAffected() == 1 { return nil }
+
+    // Nothing inserted: work out why
+    var status string
+    err = s.PG.QueryRow(ctx, `SELECT status FROM governance_proposals WHERE id = $1`, proposalID).Scan(&status)
+    switch {
+    case errors.Is(err, pgx.ErrNoRows):
+        return ErrProposalNotFound
+    case err != nil:
+        return fmt.Errorf("failed to load proposal: %w", err)
+    case status != ProposalOpen:
+        return ErrProposalClosed
+    }
+    return ErrAlreadyVoted
+}
+
+// DueProposals returns open proposals whose voting ended at or before slot, oldest deadline first.
+func (s *Storage) DueProposals(ctx context.Context, slot int64, limit int) ([]Proposal, error) {
+    rows, err := s.PG.Query(ctx,
+        `SELECT `+proposalColumns+` FROM governance_proposals p LEFT JOIN governance_votes v ON v.proposal_id = p.id
+         WHERE p.status = $1 AND p.voting_end_slot <= $2
+         GROUP BY p.id ORDER BY p.voting_end_slot LIMIT $3`, ProposalOpen, slot, limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list due proposals: %w", err)
+    }
+    defer rows.Close()
+    var out []Proposal
+    for rows.Next() {
+        p, err := scanProposal(rows)
+        if err != nil { return nil, err }
+        out = append(out, p)
+    }
+    return out, rows.Err()
+}
+
+// FinalizeProposal moves an open proposal to status. It reports false if the proposal
+// was already finalized.
+func (s *Storage) FinalizeProposal(ctx context.Context, id, status string) (bool, error) {
+    tag, err := s.PG.Exec(ctx, `UPDATE governance_proposals SET status = $2 WHERE id = $1 AND status = $3`, id, status, ProposalOpen)
+    if err != nil { return false, fmt.Errorf("failed to finalize proposal: %w", err) }
+    return tag.RowsAffected() == 1, nil
+}
\ No newline at end of file
@@ -0,0 +1,101 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"time"
+)
+
+// healthCheckTimeout bounds each dependency probe in HealthReport.
+const healthCheckTimeout = 2 * time.Second
+
+// HealthCheck probes one dependency and returns nil when it is usable.
+type HealthCheck func(ctx context.Context) error
+
+// HealthStatus is the outcome of a single dependency probe.
+type HealthStatus struct {
+	OK      bool
+	Latency time.Duration
+	Error   string
+}
+
+// MarshalJSON reports latency in milliseconds so the payload is readable without unit conversion.
+func (h HealthStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		OK        bool    `json:"ok"`
+		LatencyMS float64 `json:"latency_ms"`
+		Error     string  `json:"error,omitempty"`
+	}{h.OK, float64(h.Latency.Microseconds()) / 1000, h.Error})
+}
+
+// RegisterHealthCheck adds an external dependency (e.g. an upstream service) to HealthReport.
+// Registering a name twice replaces the earlier check.
+func (s *Storage) RegisterHealthCheck(name string, check HealthCheck) {
+	s.checksMu.Lock()
+	defer s.checksMu.Unlock()
+	if s.checks == nil {
+		s.checks = make(map[string]HealthCheck)
+	}
+	s.checks[name] = check
+}
+
+// HealthReport probes Postgres, Redis and every registered check concurrently,
+// each with its own timeout, and returns the result keyed by dependency name.
+func (s *Storage) HealthReport(ctx context.Context) map[string]HealthStatus {
+	checks := map[string]HealthCheck{
+		"postgres": func(ctx context.Context) error {
+			if s.PG == nil {
+				return errors.New("not configured")
+			}
+			return s.PG.Ping(ctx)
+		},
+		"redis": func(ctx context.Context) error {
+			if s.Redis == nil {
+				return errors.New("not configured")
+			}
+			return s.Redis.Ping(ctx).Err()
+		},
+	}
+	s.checksMu.Lock()
+	for name, check := range s.checks {
+		checks[name] = check
+	}
+	s.checksMu.Unlock()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = make(map[string]HealthStatus, len(checks))
+	)
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check HealthCheck) {
+			defer wg.Done()
+			cctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+			defer cancel()
+			start := time.Now()
+			err := check(cctx)
+			st := HealthStatus{OK: err == nil, Latency: time.Since(start)}
+			if err != nil {
+				st.Error = err.Error()
+			}
+			mu.Lock()
+			report[name] = st
+			mu.Unlock()
+		}(name, check)
+	}
+	wg.Wait()
+	return report
+}
+
+// Healthy reports whether every entry in a HealthReport is OK.
+func Healthy(report map[string]HealthStatus) bool {
+	for _, st := range report {
+		if !st.OK {
+			return false
+		}
+	}
+	return true
+}
\ No newline at end of file
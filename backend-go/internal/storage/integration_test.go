@@ -0,0 +1,339 @@
+package storage_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"go.uber.org/goleak"
+
+	"garp-backend/internal/storage"
+	"garp-backend/internal/storage/storagetest"
+	"garp-backend/internal/tenant"
+)
+
+// store is shared by every test in the package; it is nil when Docker is unavailable.
+var store *storage.Storage
+
+func TestMain(m *testing.M) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Minute)
+	env, err := storagetest.Start(ctx)
+	cancel()
+	switch {
+	case errors.Is(err, storagetest.ErrNoDocker):
+		fmt.Fprintln(os.Stderr, "storage integration tests skipped:", err)
+	case err != nil:
+		fmt.Fprintln(os.Stderr, "failed to start storage containers:", err)
+		os.Exit(1)
+	default:
+		store = env.Storage
+	}
+
+	code := m.Run()
+	if env != nil {
+		env.Close()
+	}
+	// Everything the tests started, including pgx and go-redis pool goroutines, must be gone
+	if code == 0 {
+		if err := goleak.Find(storagetest.LeakOptions()...); err != nil {
+			fmt.Fprintln(os.Stderr, "goleak:", err)
+			code = 1
+		}
+	}
+	os.Exit(code)
+}
+
+func requireStore(t *testing.T) *storage.Storage {
+	t.Helper()
+	if store == nil {
+		t.Skip("Docker is not available")
+	}
+	t.Parallel()
+	return store
+}
+
+func TestReady(t *testing.T) {
+	s := requireStore(t)
+	if !s.Ready(context.Background()) {
+		t.Fatal("Ready() = false with both containers running")
+	}
+}
+
+func TestMigrations(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	for _, table := range []string{"multisig_transactions", "contract_events", "messages", "audit_log", "contract_records", "saga_log", "token_issuances", "stake_positions", "nft_metadata", "governance_proposals", "tenant_keys"} {
+		var exists bool
+		if err := s.PG.QueryRow(ctx, `SELECT to_regclass($1) IS NOT NULL`, table).Scan(&exists); err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Errorf("table %s was not created by the migrations", table)
+		}
+	}
+	// Deployments run the migrations on every start
+	if err := s.RunMigrations(ctx); err != nil {
+		t.Fatalf("re-running migrations: %v", err)
+	}
+}
+
+func TestSaveTx(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	hash := "tx-" + t.Name()
+	if err := s.SaveTx(ctx, hash, []byte(`{"kind":"transfer"}`)); err != nil {
+		t.Fatal(err)
+	}
+	// A resubmission is ignored rather than failing
+	if err := s.SaveTx(ctx, hash, []byte(`{"kind":"transfer"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	var status string
+	var payload []byte
+	if err := s.PG.QueryRow(ctx, `SELECT status, payload FROM transactions WHERE tx_hash = $1`, hash).Scan(&status, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if status != "pending" || string(payload) != `{"kind":"transfer"}` {
+		t.Fatalf("stored status %q payload %s", status, payload)
+	}
+
+	queued, err := s.Redis.LRange(ctx, "tx_queue", 0, -1).Result()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found int
+	for _, raw := range queued {
+		var qm storage.QueueMessage
+		if json.Unmarshal([]byte(raw), &qm) == nil && qm.Hash == hash {
+			found++
+		}
+	}
+	if found == 0 {
+		t.Fatalf("%s was not queued on tx_queue", hash)
+	}
+}
+
+func TestSaveTxPerTenant(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	hash := "tx-" + t.Name()
+	acme := tenant.WithID(ctx, "acme")
+	// The same hash is stored separately for each tenant that submits it
+	if err := s.SaveTx(ctx, hash, []byte(`{"kind":"default"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.SaveTx(acme, hash, []byte(`{"kind":"acme"}`)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.UpdateTxStatus(acme, hash, "confirmed"); err != nil {
+		t.Fatal(err)
+	}
+	statuses := map[string]string{}
+	rows, err := s.PG.Query(ctx, `SELECT tenant_id, status FROM transactions WHERE tx_hash = $1`, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for rows.Next() {
+		var id, status string
+		if err := rows.Scan(&id, &status); err != nil {
+			t.Fatal(err)
+		}
+		statuses[id] = status
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if statuses[""] != "pending" || statuses["acme"] != "confirmed" {
+		t.Fatalf("statuses by tenant = %v, want only acme's confirmed", statuses)
+	}
+	if err := s.UpdateTxStatus(tenant.WithID(ctx, "other"), hash, "failed"); err == nil {
+		t.Fatal("another tenant updated the transaction")
+	}
+
+	tenants, err := s.QueuedTxTenants(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(tenants, "acme") || !slices.Contains(tenants, "") {
+		t.Fatalf("queued tenants = %v, want acme and the default tenant", tenants)
+	}
+	msgs, err := s.PopQueuedTxs(acme, 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.ContainsFunc(msgs, func(m storage.QueueMessage) bool { return m.Hash == hash }) {
+		t.Fatalf("%s was not queued on acme's queue", hash)
+	}
+}
+
+func TestCreateAndListMessages(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	alice, bob := "alice-"+t.Name(), "bob-"+t.Name()
+
+	first, err := s.CreateMessage(ctx, alice, bob, []byte("c1"), []byte("n1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateMessage(ctx, bob, alice, []byte("c2"), []byte("n2")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.CreateMessage(ctx, alice, "carol-"+t.Name(), []byte("c3"), []byte("n3")); err != nil {
+		t.Fatal(err)
+	}
+	// The same content is stored once
+	dup, err := s.CreateMessage(ctx, alice, bob, []byte("c1"), []byte("n1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dup.ID != first.ID {
+		t.Errorf("duplicate message got id %d, want %d", dup.ID, first.ID)
+	}
+
+	msgs, err := s.ListMessages(ctx, alice, bob, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 2 || string(msgs[0].ContentCiphertext) != "c1" || string(msgs[1].ContentCiphertext) != "c2" {
+		t.Fatalf("ListMessages(alice, bob) = %+v, want c1 then c2", msgs)
+	}
+	if limited, err := s.ListMessages(ctx, bob, alice, nil, 1); err != nil || len(limited) != 1 {
+		t.Fatalf("ListMessages with limit 1 = %d messages, %v", len(limited), err)
+	}
+	future := time.Now().Add(time.Hour)
+	if later, err := s.ListMessages(ctx, alice, bob, &future, 10); err != nil || len(later) != 0 {
+		t.Fatalf("ListMessages since the future = %d messages, %v", len(later), err)
+	}
+}
+
+func TestAnchorMessage(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	alice, bob := "alice-"+t.Name(), "bob-"+t.Name()
+
+	m, err := s.CreateMessage(ctx, alice, bob, []byte("anchor me"), []byte("n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.AnchoredAtBlock != nil {
+		t.Fatalf("new message already anchored at %d", *m.AnchoredAtBlock)
+	}
+	if err := s.AnchorMessage(ctx, m.ID, 42); err != nil {
+		t.Fatal(err)
+	}
+	msgs, err := s.ListMessages(ctx, alice, bob, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || msgs[0].AnchoredAtBlock == nil || *msgs[0].AnchoredAtBlock != 42 {
+		t.Fatalf("ListMessages = %+v, want the message anchored at block 42", msgs)
+	}
+}
+
+func TestEncryptedMessages(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	alice, bob := "alice-"+t.Name(), "bob-"+t.Name()
+	kek := bytes.Repeat([]byte{7}, 32)
+	enc := storage.NewEncryptedStorage(s, storage.KEKFunc(func(context.Context) ([]byte, error) { return kek, nil }))
+
+	m, err := enc.CreateEncryptedMessage(ctx, "acme", alice, bob, []byte("secret"), []byte("n1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(m.ContentCiphertext) != "secret" {
+		t.Fatalf("CreateEncryptedMessage returned content %q, want it as given", m.ContentCiphertext)
+	}
+	var stored []byte
+	if err := s.PG.QueryRow(ctx, `SELECT content_ciphertext FROM messages WHERE id = $1`, m.ID).Scan(&stored); err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(stored, []byte("secret")) {
+		t.Fatalf("content is stored in the clear: %q", stored)
+	}
+
+	msgs, err := enc.ListMessages(ctx, "acme", alice, bob, nil, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(msgs) != 1 || string(msgs[0].ContentCiphertext) != "secret" {
+		t.Fatalf("ListMessages = %+v, want the decrypted message", msgs)
+	}
+	// Other tenants see nothing
+	if msgs, err := enc.ListMessages(ctx, "globex", alice, bob, nil, 10); err != nil || len(msgs) != 0 {
+		t.Fatalf("ListMessages for another tenant = %+v, %v", msgs, err)
+	}
+}
+
+func TestMessageBuffer(t *testing.T) {
+	s := requireStore(t)
+	ctx := context.Background()
+	alice, bob := "alice-"+t.Name(), "bob-"+t.Name()
+	kek := bytes.Repeat([]byte{9}, 32)
+	enc := storage.NewEncryptedStorage(s, storage.KEKFunc(func(context.Context) ([]byte, error) { return kek, nil }))
+
+	for _, tt := range []struct {
+		name   string
+		buffer *storage.MessageBuffer
+		tenant string
+		sealed bool
+	}{
+		{name: "plain", buffer: storage.NewMessageBuffer(s, 50*time.Millisecond, 10)},
+		{name: "encrypted", buffer: enc.NewMessageBuffer(50*time.Millisecond, 10), tenant: "acme", sealed: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			defer tt.buffer.Close()
+			ctx := tenant.WithID(ctx, tt.tenant)
+			// Postgres rejects NUL in text, so this message fails the batch it lands in
+			senders := []string{alice, alice, "bad\x00sender", alice}
+			type result struct {
+				m   storage.Message
+				err error
+			}
+			results := make([]result, len(senders))
+			var wg sync.WaitGroup
+			for i, sender := range senders {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					content := fmt.Sprintf("%s-%d", tt.name, i)
+					m, err := tt.buffer.Add(ctx, storage.MessageInput{Sender: sender, Recipient: bob, Ciphertext: []byte(content), Nonce: []byte("n")})
+					results[i] = result{m, err}
+				}()
+			}
+			wg.Wait()
+
+			for i, r := range results {
+				want := fmt.Sprintf("%s-%d", tt.name, i)
+				if i == 2 {
+					if r.err == nil {
+						t.Errorf("message %d stored despite its invalid sender", i)
+					}
+					continue
+				}
+				if r.err != nil {
+					t.Fatalf("message %d: %v, want it stored despite message 2 failing", i, r.err)
+				}
+				if string(r.m.ContentCiphertext) != want {
+					t.Errorf("message %d content = %q, want %q", i, r.m.ContentCiphertext, want)
+				}
+				var stored []byte
+				if err := s.PG.QueryRow(ctx, `SELECT content_ciphertext FROM messages WHERE id = $1`, r.m.ID).Scan(&stored); err != nil {
+					t.Fatal(err)
+				}
+				if tt.sealed == (string(stored) == want) {
+					t.Errorf("message %d stored as %q, want sealed %v", i, stored, tt.sealed)
+				}
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ErrLockHeld is returned by AcquireLock when another holder owns the lock.
+var ErrLockHeld = errors.New("lock is held by another owner")
+
+// releaseScript deletes the lock only if it still carries our token, so a holder whose
+// TTL expired cannot release a lock that has since been taken by someone else.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// LockKey is the Redis key backing the distributed lock called name.
+func LockKey(name string) string { return "lock:" + name }
+
+// AcquireLock takes the named lock in Redis for at most ttl. The returned release
+// function gives the lock back early; it is safe to call after the TTL has expired.
+func (s *Storage) AcquireLock(ctx context.Context, name string, ttl time.Duration) (release func(context.Context) error, err error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate lock token: %w", err)
+	}
+	token := hex.EncodeToString(b)
+	ok, err := s.Redis.SetNX(ctx, LockKey(name), token, ttl).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire lock %s: %w", name, err)
+	}
+	if !ok {
+		return nil, ErrLockHeld
+	}
+	return func(ctx context.Context) error {
+		if err := releaseScript.Run(ctx, s.Redis, []string{LockKey(name)}, token).Err(); err != nil {
+			return fmt.Errorf("failed to release lock %s: %w", name, err)
+		}
+		return nil
+	}, nil
+}
\ No newline at end of file
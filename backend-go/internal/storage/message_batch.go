@@ -0,0 +1,218 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "sync"
+    "time"
+
+    "github.com/jackc/pgx/v5"
+
+    "garp-backend/internal/logger"
+    "garp-backend/internal/tenant"
+)
+
+// MessageInput is one message to store with CreateMessages.
+type MessageInput struct {
+    Sender     string
+    Recipient  string
+    Ciphertext []byte
+    Nonce      []byte
+
+    hash     string // set with envelope by EncryptedStorage, which hashes before sealing
+    envelope bool
+}
+
+func (in MessageInput) messageHash() string {
+    if in.hash != "" { return in.hash }
+    return hashMessage(in.Ciphertext, in.Nonce)
+}
+
+// CreateMessages stores msgs in one round trip using a pgx batch and returns them in input order.
+// Each INSERT returns the full row, so no follow-up SELECT is needed. A single message goes
+// through CreateMessage. The batch runs as one implicit transaction, so an error stores nothing.
+// Events are published for every stored message.
+func (s *Storage) CreateMessages(ctx context.Context, msgs []MessageInput) ([]Message, error) {
+    switch len(msgs) {
+    case 0:
+        return nil, nil
+    case 1:
+        m, err := s.createMessageInput(ctx, msgs[0])
+        if err != nil { return nil, err }
+        return []Message{m}, nil
+    }
+
+    tenantID := tenant.FromContext(ctx)
+    batch := &pgx.Batch{}
+    for _, in := range msgs {
+        batch.Queue(
+            `INSERT INTO messages(sender, recipient, content_ciphertext, content_nonce, hash, tenant_id, content_envelope)
+             VALUES ($1,$2,$3,$4,$5,$6,$7)
+             ON CONFLICT (tenant_id, hash) DO UPDATE SET sender = EXCLUDED.sender
+             RETURNING id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block, content_envelope`,
+            in.Sender, in.Recipient, in.Ciphertext, in.Nonce, in.messageHash(), tenantID, in.envelope)
+    }
+    br := s.PG.SendBatch(ctx, batch)
+    out := make([]Message, len(msgs))
+    for i := range msgs {
+        m := &out[i]
+        if err := br.QueryRow().Scan(&m.ID, &m.Sender, &m.Recipient, &m.ContentCiphertext, &m.ContentNonce, &m.Hash, &m.CreatedAt, &m.AnchoredAtBlock, &m.envelope); err != nil {
+            br.Close()
+            logger.FromContext(ctx).Error("failed to store message batch", "size", len(msgs), "index", i, "error", err)
+            return nil, fmt.Errorf("failed to store message %d of %d: %w", i+1, len(msgs), err)
+        }
+    }
+    if err := br.Close(); err != nil { return nil, err }
+    for _, m := range out { s.publishMessage(ctx, m) }
+    return out, nil
+}
+
+func (s *Storage) createMessageInput(ctx context.Context, in MessageInput) (Message, error) {
+    return s.createMessage(ctx, in.Sender, in.Recipient, in.Ciphertext, in.Nonce, in.messageHash(), in.envelope)
+}
+
+// ErrMessageBufferClosed is returned by MessageBuffer.Add after Close.
+var ErrMessageBufferClosed = errors.New("message buffer closed")
+
+type bufferedMessage struct {
+    in     MessageInput
+    tenant string
+    reply  chan bufferedResult
+}
+
+type bufferedResult struct {
+    msg Message
+    err error
+}
+
+// MessageBuffer coalesces concurrent CreateMessage calls into CreateMessages batches. A batch is
+// written once it reaches maxSize messages or maxDelay after its first message, whichever is first.
+// A failed batch is retried one message at a time, so a bad message fails only its own Add.
+type MessageBuffer struct {
+    store    *Storage
+    enc      *EncryptedStorage // seals content before it is queued; nil stores it as given
+    maxDelay time.Duration
+    maxSize  int
+    in       chan bufferedMessage
+    done     chan struct{}
+    closeMu  sync.RWMutex
+    closed   bool
+}
+
+// NewMessageBuffer starts a buffer flushing every 5ms or 100 messages when maxDelay or maxSize is zero.
+func NewMessageBuffer(store *Storage, maxDelay time.Duration, maxSize int) *MessageBuffer {
+    if maxDelay <= 0 { maxDelay = 5 * time.Millisecond }
+    if maxSize <= 0 { maxSize = 100 }
+    b := &MessageBuffer{store: store, maxDelay: maxDelay, maxSize: maxSize, in: make(chan bufferedMessage, maxSize), done: make(chan struct{})}
+    go b.run()
+    return b
+}
+
+// NewMessageBuffer is storage.NewMessageBuffer for messages sealed with the tenant's DEK,
+// like CreateEncryptedMessage. Add returns them with the content as given.
+func (e *EncryptedStorage) NewMessageBuffer(maxDelay time.Duration, maxSize int) *MessageBuffer {
+    b := NewMessageBuffer(e.Storage, maxDelay, maxSize)
+    b.enc = e
+    return b
+}
+
+// Add queues a message for the tenant in ctx and waits for its batch to be written. If ctx
+// ends first Add returns ctx.Err(), but the message may still be stored.
+func (b *MessageBuffer) Add(ctx context.Context, in MessageInput) (Message, error) {
+    tenantID := tenant.FromContext(ctx)
+    if b.enc != nil {
+        var err error
+        if in, err = b.enc.sealInput(ctx, tenantID, in); err != nil { return Message{}, err }
+    }
+    reply := make(chan bufferedResult, 1)
+    b.closeMu.RLock()
+    if b.closed {
+        b.closeMu.RUnlock()
+        return Message{}, ErrMessageBufferClosed
+    }
+    select {
+    case b.in <- bufferedMessage{in: in, tenant: tenantID, reply: reply}:
+        b.closeMu.RUnlock()
+    case <-ctx.Done():
+        b.closeMu.RUnlock()
+        return Message{}, ctx.Err()
+    }
+    select {
+    case r := <-reply:
+        if r.err != nil || b.enc == nil { return r.msg, r.err }
+        // An existing message with the same hash may have been returned instead
+        return r.msg, b.enc.open(tenant.WithID(ctx, tenantID), tenantID, &r.msg)
+    case <-ctx.Done():
+        return Message{}, ctx.Err()
+    }
+}
+
+// Close flushes queued messages and stops the buffer.
+func (b *MessageBuffer) Close() {
+    b.closeMu.Lock()
+    if b.closed {
+        b.closeMu.Unlock()
+        return
+    }
+    b.closed = true
+    close(b.in)
+    b.closeMu.Unlock()
+    <-b.done
+}
+
+func (b *MessageBuffer) run() {
+    defer close(b.done)
+    for first := range b.in {
+        pending := []bufferedMessage{first}
+        timer := time.NewTimer(b.maxDelay)
+    collect:
+        for len(pending) < b.maxSize {
+            select {
+            case m, ok := <-b.in:
+                if !ok { break collect }
+                pending = append(pending, m)
+            case <-timer.C:
+                break collect
+            }
+        }
+        timer.Stop()
+        b.flush(pending)
+    }
+}
+
+// flush writes pending as one CreateMessages batch per tenant.
+func (b *MessageBuffer) flush(pending []bufferedMessage) {
+    var tenants []string
+    byTenant := map[string][]bufferedMessage{}
+    for _, p := range pending {
+        if _, ok := byTenant[p.tenant]; !ok { tenants = append(tenants, p.tenant) }
+        byTenant[p.tenant] = append(byTenant[p.tenant], p)
+    }
+    for _, t := range tenants { b.flushTenant(t, byTenant[t]) }
+}
+
+func (b *MessageBuffer) flushTenant(tenantID string, pending []bufferedMessage) {
+    // Callers may have given up already, so the batch gets its own deadline
+    ctx, cancel := context.WithTimeout(tenant.WithID(context.Background(), tenantID), 10*time.Second)
+    defer cancel()
+    inputs := make([]MessageInput, len(pending))
+    for i, p := range pending { inputs[i] = p.in }
+    msgs, err := b.store.CreateMessages(ctx, inputs)
+    if err != nil && len(pending) > 1 {
+        // The batch stored nothing; store each message alone so only the bad ones fail
+        logger.FromContext(ctx).Warn("message batch failed, storing messages one by one", "size", len(pending), "error", err)
+        for _, p := range pending {
+            m, err := b.store.createMessageInput(ctx, p.in)
+            p.reply <- bufferedResult{msg: m, err: err}
+        }
+        return
+    }
+    for i, p := range pending {
+        if err != nil {
+            p.reply <- bufferedResult{err: err}
+            continue
+        }
+        p.reply <- bufferedResult{msg: msgs[i]}
+    }
+}
\ No newline at end of file
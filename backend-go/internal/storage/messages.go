@@ -5,7 +5,14 @@ import (
     "crypto/sha256"
     "encoding/hex"
     "encoding/json"
+    "errors"
+    "fmt"
     "time"
+
+    "github.com/jackc/pgx/v5"
+
+    "garp-backend/internal/logger"
+    "garp-backend/internal/tenant"
 )
 
 type Message struct {
@@ -17,68 +24,99 @@ type Message struct {
     Hash             string     `json:"hash"`
     CreatedAt        time.Time  `json:"created_at"`
     AnchoredAtBlock  *int64     `json:"anchored_at_block,omitempty"`
+    DeletedAt        *time.Time `json:"deleted_at,omitempty"` // only set by ListDeletedMessages
+    DeletedBy        *string    `json:"deleted_by,omitempty"`
+    envelope         bool       // ContentCiphertext is sealed with the tenant's DEK (see EncryptedStorage)
 }
 
+var (
+    ErrMessageNotFound  = errors.New("message not found")
+    ErrNotMessageSender = errors.New("only the sender can delete a message")
+)
+
 func hashMessage(ciphertext, nonce []byte) string {
     h := sha256.Sum256(append(ciphertext, nonce...))
     return hex.EncodeToString(h[:])
 }
 
+// CreateMessage stores a message for the tenant in ctx (see tenant.WithID). Like the
+// other message queries it only ever touches that tenant's rows.
 func (s *Storage) CreateMessage(ctx context.Context, sender, recipient string, ciphertext, nonce []byte) (Message, error) {
-    h := hashMessage(ciphertext, nonce)
+    return s.createMessage(ctx, sender, recipient, ciphertext, nonce, hashMessage(ciphertext, nonce), false)
+}
+
+// createMessage stores content as given under hash; envelope marks content sealed by
+// EncryptedStorage, whose hash is taken before sealing so duplicates are still detected.
+func (s *Storage) createMessage(ctx context.Context, sender, recipient string, content, nonce []byte, h string, envelope bool) (Message, error) {
+    tenantID := tenant.FromContext(ctx)
     var id int64
     err := s.PG.QueryRow(ctx,
-        `INSERT INTO messages(sender, recipient, content_ciphertext, content_nonce, hash)
-         VALUES ($1,$2,$3,$4,$5)
-         ON CONFLICT (hash) DO UPDATE SET sender = EXCLUDED.sender
-         RETURNING id`, sender, recipient, ciphertext, nonce, h).Scan(&id)
-    if err != nil { return Message{}, err }
+        `INSERT INTO messages(sender, recipient, content_ciphertext, content_nonce, hash, tenant_id, content_envelope)
+         VALUES ($1,$2,$3,$4,$5,$6,$7)
+         ON CONFLICT (tenant_id, hash) DO UPDATE SET sender = EXCLUDED.sender
+         RETURNING id`, sender, recipient, content, nonce, h, tenantID, envelope).Scan(&id)
+    if err != nil {
+        logger.FromContext(ctx).Error("failed to store message", "sender", sender, "recipient", recipient, "error", err)
+        return Message{}, err
+    }
     var m Message
     err = s.PG.QueryRow(ctx,
-        `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block
-         FROM messages WHERE id = $1`, id).
-         Scan(&m.ID, &m.Sender, &m.Recipient, &m.ContentCiphertext, &m.ContentNonce, &m.Hash, &m.CreatedAt, &m.AnchoredAtBlock)
+        `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block, content_envelope
+         FROM messages WHERE id = $1 AND tenant_id = $2`, id, tenantID).
+         Scan(&m.ID, &m.Sender, &m.Recipient, &m.ContentCiphertext, &m.ContentNonce, &m.Hash, &m.CreatedAt, &m.AnchoredAtBlock, &m.envelope)
     if err != nil { return Message{}, err }
-    // Publish event for real-time streams
-    if s.Redis != nil {
-        b, _ := json.Marshal(map[string]any{
-            "type": "message",
-            "id": m.ID,
-            "sender": m.Sender,
-            "recipient": m.Recipient,
-            "hash": m.Hash,
-            "created_at": m.CreatedAt,
-        })
-        _ = s.Redis.Publish(ctx, "messages", b).Err()
-    }
+    s.publishMessage(ctx, m)
     return m, nil
 }
 
+// publishMessage announces a stored message on the "messages" channel for real-time streams,
+// prefixed with the tenant for tenants other than the default one.
+func (s *Storage) publishMessage(ctx context.Context, m Message) {
+    if s.Redis == nil { return }
+    b, _ := json.Marshal(map[string]any{
+        "type": "message",
+        "id": m.ID,
+        "sender": m.Sender,
+        "recipient": m.Recipient,
+        "hash": m.Hash,
+        "created_at": m.CreatedAt,
+    })
+    if err := s.Redis.Publish(ctx, tenantKey(tenant.FromContext(ctx), "messages"), b).Err(); err != nil {
+        logger.FromContext(ctx).Warn("failed to publish message event", "message_id", m.ID, "error", err)
+    }
+}
+
 func (s *Storage) ListMessages(ctx context.Context, a, b string, since *time.Time, limit int) ([]Message, error) {
     if limit <= 0 { limit = 100 }
+    tenantID := tenant.FromContext(ctx)
     var rows pgRows
     var err error
     if since != nil {
         rows, err = s.PG.Query(ctx,
-            `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block
+            `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block, content_envelope
              FROM messages
              WHERE created_at >= $1 AND ((sender = $2 AND recipient = $3) OR (sender = $3 AND recipient = $2))
+               AND deleted_at IS NULL AND tenant_id = $5
              ORDER BY created_at ASC
-             LIMIT $4`, since.UTC(), a, b, limit)
+             LIMIT $4`, since.UTC(), a, b, limit, tenantID)
     } else {
         rows, err = s.PG.Query(ctx,
-            `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block
+            `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block, content_envelope
              FROM messages
-             WHERE (sender = $1 AND recipient = $2) OR (sender = $2 AND recipient = $1)
+             WHERE ((sender = $1 AND recipient = $2) OR (sender = $2 AND recipient = $1))
+               AND deleted_at IS NULL AND tenant_id = $4
              ORDER BY created_at ASC
-             LIMIT $3`, a, b, limit)
+             LIMIT $3`, a, b, limit, tenantID)
+    }
+    if err != nil {
+        logger.FromContext(ctx).Error("failed to list messages", "error", err)
+        return nil, err
     }
-    if err != nil { return nil, err }
     defer rows.Close()
     var out []Message
     for rows.Next() {
         var m Message
-        if err := rows.Scan(&m.ID, &m.Sender, &m.Recipient, &m.ContentCiphertext, &m.ContentNonce, &m.Hash, &m.CreatedAt, &m.AnchoredAtBlock); err != nil {
+        if err := rows.Scan(&m.ID, &m.Sender, &m.Recipient, &m.ContentCiphertext, &m.ContentNonce, &m.Hash, &m.CreatedAt, &m.AnchoredAtBlock, &m.envelope); err != nil {
             return nil, err
         }
         out = append(out, m)
@@ -87,10 +125,92 @@ func (s *Storage) ListMessages(ctx context.Context, a, b string, since *time.Tim
 }
 
 func (s *Storage) AnchorMessage(ctx context.Context, id int64, block int64) error {
-    _, err := s.PG.Exec(ctx, `UPDATE messages SET anchored_at_block = $2 WHERE id = $1`, id, block)
+    _, err := s.PG.Exec(ctx, `UPDATE messages SET anchored_at_block = $2 WHERE id = $1 AND tenant_id = $3`, id, block, tenant.FromContext(ctx))
+    if err != nil {
+        logger.FromContext(ctx).Error("failed to anchor message", "message_id", id, "block", block, "error", err)
+    }
     return err
 }
 
+// UnanchoredMessageIDs returns up to limit IDs of live messages created before cutoff that
+// are not yet anchored to a block, oldest first. Like AnchorMessages it spans all tenants,
+// since the anchor worker anchors every tenant's messages in the same block.
+func (s *Storage) UnanchoredMessageIDs(ctx context.Context, cutoff time.Time, limit int) ([]int64, error) {
+    rows, err := s.PG.Query(ctx,
+        `SELECT id FROM messages
+         WHERE anchored_at_block IS NULL AND deleted_at IS NULL AND created_at < $1
+         ORDER BY id LIMIT $2`, cutoff.UTC(), limit)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list unanchored messages: %w", err)
+    }
+    defer rows.Close()
+    var ids []int64
+    for rows.Next() {
+        var id int64
+        if err := rows.Scan(&id); err != nil { return nil, err }
+        ids = append(ids, id)
+    }
+    return ids, rows.Err()
+}
+
+// AnchorMessages anchors every message in ids to block in one statement and returns how many
+// were updated. Messages that are already anchored keep their original block.
+func (s *Storage) AnchorMessages(ctx context.Context, ids []int64, block int64) (int64, error) {
+    if len(ids) == 0 { return 0, nil }
+    tag, err := s.PG.Exec(ctx, `UPDATE messages SET anchored_at_block = $2 WHERE id = ANY($1) AND anchored_at_block IS NULL`, ids, block)
+    if err != nil {
+        logger.FromContext(ctx).Error("failed to anchor messages", "count", len(ids), "block", block, "error", err)
+        return 0, err
+    }
+    return tag.RowsAffected(), nil
+}
+
+// DeleteMessage soft-deletes ("unsends") a message: the row is kept as a tombstone with
+// deleted_at/deleted_by set and is hidden from ListMessages. Only the sender may delete;
+// deleting an already deleted message is a no-op.
+func (s *Storage) DeleteMessage(ctx context.Context, id int64, requester string) error {
+    tenantID := tenant.FromContext(ctx)
+    var sender string
+    var deleted bool
+    err := s.PG.QueryRow(ctx, `SELECT sender, deleted_at IS NOT NULL FROM messages WHERE id = $1 AND tenant_id = $2`, id, tenantID).Scan(&sender, &deleted)
+    if errors.Is(err, pgx.ErrNoRows) { return ErrMessageNotFound }
+    if err != nil { return fmt.Errorf("failed to load message: %w", err) }
+    if sender != requester { return ErrNotMessageSender }
+    if deleted { return nil }
+    _, err = s.PG.Exec(ctx, `UPDATE messages SET deleted_at = NOW(), deleted_by = $2 WHERE id = $1 AND tenant_id = $3 AND deleted_at IS NULL`, id, requester, tenantID)
+    if err != nil {
+        logger.FromContext(ctx).Error("failed to delete message", "message_id", id, "error", err)
+        return err
+    }
+    return nil
+}
+
+// ListDeletedMessages returns tombstones of messages sent or received by participant, oldest
+// deletion first, for audit. since filters on deletion time.
+func (s *Storage) ListDeletedMessages(ctx context.Context, participant string, since *time.Time) ([]Message, error) {
+    var from time.Time
+    if since != nil { from = since.UTC() }
+    rows, err := s.PG.Query(ctx,
+        `SELECT id, sender, recipient, content_ciphertext, content_nonce, hash, created_at, anchored_at_block, deleted_at, deleted_by, content_envelope
+         FROM messages
+         WHERE deleted_at IS NOT NULL AND deleted_at >= $2 AND (sender = $1 OR recipient = $1) AND tenant_id = $3
+         ORDER BY deleted_at ASC`, participant, from, tenant.FromContext(ctx))
+    if err != nil {
+        logger.FromContext(ctx).Error("failed to list deleted messages", "error", err)
+        return nil, err
+    }
+    defer rows.Close()
+    var out []Message
+    for rows.Next() {
+        var m Message
+        if err := rows.Scan(&m.ID, &m.Sender, &m.Recipient, &m.ContentCiphertext, &m.ContentNonce, &m.Hash, &m.CreatedAt, &m.AnchoredAtBlock, &m.DeletedAt, &m.DeletedBy, &m.envelope); err != nil {
+            return nil, err
+        }
+        out = append(out, m)
+    }
+    return out, rows.Err()
+}
+
 // minimal interface alias for pgx Rows to simplify testing
 type pgRows interface{
     Next() bool
@@ -4,11 +4,24 @@ import (
     "context"
     "os"
     "path/filepath"
+    "time"
+
+    "garp-backend/internal/logger"
+    "garp-backend/internal/middleware"
 )
 
-// RunMigrations ensures required tables exist.
+// RunMigrations ensures required tables exist. When a Pushgateway is configured the
+// per-file timings are pushed afterwards, since a migration-only run exits before any scrape.
 func (s *Storage) RunMigrations(ctx context.Context) error {
     if s.PG == nil { return nil }
+    if s.pushgatewayURL != "" {
+        defer func() {
+            host, _ := os.Hostname()
+            if perr := middleware.PushMetrics(ctx, s.pushgatewayURL, "garp-backend-migrations", host); perr != nil {
+                logger.FromContext(ctx).Warn("migration metrics push failed", "error", perr)
+            }
+        }()
+    }
     base := filepath.Join("backend-go","migrations")
     entries, err := os.ReadDir(base)
     if err != nil { return err }
@@ -17,7 +30,10 @@ func (s *Storage) RunMigrations(ctx context.Context) error {
         path := filepath.Join(base, e.Name())
         sql, err := os.ReadFile(path)
         if err != nil { return err }
-        if _, err := s.PG.Exec(ctx, string(sql)); err != nil { return err }
+        start := time.Now()
+        _, err = s.PG.Exec(ctx, string(sql))
+        middleware.RecordMigration(e.Name(), time.Since(start), err)
+        if err != nil { return err }
     }
     return nil
 }
\ No newline at end of file
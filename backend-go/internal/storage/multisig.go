@@ -0,0 +1,154 @@
+package storage
+
+import (
+    "context"
+    "crypto/ed25519"
+    "crypto/rand"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "slices"
+    "time"
+
+    "github.com/jackc/pgx/v5"
+)
+
+// Multi-signature transaction states
+const (
+    MultiSigPending   = "pending"
+    MultiSigExecuting = "executing"
+    MultiSigExecuted  = "executed"
+    MultiSigFailed    = "failed"
+)
+
+var (
+    ErrMultiSigNotFound = errors.New("multisig transaction not found")
+    ErrNotASigner       = errors.New("signer is not part of this multisig transaction")
+    ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// MultiSigTx is an M-of-N transaction collecting signatures before execution.
+// Signers are hex ed25519 public keys; each signs the raw bytes of Digest.
+type MultiSigTx struct {
+    ID         string            `json:"id"`
+    Commands   json.RawMessage   `json:"commands"`
+    Threshold  int               `json:"threshold"`
+    Signers    []string          `json:"signers"`
+    Signatures []string          `json:"signatures"` // aligned with Signers; "" until that signer has signed
+    Digest     string            `json:"digest"`
+    Status     string            `json:"status"`
+    TxID       *string           `json:"tx_id,omitempty"`
+    CreatedAt  time.Time         `json:"created_at"`
+}
+
+// NewMultiSigTx validates the parameters and assigns an ID and signing digest.
+func NewMultiSigTx(commands json.RawMessage, threshold int, signers []string) (MultiSigTx, error) {
+    var cmds []json.RawMessage
+    if err := json.Unmarshal(commands, &cmds); err != nil || len(cmds) == 0 {
+        return MultiSigTx{}, fmt.Errorf("commands must be a non-empty JSON array")
+    }
+    if threshold < 1 || threshold > len(signers) {
+        return MultiSigTx{}, fmt.Errorf("threshold must be between 1 and %d", len(signers))
+    }
+    seen := map[string]bool{}
+    for _, s := range signers {
+        if k, err := hex.DecodeString(s); err != nil || len(k) != ed25519.PublicKeySize {
+            return MultiSigTx{}, fmt.Errorf("signer %q is not a hex ed25519 public key", s)
+        }
+        if seen[s] { return MultiSigTx{}, fmt.Errorf("duplicate signer %q", s) }
+        seen[s] = true
+    }
+    var id [16]byte
+    if _, err := rand.Read(id[:]); err != nil { return MultiSigTx{}, err }
+    m := MultiSigTx{ID: hex.EncodeToString(id[:]), Commands: commands, Threshold: threshold, Signers: signers, Signatures: make([]string, len(signers)), Status: MultiSigPending}
+    // The ID is part of the digest so a signature can't be replayed onto another multisig transaction
+    b, err := json.Marshal(struct {
+        ID        string          `json:"id"`
+        Commands  json.RawMessage `json:"commands"`
+        Threshold int             `json:"threshold"`
+        Signers   []string        `json:"signers"`
+    }{m.ID, commands, threshold, signers})
+    if err != nil { return MultiSigTx{}, err }
+    h := sha256.Sum256(b)
+    m.Digest = hex.EncodeToString(h[:])
+    return m, nil
+}
+
+// Verify checks that signature is signer's ed25519 signature over the digest.
+func (m MultiSigTx) Verify(signer, signature string) error {
+    if !slices.Contains(m.Signers, signer) { return ErrNotASigner }
+    pub, err := hex.DecodeString(signer)
+    if err != nil || len(pub) != ed25519.PublicKeySize { return ErrNotASigner }
+    sig, err := hex.DecodeString(signature)
+    if err != nil { return ErrInvalidSignature }
+    digest, _ := hex.DecodeString(m.Digest)
+    if !ed25519.Verify(pub, digest, sig) { return ErrInvalidSignature }
+    return nil
+}
+
+// Ready reports whether enough signatures have been collected.
+func (m MultiSigTx) Ready() bool {
+    n := 0
+    for _, sig := range m.Signatures {
+        if sig != "" { n++ }
+    }
+    return n >= m.Threshold
+}
+
+func (s *Storage) CreateMultiSig(ctx context.Context, m MultiSigTx) (MultiSigTx, error) {
+    err := s.PG.QueryRow(ctx,
+        `INSERT INTO multisig_transactions(id, commands, threshold, signers, digest, status)
+         VALUES ($1,$2,$3,$4,$5,$6) RETURNING created_at`,
+        m.ID, m.Commands, m.Threshold, m.Signers, m.Digest, m.Status).Scan(&m.CreatedAt)
+    return m, err
+}
+
+func (s *Storage) GetMultiSig(ctx context.Context, id string) (MultiSigTx, error) {
+    var m MultiSigTx
+    err := s.PG.QueryRow(ctx,
+        `SELECT id, commands, threshold, signers, digest, status, tx_id, created_at
+         FROM multisig_transactions WHERE id = $1`, id).
+        Scan(&m.ID, &m.Commands, &m.Threshold, &m.Signers, &m.Digest, &m.Status, &m.TxID, &m.CreatedAt)
+    if errors.Is(err, pgx.ErrNoRows) { return MultiSigTx{}, ErrMultiSigNotFound }
+    if err != nil { return MultiSigTx{}, err }
+    m.Signatures = make([]string, len(m.Signers))
+    rows, err := s.PG.Query(ctx, `SELECT signer, signature FROM multisig_signatures WHERE multisig_id = $1`, id)
+    if err != nil { return MultiSigTx{}, err }
+    defer rows.Close()
+    for rows.Next() {
+        var signer, sig string
+        if err := rows.Scan(&signer, &sig); err != nil { return MultiSigTx{}, err }
+        if i := slices.Index(m.Signers, signer); i >= 0 { m.Signatures[i] = sig }
+    }
+    return m, rows.Err()
+}
+
+// AddMultiSigSignature stores a signature that the caller has already verified.
+// Re-submitting a signer's signature is a no-op.
+func (s *Storage) AddMultiSigSignature(ctx context.Context, id, signer, signature string) error {
+    _, err := s.PG.Exec(ctx,
+        `INSERT INTO multisig_signatures(multisig_id, signer, signature) VALUES ($1,$2,$3)
+         ON CONFLICT (multisig_id, signer) DO NOTHING`, id, signer, signature)
+    return err
+}
+
+// ClaimMultiSigExecution moves a pending transaction to executing. It returns false if
+// another request already claimed it, so the transaction is submitted at most once.
+func (s *Storage) ClaimMultiSigExecution(ctx context.Context, id string) (bool, error) {
+    tag, err := s.PG.Exec(ctx,
+        `UPDATE multisig_transactions SET status = $2 WHERE id = $1 AND status = $3`,
+        id, MultiSigExecuting, MultiSigPending)
+    if err != nil { return false, err }
+    return tag.RowsAffected() == 1, nil
+}
+
+// FinishMultiSig records the execution outcome; txID is empty when submission failed.
+func (s *Storage) FinishMultiSig(ctx context.Context, id, txID string) error {
+    status, ref := MultiSigExecuted, &txID
+    if txID == "" { status, ref = MultiSigFailed, nil }
+    _, err := s.PG.Exec(ctx,
+        `UPDATE multisig_transactions SET status = $2, tx_id = $3, executed_at = NOW() WHERE id = $1`, id, status, ref)
+    return err
+}
\ No newline at end of file
@@ -0,0 +1,45 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/jackc/pgx/v5"
+)
+
+var ErrNFTNotFound = errors.New("nft not found")
+
+// NFTMetadata is the off-chain record kept for an NFT contract.
+type NFTMetadata struct {
+    ContractID  string    `json:"contract_id"`
+    AssetID     string    `json:"asset_id"`
+    Owner       string    `json:"owner"`
+    MetadataURI string    `json:"metadata_uri"`
+    Supply      int64     `json:"supply"`
+    CreatedAt   time.Time `json:"created_at"`
+}
+
+// SaveNFTMetadata stores the metadata of a newly created NFT contract and returns it
+// with CreatedAt filled in.
+func (s *Storage) SaveNFTMetadata(ctx context.Context, m NFTMetadata) (NFTMetadata, error) {
+    err := s.PG.QueryRow(ctx,
+        `INSERT INTO nft_metadata (contract_id, asset_id, owner, metadata_uri, supply) VALUES ($1, $2, $3, $4, $5)
+         RETURNING created_at`, m.ContractID, m.AssetID, m.Owner, m.MetadataURI, m.Supply).Scan(&m.CreatedAt)
+    if err != nil {
+        return m, fmt.Errorf("failed to save nft metadata: %w", err)
+    }
+    return m, nil
+}
+
+// GetNFTMetadata loads the metadata stored for contractID.
+func (s *Storage) GetNFTMetadata(ctx context.Context, contractID string) (NFTMetadata, error) {
+    var m NFTMetadata
+    err := s.PG.QueryRow(ctx,
+        `SELECT contract_id, asset_id, owner, metadata_uri, supply, created_at FROM nft_metadata WHERE contract_id = $1`, contractID).
+        Scan(&m.ContractID, &m.AssetID, &m.Owner, &m.MetadataURI, &m.Supply, &m.CreatedAt)
+    if errors.Is(err, pgx.ErrNoRows) { return m, ErrNFTNotFound }
+    if err != nil { return m, fmt.Errorf("failed to load nft metadata: %w", err) }
+    return m, nil
+}
\ No newline at end of file
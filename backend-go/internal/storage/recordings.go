@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+
+	"garp-backend/internal/middleware"
+)
+
+// RedisRecordingStore keeps recorded requests as JSON in Redis for middleware.RecordingTTL.
+type RedisRecordingStore struct{ rdb *redis.Client }
+
+var _ middleware.RecordingStore = (*RedisRecordingStore)(nil)
+
+func NewRedisRecordingStore(rdb *redis.Client) *RedisRecordingStore {
+	return &RedisRecordingStore{rdb: rdb}
+}
+
+func recordingKey(id string) string { return "recording:" + id }
+
+// SaveRecording stores rec with SETNX, so a client reusing a request ID cannot overwrite
+// an earlier recording.
+func (s *RedisRecordingStore) SaveRecording(ctx context.Context, rec middleware.RecordedRequest) error {
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.SetNX(ctx, recordingKey(rec.ID), b, middleware.RecordingTTL).Err(); err != nil {
+		return fmt.Errorf("failed to save recording %s: %w", rec.ID, err)
+	}
+	return nil
+}
+
+func (s *RedisRecordingStore) Recording(ctx context.Context, id string) (*middleware.RecordedRequest, error) {
+	b, err := s.rdb.Get(ctx, recordingKey(id)).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, middleware.ErrRecordingNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording %s: %w", id, err)
+	}
+	var rec middleware.RecordedRequest
+	if err := json.Unmarshal(b, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode recording %s: %w", id, err)
+	}
+	return &rec, nil
+}
+
+// AddReplay appends result to the recording, keeping its remaining TTL.
+func (s *RedisRecordingStore) AddReplay(ctx context.Context, id string, result middleware.ReplayResult) error {
+	rec, err := s.Recording(ctx, id)
+	if err != nil {
+		return err
+	}
+	rec.Replays = append(rec.Replays, result)
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if err := s.rdb.SetArgs(ctx, recordingKey(id), b, redis.SetArgs{Mode: "XX", KeepTTL: true}).Err(); err != nil && !errors.Is(err, redis.Nil) {
+		return fmt.Errorf("failed to save replay of %s: %w", id, err)
+	}
+	return nil
+}
\ No newline at end of file
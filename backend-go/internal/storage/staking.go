@@ -0,0 +1,80 @@
+package storage
+
+import (
+    "context"
+    "errors"
+    "fmt"
+    "time"
+
+    "github.com/jackc/pgx/v5"
+)
+
+// Stake position states
+const (
+    StakeActive    = "active"
+    StakeUnstaking = "unstaking"
+    StakeWithdrawn = "withdrawn"
+)
+
+var ErrStakeNotFound = errors.New("stake position not found")
+
+// StakePosition is a stake delegated to a validator.
+type StakePosition struct {
+    ID        string    `json:"id"`
+    Address   string    `json:"address"`
+    Amount    int64     `json:"amount"`
+    Validator string    `json:"validator"`
+    Status    string    `json:"status"`
+    CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateStakePosition records a newly opened stake as active.
+func (s *Storage) CreateStakePosition(ctx context.Context, p StakePosition) error {
+    _, err := s.PG.Exec(ctx,
+        `INSERT INTO stake_positions (id, address, amount, validator, status) VALUES ($1, $2, $3, $4, $5)
+         ON CONFLICT (id) DO NOTHING`, p.ID, p.Address, p.Amount, p.Validator, StakeActive)
+    if err != nil {
+        return fmt.Errorf("failed to record stake position: %w", err)
+    }
+    return nil
+}
+
+// GetStakePosition loads one stake position.
+func (s *Storage) GetStakePosition(ctx context.Context, id string) (StakePosition, error) {
+    var p StakePosition
+    err := s.PG.QueryRow(ctx,
+        `SELECT id, address, amount, validator, status, created_at FROM stake_positions WHERE id = $1`, id).
+        Scan(&p.ID, &p.Address, &p.Amount, &p.Validator, &p.Status, &p.CreatedAt)
+    if errors.Is(err, pgx.ErrNoRows) { return p, ErrStakeNotFound }
+    if err != nil { return p, fmt.Errorf("failed to load stake position: %w", err) }
+    return p, nil
+}
+
+// SetStakeStatus moves a stake position to status.
+func (s *Storage) SetStakeStatus(ctx context.Context, id, status string) error {
+    tag, err := s.PG.Exec(ctx, `UPDATE stake_positions SET status = $2 WHERE id = $1`, id, status)
+    if err != nil { return fmt.Errorf("failed to update stake position: %w", err) }
+    if tag.RowsAffected() == 0 { return ErrStakeNotFound }
+    return nil
+}
+
+// ListStakePositions returns address's active and unstaking positions, newest first.
+func (s *Storage) ListStakePositions(ctx context.Context, address string) ([]StakePosition, error) {
+    rows, err := s.PG.Query(ctx,
+        `SELECT id, address, amount, validator, status, created_at FROM stake_positions
+         WHERE address = $1 AND status IN ($2, $3)
+         ORDER BY created_at DESC`, address, StakeActive, StakeUnstaking)
+    if err != nil {
+        return nil, fmt.Errorf("failed to list stake positions: %w", err)
+    }
+    defer rows.Close()
+    out := []StakePosition{}
+    for rows.Next() {
+        var p StakePosition
+        if err := rows.Scan(&p.ID, &p.Address, &p.Amount, &p.Validator, &p.Status, &p.CreatedAt); err != nil {
+            return nil, err
+        }
+        out = append(out, p)
+    }
+    return out, rows.Err()
+}
\ No newline at end of file
@@ -3,20 +3,80 @@ package storage
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
+
+	"garp-backend/internal/logger"
+	"garp-backend/internal/tenant"
 )
 
 type Storage struct {
 	PG    *pgxpool.Pool
 	Redis *redis.Client
+
+	checksMu sync.Mutex
+	checks   map[string]HealthCheck
+
+	hooksMu sync.RWMutex
+	txHooks []TxStatusHook
+
+	pushgatewayURL string
 }
 
+// Config holds connection settings. Zero pool and Redis values fall back to the defaults
+// noted on each field.
 type Config struct {
 	PostgresURL string
 	RedisURL    string
+	Pool        PoolConfig
+
+	RedisMaxRetries   int           // default 3; -1 disables retries
+	RedisDialTimeout  time.Duration // default 5s
+	RedisReadTimeout  time.Duration // default 3s
+	RedisWriteTimeout time.Duration // default 3s
+	RedisPoolSize     int           // default 10 per CPU
+
+	PushgatewayURL string // optional; RunMigrations pushes its metrics here when set
+}
+
+// PoolConfig tunes the Postgres connection pool.
+type PoolConfig struct {
+	MaxConns          int32         // default 10
+	MinConns          int32         // default 0; warm connections kept open for spikes
+	MaxConnLifetime   time.Duration // default 1h; recycles connections behind load balancers
+	MaxConnIdleTime   time.Duration // default 30m
+	HealthCheckPeriod time.Duration // default 1m
+}
+
+// DefaultPoolConfig returns the pool settings used for zero PoolConfig fields.
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxConns:          10,
+		MaxConnLifetime:   time.Hour,
+		MaxConnIdleTime:   30 * time.Minute,
+		HealthCheckPeriod: time.Minute,
+	}
+}
+
+func (p PoolConfig) apply(c *pgxpool.Config) {
+	def := DefaultPoolConfig()
+	c.MaxConns = orDefault(p.MaxConns, def.MaxConns)
+	c.MinConns = p.MinConns
+	c.MaxConnLifetime = orDefault(p.MaxConnLifetime, def.MaxConnLifetime)
+	c.MaxConnIdleTime = orDefault(p.MaxConnIdleTime, def.MaxConnIdleTime)
+	c.HealthCheckPeriod = orDefault(p.HealthCheckPeriod, def.HealthCheckPeriod)
+}
+
+func orDefault[T int | int32 | time.Duration](v, def T) T {
+	if v > 0 {
+		return v
+	}
+	return def
 }
 
 func Init(ctx context.Context, cfg Config) (*Storage, error) {
@@ -24,7 +84,7 @@ func Init(ctx context.Context, cfg Config) (*Storage, error) {
 	if err != nil {
 		return nil, err
 	}
-	pgCfg.MaxConns = 10
+	cfg.Pool.apply(pgCfg)
 	pg, err := pgxpool.NewWithConfig(ctx, pgCfg)
 	if err != nil {
 		return nil, err
@@ -35,13 +95,29 @@ func Init(ctx context.Context, cfg Config) (*Storage, error) {
 		pg.Close()
 		return nil, err
 	}
+	// Explicit settings win over the URL; go-redis supplies the defaults otherwise
+	if cfg.RedisMaxRetries != 0 {
+		rdbOpts.MaxRetries = cfg.RedisMaxRetries
+	}
+	if cfg.RedisDialTimeout > 0 {
+		rdbOpts.DialTimeout = cfg.RedisDialTimeout
+	}
+	if cfg.RedisReadTimeout > 0 {
+		rdbOpts.ReadTimeout = cfg.RedisReadTimeout
+	}
+	if cfg.RedisWriteTimeout > 0 {
+		rdbOpts.WriteTimeout = cfg.RedisWriteTimeout
+	}
+	if cfg.RedisPoolSize > 0 {
+		rdbOpts.PoolSize = cfg.RedisPoolSize
+	}
 	rdb := redis.NewClient(rdbOpts)
 	if err := rdb.Ping(ctx).Err(); err != nil {
 		pg.Close()
 		return nil, err
 	}
 
-	return &Storage{PG: pg, Redis: rdb}, nil
+	return &Storage{PG: pg, Redis: rdb, pushgatewayURL: cfg.PushgatewayURL}, nil
 }
 
 func (s *Storage) Close() {
@@ -53,30 +129,136 @@ func (s *Storage) Close() {
 	}
 }
 
-// SaveTx persists a transaction stub and enqueues it for processing.
+// QueueMessage is an entry on a tenant's TxQueue.
 type QueueMessage struct {
-	Kind  string `json:"kind"`
-	Hash  string `json:"hash"`
-	Retry int    `json:"retry"`
+	Kind   string `json:"kind"`
+	Hash   string `json:"hash"`
+	Retry  int    `json:"retry"`
+	Status string `json:"status,omitempty"` // last status the monitor recorded; empty until the first change
 }
 
+// TxStatusEvent is published on TxChannel(tenant, hash) whenever a transaction's status changes.
+type TxStatusEvent struct {
+	Hash      string    `json:"hash"`
+	Status    string    `json:"status"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TxStatusHook is called after UpdateTxStatus persists a new status, e.g. to mirror it
+// into an external system. ctx carries the transaction's tenant (tenant.FromContext). Hooks
+// run synchronously; errors are theirs to log.
+type TxStatusHook func(ctx context.Context, hash, status string)
+
+// OnTxStatus registers a hook run after every successful UpdateTxStatus.
+func (s *Storage) OnTxStatus(hook TxStatusHook) {
+	s.hooksMu.Lock()
+	defer s.hooksMu.Unlock()
+	s.txHooks = append(s.txHooks, hook)
+}
+
+// TxChannel is the Redis pub/sub channel carrying status updates for one of a tenant's
+// transactions. The default tenant ("") keeps the bare "tx:<hash>" channel.
+func TxChannel(tenantID, hash string) string { return tenantKey(tenantID, "tx:"+hash) }
+
+// SaveTx persists a transaction stub for the context's tenant and enqueues it on the
+// tenant's TxQueue for the status monitor. The table comes from migration 0014.
 func (s *Storage) SaveTx(ctx context.Context, hash string, payload []byte) error {
-	_, err := s.PG.Exec(ctx, `CREATE TABLE IF NOT EXISTS transactions (
-        tx_hash TEXT PRIMARY KEY,
-        created_at TIMESTAMPTZ DEFAULT NOW(),
-        payload BYTEA
-    )`)
+	tenantID := tenant.FromContext(ctx)
+	_, err := s.PG.Exec(ctx, `INSERT INTO transactions (tenant_id, tx_hash, payload) VALUES ($1,$2,$3)
+        ON CONFLICT (tenant_id, tx_hash) DO NOTHING`, tenantID, hash, payload)
 	if err != nil {
 		return err
 	}
-	_, err = s.PG.Exec(ctx, `INSERT INTO transactions (tx_hash, payload) VALUES ($1,$2)
-        ON CONFLICT (tx_hash) DO NOTHING`, hash, payload)
-	if err != nil {
+	if err := s.queueTx(ctx, QueueMessage{Kind: "tx", Hash: hash, Retry: 0}); err != nil {
 		return err
 	}
-	msg := QueueMessage{Kind: "tx", Hash: hash, Retry: 0}
+	s.publishTxStatus(ctx, hash, "pending")
+	return nil
+}
+
+// TxQueue is the Redis list SaveTx pushes new transactions onto, one per tenant
+// ("tenant:<id>:tx_queue"; the default tenant keeps the bare key). The status monitor
+// consumes it from the other end, so transactions are checked oldest first.
+const TxQueue = "tx_queue"
+
+// TxQueueTenants is the Redis set of tenants that have queued transactions, so the status
+// monitor can find their queues without scanning keys.
+const TxQueueTenants = "tx_queue:tenants"
+
+// queueTx pushes msg onto the context tenant's TxQueue and records the tenant.
+func (s *Storage) queueTx(ctx context.Context, msg QueueMessage) error {
+	tenantID := tenant.FromContext(ctx)
 	b, _ := json.Marshal(msg)
-	return s.Redis.LPush(ctx, "tx_queue", b).Err()
+	_, err := s.Redis.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.LPush(ctx, tenantKey(tenantID, TxQueue), b)
+		p.SAdd(ctx, TxQueueTenants, tenantID)
+		return nil
+	})
+	return err
+}
+
+// QueuedTxTenants returns the tenants whose TxQueue the status monitor should read. A tenant
+// stays listed once it has queued a transaction; its queue may be empty.
+func (s *Storage) QueuedTxTenants(ctx context.Context) ([]string, error) {
+	return s.Redis.SMembers(ctx, TxQueueTenants).Result()
+}
+
+// PopQueuedTxs removes up to n messages from the context tenant's TxQueue, oldest first.
+// Entries that do not decode are dropped with a warning.
+func (s *Storage) PopQueuedTxs(ctx context.Context, n int) ([]QueueMessage, error) {
+	vals, err := s.Redis.RPopCount(ctx, tenantKey(tenant.FromContext(ctx), TxQueue), n).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	msgs := make([]QueueMessage, 0, len(vals))
+	for _, v := range vals {
+		var msg QueueMessage
+		if err := json.Unmarshal([]byte(v), &msg); err != nil || msg.Hash == "" {
+			logger.FromContext(ctx).Warn("dropping malformed transaction queue entry", "entry", v, "error", err)
+			continue
+		}
+		msgs = append(msgs, msg)
+	}
+	return msgs, nil
+}
+
+// RequeueTx pushes msg back onto the context tenant's TxQueue with its retry count incremented.
+func (s *Storage) RequeueTx(ctx context.Context, msg QueueMessage) error {
+	msg.Retry++
+	return s.queueTx(ctx, msg)
+}
+
+// UpdateTxStatus records a new status for one of the context tenant's transactions and
+// notifies subscribers.
+func (s *Storage) UpdateTxStatus(ctx context.Context, hash, status string) error {
+	tag, err := s.PG.Exec(ctx, `UPDATE transactions SET status = $3 WHERE tenant_id = $1 AND tx_hash = $2`,
+		tenant.FromContext(ctx), hash, status)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("transaction %s not found", hash)
+	}
+	s.publishTxStatus(ctx, hash, status)
+	s.hooksMu.RLock()
+	hooks := s.txHooks
+	s.hooksMu.RUnlock()
+	for _, hook := range hooks {
+		hook(ctx, hash, status)
+	}
+	return nil
+}
+
+// publishTxStatus is best effort: the status is already persisted, so a failed
+// publish only delays subscribers until they poll.
+func (s *Storage) publishTxStatus(ctx context.Context, hash, status string) {
+	b, _ := json.Marshal(TxStatusEvent{Hash: hash, Status: status, UpdatedAt: time.Now().UTC()})
+	if err := s.Redis.Publish(ctx, TxChannel(tenant.FromContext(ctx), hash), b).Err(); err != nil {
+		logger.FromContext(ctx).Warn("failed to publish transaction status", "hash", hash, "status", status, "error", err)
+	}
 }
 
 // Ready checks DB and Redis connectivity.
@@ -0,0 +1,146 @@
+// Package storagetest starts throwaway Postgres and Redis containers for integration tests
+// of code built on storage.Storage.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+	"go.uber.org/goleak"
+
+	"garp-backend/internal/storage"
+)
+
+// ErrNoDocker is returned by Start when no Docker daemon is reachable.
+var ErrNoDocker = errors.New("docker is not available")
+
+// Env is a running postgres:16-alpine and redis:7-alpine pair with a migrated Storage
+// connected to both.
+type Env struct {
+	Storage     *storage.Storage
+	PostgresURL string // for code that opens its own connections, such as integration.DBIntegration
+
+	containers []testcontainers.Container
+}
+
+// Start runs the containers, waits for them to accept connections and applies the
+// migrations. RunMigrations reads backend-go/migrations relative to the working directory,
+// so Start changes it to the repository root, as the binary expects to be started there.
+// testcontainers' reaper removes the containers even if the test binary is killed before Close.
+func Start(ctx context.Context) (*Env, error) {
+	if err := checkDocker(ctx); err != nil {
+		return nil, err
+	}
+	env := &Env{}
+
+	pg, err := tcpostgres.Run(ctx, "postgres:16-alpine",
+		tcpostgres.WithDatabase("garp"),
+		tcpostgres.WithUsername("garp"),
+		tcpostgres.WithPassword("garp"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	if pg != nil {
+		env.containers = append(env.containers, pg)
+	}
+	if err != nil {
+		env.Close()
+		return nil, fmt.Errorf("failed to start postgres:16-alpine: %w", err)
+	}
+	rd, err := tcredis.Run(ctx, "redis:7-alpine")
+	if rd != nil {
+		env.containers = append(env.containers, rd)
+	}
+	if err != nil {
+		env.Close()
+		return nil, fmt.Errorf("failed to start redis:7-alpine: %w", err)
+	}
+
+	var cfg storage.Config
+	if cfg.PostgresURL, err = pg.ConnectionString(ctx, "sslmode=disable"); err != nil {
+		env.Close()
+		return nil, err
+	}
+	if cfg.RedisURL, err = rd.ConnectionString(ctx); err != nil {
+		env.Close()
+		return nil, err
+	}
+	s, err := storage.Init(ctx, cfg)
+	if err != nil {
+		env.Close()
+		return nil, fmt.Errorf("containers did not become ready: %w", err)
+	}
+	env.Storage, env.PostgresURL = s, cfg.PostgresURL
+	if !s.Ready(ctx) {
+		env.Close()
+		return nil, errors.New("containers did not become ready: storage not ready")
+	}
+
+	if err := chdirRepoRoot(); err != nil {
+		env.Close()
+		return nil, err
+	}
+	if err := env.Storage.RunMigrations(ctx); err != nil {
+		env.Close()
+		return nil, fmt.Errorf("failed to run migrations: %w", err)
+	}
+	return env, nil
+}
+
+// checkDocker returns an error wrapping ErrNoDocker unless testcontainers can reach a
+// healthy Docker daemon.
+func checkDocker(ctx context.Context) error {
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDocker, err)
+	}
+	defer provider.Close()
+	if err := provider.Health(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrNoDocker, err)
+	}
+	return nil
+}
+
+// Close disconnects the Storage and removes the containers.
+func (e *Env) Close() {
+	if e.Storage != nil {
+		e.Storage.Close()
+	}
+	for _, c := range e.containers {
+		_ = testcontainers.TerminateContainer(c)
+	}
+}
+
+// LeakOptions are the goleak options for packages that use this one. testcontainers keeps a
+// connection to its reaper, and the Docker client keeps idle connections, for the life of
+// the test binary; neither is a leak of the code under test.
+func LeakOptions() []goleak.Option {
+	return []goleak.Option{
+		goleak.IgnoreAnyFunction("github.com/testcontainers/testcontainers-go.(*Reaper).connect.func1"),
+		goleak.IgnoreAnyFunction("net/http.(*persistConn).readLoop"),
+		goleak.IgnoreAnyFunction("net/http.(*persistConn).writeLoop"),
+	}
+}
+
+// chdirRepoRoot walks up from the working directory to the directory holding backend-go/migrations.
+func chdirRepoRoot() error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "backend-go", "migrations")); err == nil {
+			return os.Chdir(dir)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return errors.New("backend-go/migrations not found above the working directory")
+		}
+		dir = parent
+	}
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package storage
+
+import (
+    "context"
+    "time"
+
+    "garp-backend/internal/tenant"
+)
+
+// TenantStorage scopes a Storage to one tenant: message and transaction queries only see
+// the tenant's rows, and Redis keys, queues and channels get a "tenant:<id>:" prefix. The
+// tenant travels in the context (tenant.WithID), so calls on the embedded Storage with a
+// tenant context are scoped the same way; TenantStorage just sets it for every call.
+type TenantStorage struct {
+    *Storage
+    tenantID string
+}
+
+func NewTenantStorage(s *Storage, tenantID string) *TenantStorage {
+    return &TenantStorage{Storage: s, tenantID: tenantID}
+}
+
+func (t *TenantStorage) TenantID() string { return t.tenantID }
+
+// Context returns ctx scoped to the tenant, overriding any tenant already set on it.
+func (t *TenantStorage) Context(ctx context.Context) context.Context {
+    return tenant.WithID(ctx, t.tenantID)
+}
+
+// Key returns the tenant's Redis key (or channel) for key.
+func (t *TenantStorage) Key(key string) string { return tenantKey(t.tenantID, key) }
+
+func (t *TenantStorage) CreateMessage(ctx context.Context, sender, recipient string, ciphertext, nonce []byte) (Message, error) {
+    return t.Storage.CreateMessage(t.Context(ctx), sender, recipient, ciphertext, nonce)
+}
+
+func (t *TenantStorage) CreateMessages(ctx context.Context, msgs []MessageInput) ([]Message, error) {
+    return t.Storage.CreateMessages(t.Context(ctx), msgs)
+}
+
+func (t *TenantStorage) ListMessages(ctx context.Context, a, b string, since *time.Time, limit int) ([]Message, error) {
+    return t.Storage.ListMessages(t.Context(ctx), a, b, since, limit)
+}
+
+func (t *TenantStorage) AnchorMessage(ctx context.Context, id int64, block int64) error {
+    return t.Storage.AnchorMessage(t.Context(ctx), id, block)
+}
+
+func (t *TenantStorage) DeleteMessage(ctx context.Context, id int64, requester string) error {
+    return t.Storage.DeleteMessage(t.Context(ctx), id, requester)
+}
+
+func (t *TenantStorage) ListDeletedMessages(ctx context.Context, participant string, since *time.Time) ([]Message, error) {
+    return t.Storage.ListDeletedMessages(t.Context(ctx), participant, since)
+}
+
+func (t *TenantStorage) SaveTx(ctx context.Context, hash string, payload []byte) error {
+    return t.Storage.SaveTx(t.Context(ctx), hash, payload)
+}
+
+func (t *TenantStorage) PopQueuedTxs(ctx context.Context, n int) ([]QueueMessage, error) {
+    return t.Storage.PopQueuedTxs(t.Context(ctx), n)
+}
+
+func (t *TenantStorage) RequeueTx(ctx context.Context, msg QueueMessage) error {
+    return t.Storage.RequeueTx(t.Context(ctx), msg)
+}
+
+func (t *TenantStorage) UpdateTxStatus(ctx context.Context, hash, status string) error {
+    return t.Storage.UpdateTxStatus(t.Context(ctx), hash, status)
+}
+
+// tenantKey prefixes key with the tenant; the default tenant ("") keeps the bare key.
+func tenantKey(tenantID, key string) string {
+    if tenantID == "" { return key }
+    return "tenant:" + tenantID + ":" + key
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+package storage
+
+import (
+    "context"
+    "fmt"
+)
+
+// TokenSupply is the issuance summary of one asset.
+type TokenSupply struct {
+    AssetID string `json:"asset_id"`
+    Issued  int64  `json:"issued"`
+    Burned  int64  `json:"burned"`
+    Supply  int64  `json:"supply"` // Issued - Burned
+}
+
+// RecordIssuance records a confirmed mint of amount units of assetID to recipient.
+// Recording the same txID twice is a no-op, so callers may retry.
+func (s *Storage) RecordIssuance(ctx context.Context, assetID string, amount int64, recipient, txID string) error {
+    _, err := s.PG.Exec(ctx,
+        `INSERT INTO token_issuances (asset_id, kind, amount, recipient, tx_id) VALUES ($1, 'mint', $2, $3, $4)
+         ON CONFLICT (tx_id) DO NOTHING`, assetID, amount, recipient, txID)
+    if err != nil {
+        return fmt.Errorf("failed to record issuance: %w", err)
+    }
+    return nil
+}
+
+// TokenSupply totals the recorded mints and burns of assetID. Unknown assets report zeros.
+func (s *Storage) TokenSupply(ctx context.Context, assetID string) (TokenSupply, error) {
+    out := TokenSupply{AssetID: assetID}
+    err := s.PG.QueryRow(ctx,
+        `SELECT COALESCE(SUM(amount) FILTER (WHERE kind = 'mint'), 0),
+                COALESCE(SUM(amount) FILTER (WHERE kind = 'burn'), 0)
+         FROM token_issuances WHERE asset_id = $1`, assetID).Scan(&out.Issued, &out.Burned)
+    if err != nil {
+        return out, fmt.Errorf("failed to load token supply: %w", err)
+    }
+    out.Supply = out.Issued - out.Burned
+    return out, nil
+}
\ No newline at end of file
@@ -0,0 +1,22 @@
+// Package tenant carries the tenant a request belongs to through its context, so storage
+// can scope rows and Redis keys without depending on the HTTP layer that sets it.
+package tenant
+
+import "context"
+
+type contextKey string
+
+const tenantContextKey contextKey = "tenant_id"
+
+// WithID returns a context scoped to tenantID; storage reads it to isolate rows and keys.
+func WithID(ctx context.Context, tenantID string) context.Context {
+    return context.WithValue(ctx, tenantContextKey, tenantID)
+}
+
+// FromContext returns the tenant set by WithID, or "" (the default tenant) if none is set.
+func FromContext(ctx context.Context) string {
+    if ctx != nil {
+        if id, ok := ctx.Value(tenantContextKey).(string); ok { return id }
+    }
+    return ""
+}
\ No newline at end of file
@@ -0,0 +1,93 @@
+// Package txmonitor follows submitted transactions on the participant node and records their
+// status changes in storage.
+package txmonitor
+
+import (
+    "context"
+    "errors"
+    "log/slog"
+    "time"
+
+    apimodel "garp-backend/internal/api"
+    "garp-backend/internal/client"
+    "garp-backend/internal/storage"
+    "garp-backend/internal/tenant"
+)
+
+// DefaultBatchSize is used when Monitor.BatchSize is zero.
+const DefaultBatchSize = 100
+
+// DefaultMaxChecks is used when Monitor.MaxChecks is zero: about an hour at a 5s interval.
+const DefaultMaxChecks = 720
+
+// Monitor consumes every tenant's storage.TxQueue and polls the participant for each queued transaction
+// until it reaches a final status.
+type Monitor struct {
+    Participant client.ParticipantClientInterface
+    BatchSize   int // transactions checked per tenant per tick; zero uses DefaultBatchSize
+    MaxChecks   int // checks before a transaction is dropped from the queue; zero uses DefaultMaxChecks
+}
+
+// Start checks up to BatchSize queued transactions of each tenant every pollInterval, with
+// the tenant set on the context so updates stay in its rows and channels. A status that differs
+// from the last one seen is written with UpdateTxStatus, which publishes it to the
+// transaction's event stream and runs the OnTxStatus hooks. Transactions that are not yet
+// final go back on the queue. Start blocks until ctx is cancelled and then returns nil.
+func (m *Monitor) Start(ctx context.Context, s *storage.Storage, pollInterval time.Duration) error {
+    if pollInterval <= 0 { return errors.New("tx monitor: poll interval must be positive") }
+    if m.Participant == nil { return errors.New("tx monitor: participant client is required") }
+    batch := m.BatchSize
+    if batch <= 0 { batch = DefaultBatchSize }
+
+    ticker := time.NewTicker(pollInterval)
+    defer ticker.Stop()
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case <-ticker.C:
+        }
+        tenants, err := s.QueuedTxTenants(ctx)
+        if err != nil {
+            slog.Warn("tx monitor: failed to list tenants with queued transactions", "error", err)
+            continue
+        }
+        for _, id := range tenants {
+            tctx := tenant.WithID(ctx, id)
+            msgs, err := s.PopQueuedTxs(tctx, batch)
+            if err != nil {
+                slog.Warn("tx monitor: failed to read the transaction queue", "tenant", id, "error", err)
+                continue
+            }
+            for _, msg := range msgs { m.check(tctx, s, msg) }
+        }
+    }
+}
+
+// final reports whether status is one a transaction never leaves.
+func final(status string) bool { return status == "confirmed" || status == "failed" }
+
+// check polls the participant for one queued transaction and requeues it unless it is final.
+func (m *Monitor) check(ctx context.Context, s *storage.Storage, msg storage.QueueMessage) {
+    var tx apimodel.TransactionInfo
+    if err := m.Participant.GetTransaction(ctx, msg.Hash, &tx); err != nil {
+        slog.Warn("tx monitor: failed to fetch transaction", "hash", msg.Hash, "error", err)
+    } else if tx.Status != nil && *tx.Status != "pending" && *tx.Status != msg.Status {
+        if err := s.UpdateTxStatus(ctx, msg.Hash, *tx.Status); err != nil {
+            slog.Warn("tx monitor: failed to update transaction status", "hash", msg.Hash, "status", *tx.Status, "error", err)
+        } else {
+            msg.Status = *tx.Status
+        }
+    }
+    if final(msg.Status) { return }
+
+    maxChecks := m.MaxChecks
+    if maxChecks <= 0 { maxChecks = DefaultMaxChecks }
+    if msg.Retry+1 >= maxChecks {
+        slog.Warn("tx monitor: giving up on transaction", "hash", msg.Hash, "checks", msg.Retry+1, "status", msg.Status)
+        return
+    }
+    if err := s.RequeueTx(ctx, msg); err != nil {
+        slog.Error("tx monitor: failed to requeue transaction", "hash", msg.Hash, "error", err)
+    }
+}
\ No newline at end of file
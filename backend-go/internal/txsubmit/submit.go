@@ -0,0 +1,138 @@
+// Package txsubmit is the transaction submission path shared by the REST and gRPC APIs:
+// schema validation, deduplication of retries, submission to the participant node, and
+// recording the transaction for the status monitor and the pool.
+package txsubmit
+
+import (
+    "context"
+    "crypto/sha256"
+    "encoding/hex"
+    "encoding/json"
+    "errors"
+    "fmt"
+    "strings"
+    "time"
+
+    apimodel "garp-backend/internal/api"
+    "garp-backend/internal/client"
+    "garp-backend/internal/logger"
+    "garp-backend/internal/middleware"
+    "garp-backend/internal/state"
+    "garp-backend/internal/storage"
+    "garp-backend/schema"
+)
+
+// DedupTTL is how long SubmitOnce remembers a body, as DeduplicateMiddleware does for REST.
+const DedupTTL = 5 * time.Minute
+
+const dedupPending = "pending"
+
+var (
+    // ErrSubmissionFailed wraps errors from the participant node.
+    ErrSubmissionFailed = errors.New("transaction submission failed")
+    // ErrInFlight is returned by SubmitOnce while an identical submission is still running.
+    ErrInFlight = errors.New("duplicate transaction is still being processed")
+)
+
+// ValidationError lists the schema.Transaction keywords a body failed.
+type ValidationError struct {
+    Failures []middleware.ValidationFailure
+}
+
+func (e *ValidationError) Error() string {
+    msgs := make([]string, len(e.Failures))
+    for i, f := range e.Failures { msgs[i] = f.Path + ": " + f.Message }
+    return "transaction failed validation: " + strings.Join(msgs, "; ")
+}
+
+// Submitter submits transaction bodies to the participant and records the accepted ones.
+type Submitter struct {
+    participant client.ParticipantClientInterface
+    store       *storage.Storage
+    pool        *state.TxPool
+    schema      *middleware.BodySchema
+}
+
+// New returns a Submitter that saves accepted transactions to store, where the status monitor
+// picks them up, and adds them to pool. SubmitOnce deduplicates through store's Redis.
+func New(participant client.ParticipantClientInterface, store *storage.Storage, pool *state.TxPool) *Submitter {
+    return &Submitter{participant: participant, store: store, pool: pool, schema: middleware.CompileBodySchema(schema.Transaction)}
+}
+
+// Validate checks raw against schema.Transaction. It returns middleware.ErrInvalidJSON or a
+// *ValidationError.
+func (s *Submitter) Validate(raw []byte) error {
+    failures, err := s.schema.Check(raw)
+    if err != nil { return err }
+    if len(failures) > 0 { return &ValidationError{Failures: failures} }
+    return nil
+}
+
+// Submit validates raw, submits it to the participant and records it. Failing to save or
+// pool an accepted transaction is only logged, since the participant already has it.
+func (s *Submitter) Submit(ctx context.Context, raw []byte) (apimodel.TransactionInfo, error) {
+    if err := s.Validate(raw); err != nil { return apimodel.TransactionInfo{}, err }
+    var body struct {
+        Submitter string `json:"submitter"`
+    }
+    _ = json.Unmarshal(raw, &body) // already validated against the schema
+
+    var tx apimodel.TransactionInfo
+    if err := s.participant.SubmitTransaction(ctx, json.RawMessage(raw), &tx); err != nil {
+        return apimodel.TransactionInfo{}, fmt.Errorf("%w: %w", ErrSubmissionFailed, err)
+    }
+    status := "pending"
+    if tx.Status != nil { status = *tx.Status }
+    middleware.RecordTransactionSubmitted(body.Submitter, status)
+    if tx.ID != "" {
+        log := logger.FromContext(ctx)
+        if err := s.store.SaveTx(ctx, tx.ID, raw); err != nil {
+            log.Warn("failed to store transaction", "id", tx.ID, "error", err)
+        }
+        if err := s.pool.Add(state.Transaction{TxHash: tx.ID, Status: status, CreatedAt: time.Now().UTC()}); err != nil {
+            log.Warn("failed to pool transaction", "id", tx.ID, "error", err)
+        }
+    }
+    return tx, nil
+}
+
+// SubmitOnce is Submit for callers without DeduplicateMiddleware in front: an identical body
+// from the same caller within DedupTTL gets the first result back instead of being submitted
+// again, and ErrInFlight while the first is still running. Failed submissions are not
+// remembered. Redis errors fail open.
+func (s *Submitter) SubmitOnce(ctx context.Context, caller string, raw []byte) (apimodel.TransactionInfo, error) {
+    if s.store == nil || s.store.Redis == nil { return s.Submit(ctx, raw) }
+    rdb := s.store.Redis
+    log := logger.FromContext(ctx)
+    callerSum := sha256.Sum256([]byte(caller))
+    bodySum := sha256.Sum256(raw)
+    key := "dedup:submit:" + hex.EncodeToString(callerSum[:8]) + ":" + hex.EncodeToString(bodySum[:])
+
+    fresh, err := rdb.SetNX(ctx, key, dedupPending, DedupTTL).Result()
+    if err != nil {
+        log.Warn("dedup: redis unavailable, submitting transaction", "error", err)
+        return s.Submit(ctx, raw)
+    }
+    if !fresh {
+        stored, err := rdb.Get(ctx, key).Bytes()
+        if err == nil && string(stored) == dedupPending { return apimodel.TransactionInfo{}, ErrInFlight }
+        var tx apimodel.TransactionInfo
+        if err == nil && json.Unmarshal(stored, &tx) == nil { return tx, nil }
+        // Expired or unreadable; treat it as a new submission
+        return s.Submit(ctx, raw)
+    }
+
+    tx, err := s.Submit(ctx, raw)
+    // Store with a fresh context: the caller's may already be cancelled
+    storeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 2*time.Second)
+    defer cancel()
+    if err != nil {
+        _ = rdb.Del(storeCtx, key).Err()
+        return tx, err
+    }
+    val, _ := json.Marshal(tx)
+    if err := rdb.Set(storeCtx, key, val, DedupTTL).Err(); err != nil {
+        log.Warn("dedup: failed to store transaction", "error", err)
+    }
+    return tx, nil
+}
\ No newline at end of file
@@ -0,0 +1,20 @@
+-- Multi-signature transactions (M-of-N approval before execution)
+CREATE TABLE IF NOT EXISTS multisig_transactions (
+    id          TEXT PRIMARY KEY,
+    commands    JSONB NOT NULL,
+    threshold   INT NOT NULL CHECK (threshold > 0),
+    signers     TEXT[] NOT NULL,
+    digest      TEXT NOT NULL,
+    status      TEXT NOT NULL DEFAULT 'pending',
+    tx_id       TEXT,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    executed_at TIMESTAMPTZ
+);
+
+CREATE TABLE IF NOT EXISTS multisig_signatures (
+    multisig_id TEXT NOT NULL REFERENCES multisig_transactions(id) ON DELETE CASCADE,
+    signer      TEXT NOT NULL,
+    signature   TEXT NOT NULL,
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (multisig_id, signer)
+);
@@ -0,0 +1,13 @@
+-- Smart contract events emitted during transaction execution
+CREATE TABLE IF NOT EXISTS contract_events (
+    event_id     TEXT PRIMARY KEY,
+    contract_id  TEXT NOT NULL,
+    block_number BIGINT NOT NULL,
+    tx_id        TEXT NOT NULL,
+    topic        TEXT NOT NULL,
+    data         JSONB NOT NULL,
+    timestamp    TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_events_contract_block ON contract_events(contract_id, block_number);
+CREATE INDEX IF NOT EXISTS idx_contract_events_topic ON contract_events(contract_id, topic, block_number);
@@ -0,0 +1,16 @@
+-- Chat messages (created here for fresh databases) and soft delete ("unsend") tombstones
+CREATE TABLE IF NOT EXISTS messages (
+    id                 BIGSERIAL PRIMARY KEY,
+    sender             TEXT NOT NULL,
+    recipient          TEXT NOT NULL,
+    content_ciphertext BYTEA NOT NULL,
+    content_nonce      BYTEA NOT NULL,
+    hash               TEXT NOT NULL UNIQUE,
+    created_at         TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    anchored_at_block  BIGINT
+);
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS deleted_by TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_messages_deleted_at ON messages (deleted_at) WHERE deleted_at IS NOT NULL;
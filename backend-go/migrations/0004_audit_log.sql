@@ -0,0 +1,46 @@
+-- Append-only audit trail of state-changing API requests
+CREATE TABLE IF NOT EXISTS audit_log (
+    id                BIGSERIAL PRIMARY KEY,
+    ts                TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    user_id           TEXT,
+    method            TEXT NOT NULL,
+    path              TEXT NOT NULL,
+    request_body_hash TEXT NOT NULL,
+    status_code       INT NOT NULL,
+    ip_address        TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_log_ts ON audit_log (ts);
+CREATE INDEX IF NOT EXISTS idx_audit_log_user ON audit_log (user_id, ts) WHERE user_id IS NOT NULL;
+
+-- Rows can never be changed or removed, not even by the table owner
+CREATE OR REPLACE FUNCTION audit_log_immutable() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_log is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS audit_log_no_modify ON audit_log;
+CREATE TRIGGER audit_log_no_modify BEFORE UPDATE OR DELETE ON audit_log
+    FOR EACH ROW EXECUTE FUNCTION audit_log_immutable();
+
+-- Writer role for the application: insert and read, no UPDATE/DELETE/TRUNCATE grants
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'audit_writer') THEN
+        CREATE ROLE audit_writer NOLOGIN;
+    END IF;
+EXCEPTION WHEN insufficient_privilege THEN
+    RAISE NOTICE 'skipping audit_writer role: insufficient privilege';
+END
+$$;
+
+REVOKE UPDATE, DELETE, TRUNCATE ON audit_log FROM PUBLIC;
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_roles WHERE rolname = 'audit_writer') THEN
+        GRANT SELECT, INSERT ON audit_log TO audit_writer;
+        GRANT USAGE ON SEQUENCE audit_log_id_seq TO audit_writer;
+    END IF;
+END
+$$;
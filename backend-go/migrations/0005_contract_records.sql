@@ -0,0 +1,11 @@
+-- Contracts per owner for account listings (on-chain contracts not yet indexed are merged in by the API)
+CREATE TABLE IF NOT EXISTS contract_records (
+    contract_id  TEXT PRIMARY KEY,
+    template_id  TEXT NOT NULL,
+    owner        TEXT NOT NULL,
+    counterparty TEXT NOT NULL DEFAULT '',
+    status       TEXT NOT NULL,
+    created_at   TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_contract_records_owner ON contract_records(owner, status, created_at);
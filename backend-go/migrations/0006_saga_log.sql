@@ -0,0 +1,12 @@
+-- Step transitions of cross-service sagas, for auditing partial failures and compensations
+CREATE TABLE IF NOT EXISTS saga_log (
+    id          BIGSERIAL PRIMARY KEY,
+    saga_id     TEXT NOT NULL,
+    saga        TEXT NOT NULL,
+    step        TEXT NOT NULL,
+    state       TEXT NOT NULL,
+    error       TEXT NOT NULL DEFAULT '',
+    recorded_at TIMESTAMPTZ NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_saga_log_saga ON saga_log(saga_id, id);
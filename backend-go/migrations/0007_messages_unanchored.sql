@@ -0,0 +1,2 @@
+-- Lets the anchor worker find messages still waiting for a block without scanning anchored history
+CREATE INDEX IF NOT EXISTS idx_messages_unanchored ON messages (id) WHERE anchored_at_block IS NULL AND deleted_at IS NULL;
@@ -0,0 +1,12 @@
+-- Token mints and burns per asset; circulating supply is the sum of mints minus burns
+CREATE TABLE IF NOT EXISTS token_issuances (
+    id         BIGSERIAL PRIMARY KEY,
+    asset_id   TEXT NOT NULL,
+    kind       TEXT NOT NULL CHECK (kind IN ('mint', 'burn')),
+    amount     BIGINT NOT NULL CHECK (amount > 0),
+    recipient  TEXT NOT NULL,
+    tx_id      TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_token_issuances_asset ON token_issuances (asset_id, kind);
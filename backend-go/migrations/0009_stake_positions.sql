@@ -0,0 +1,11 @@
+-- Stake positions opened through the backend; status moves active -> unstaking -> withdrawn
+CREATE TABLE IF NOT EXISTS stake_positions (
+    id         TEXT PRIMARY KEY, -- stake ID assigned by the participant
+    address    TEXT NOT NULL,
+    amount     BIGINT NOT NULL CHECK (amount > 0),
+    validator  TEXT NOT NULL,
+    status     TEXT NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_stake_positions_address ON stake_positions (address, status);
@@ -0,0 +1,11 @@
+-- Off-chain metadata of NFT contracts created through POST /api/v1/nfts
+CREATE TABLE IF NOT EXISTS nft_metadata (
+    contract_id  TEXT PRIMARY KEY,
+    asset_id     TEXT NOT NULL,
+    owner        TEXT NOT NULL,
+    metadata_uri TEXT NOT NULL,
+    supply       BIGINT NOT NULL DEFAULT 1 CHECK (supply > 0),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_nft_metadata_owner ON nft_metadata (owner);
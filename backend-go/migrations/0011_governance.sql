@@ -0,0 +1,22 @@
+-- Governance proposals and the votes cast on them; status moves open -> executed | rejected
+CREATE TABLE IF NOT EXISTS governance_proposals (
+    id              TEXT PRIMARY KEY,
+    proposer        TEXT NOT NULL,
+    description     TEXT NOT NULL,
+    voting_end_slot BIGINT NOT NULL,
+    contract_id     TEXT,  -- on-chain action exercised when the proposal passes
+    choice          TEXT,
+    arguments       JSONB,
+    status          TEXT NOT NULL DEFAULT 'open',
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_governance_proposals_open ON governance_proposals (voting_end_slot) WHERE status = 'open';
+
+CREATE TABLE IF NOT EXISTS governance_votes (
+    proposal_id TEXT NOT NULL REFERENCES governance_proposals (id),
+    voter       TEXT NOT NULL,
+    vote        TEXT NOT NULL CHECK (vote IN ('yes', 'no', 'abstain')),
+    cast_at     TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (proposal_id, voter)
+);
@@ -0,0 +1,7 @@
+-- Per-tenant isolation of chat messages; '' is the default tenant for existing rows
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+
+-- Content hashes only need to be unique within a tenant
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS messages_hash_key;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_messages_tenant_hash ON messages (tenant_id, hash);
+CREATE INDEX IF NOT EXISTS idx_messages_tenant_created ON messages (tenant_id, created_at);
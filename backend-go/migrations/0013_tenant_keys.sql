@@ -0,0 +1,9 @@
+-- Envelope encryption of message content: one data encryption key (DEK) per tenant, stored
+-- wrapped by a key encryption key (KEK) that lives in GCP Secret Manager or Vault
+CREATE TABLE IF NOT EXISTS tenant_keys (
+    tenant_id   TEXT PRIMARY KEY,
+    wrapped_dek BYTEA NOT NULL, -- AES-GCM nonce || sealed DEK
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+ALTER TABLE messages ADD COLUMN IF NOT EXISTS content_envelope BOOLEAN NOT NULL DEFAULT FALSE;
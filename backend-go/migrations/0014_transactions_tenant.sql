@@ -0,0 +1,16 @@
+-- Per-tenant isolation of submitted transactions; '' is the default tenant for existing rows.
+-- SaveTx used to create this table on first use, so older deployments may already have it.
+CREATE TABLE IF NOT EXISTS transactions (
+    tenant_id  TEXT NOT NULL DEFAULT '',
+    tx_hash    TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT NOW(),
+    payload    BYTEA,
+    status     TEXT NOT NULL DEFAULT 'pending'
+);
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS tenant_id TEXT NOT NULL DEFAULT '';
+ALTER TABLE transactions ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'pending';
+
+-- Hashes only need to be unique within a tenant: two tenants may submit the same transaction
+ALTER TABLE transactions DROP CONSTRAINT IF EXISTS transactions_pkey;
+ALTER TABLE transactions ALTER COLUMN tx_hash SET NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_transactions_tenant_hash ON transactions (tenant_id, tx_hash);
@@ -0,0 +1,20 @@
+// Package schema embeds the JSON Schema (draft-07) documents used to validate request bodies.
+package schema
+
+import _ "embed"
+
+// Transaction validates POST /api/v1/transactions bodies (and each item of a batch).
+//go:embed transaction.json
+var Transaction []byte
+
+// Contract validates POST /api/v1/contracts bodies.
+//go:embed contract.json
+var Contract []byte
+
+// WalletTransfer validates POST /api/v1/wallet/transfer bodies.
+//go:embed wallet_transfer.json
+var WalletTransfer []byte
+
+// TokenMint validates POST /api/v1/tokens/mint bodies.
+//go:embed token_mint.json
+var TokenMint []byte
\ No newline at end of file
@@ -2,6 +2,7 @@ package garp
 
 import (
     "bytes"
+    "context"
     "encoding/json"
     "fmt"
     "net/http"
@@ -12,6 +13,7 @@ import (
 type ChatClient struct {
     BaseURL string
     HTTP    *http.Client
+    Token   string // bearer JWT whose subject is the caller's address; required by requests that act on the caller's own messages
 }
 
 func NewChatClient(baseURL string, httpClient *http.Client) *ChatClient {
@@ -68,6 +70,22 @@ func (c *ChatClient) SendMessage(req CreateMessageRequest) (*CreateMessageRespon
     return &out, nil
 }
 
+// DeleteMessage unsends a message the caller sent; the caller is the subject of c.Token.
+// The backend keeps a tombstone for audit.
+func (c *ChatClient) DeleteMessage(ctx context.Context, id int64) error {
+    if c.Token == "" { return fmt.Errorf("chat client token is not set") }
+    httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf("%s/messages/%d", c.BaseURL, id), nil)
+    if err != nil { return err }
+    httpReq.Header.Set("Authorization", "Bearer "+c.Token)
+    resp, err := c.HTTP.Do(httpReq)
+    if err != nil { return &NetworkError{Cause: err} }
+    defer resp.Body.Close()
+    if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+        return fmt.Errorf("HTTP %d", resp.StatusCode)
+    }
+    return nil
+}
+
 func (c *ChatClient) ListMessages(address, peer, since string, limit int) ([]Message, error) {
     q := url.Values{}
     q.Set("address", address)
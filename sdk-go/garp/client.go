@@ -7,6 +7,8 @@ import (
     "errors"
     "fmt"
     "net/http"
+    "net/url"
+    "strconv"
     "time"
 )
 
@@ -83,29 +85,7 @@ type SimulationResult struct {
 }
 
 func (c *Client) rpc(method string, params interface{}, out interface{}) error {
-    body := jsonRpcRequest{Jsonrpc: "2.0", ID: 1, Method: method, Params: params}
-    b, _ := json.Marshal(body)
-    req, err := http.NewRequest("POST", c.BaseURL+"/rpc", bytes.NewReader(b))
-    if err != nil {
-        return err
-    }
-    req.Header.Set("content-type", "application/json")
-    resp, err := c.HTTP.Do(req)
-    if err != nil {
-        return err
-    }
-    defer resp.Body.Close()
-    var jr jsonRpcResponse
-    if err := json.NewDecoder(resp.Body).Decode(&jr); err != nil {
-        return err
-    }
-    if jr.Error != nil {
-        return errors.New(fmt.Sprintf("RPC %s failed (%d): %s", method, jr.Error.Code, jr.Error.Message))
-    }
-    if out == nil {
-        return nil
-    }
-    return json.Unmarshal(jr.Result, out)
+    return c.rpcCtx(context.Background(), method, params, out)
 }
 
 func (c *Client) rpcCtx(ctx context.Context, method string, params interface{}, out interface{}) error {
@@ -118,20 +98,23 @@ func (c *Client) rpcCtx(ctx context.Context, method string, params interface{},
     req.Header.Set("content-type", "application/json")
     resp, err := c.HTTP.Do(req)
     if err != nil {
-        return err
+        return &NetworkError{Cause: err}
     }
     defer resp.Body.Close()
     var jr jsonRpcResponse
     if err := json.NewDecoder(resp.Body).Decode(&jr); err != nil {
-        return err
+        return &DecodeError{Cause: err}
     }
     if jr.Error != nil {
-        return errors.New(fmt.Sprintf("RPC %s failed (%d): %s", method, jr.Error.Code, jr.Error.Message))
+        return &RPCError{Method: method, Code: jr.Error.Code, Message: jr.Error.Message, Data: jr.Error.Data}
     }
     if out == nil {
         return nil
     }
-    return json.Unmarshal(jr.Result, out)
+    if err := json.Unmarshal(jr.Result, out); err != nil {
+        return &DecodeError{Cause: err}
+    }
+    return nil
 }
 
 // Timing & consensus
@@ -300,12 +283,16 @@ type AssetMappingResponse struct {
 
 // InitiateBridgeTransfer initiates a cross-chain asset transfer
 func (c *Client) InitiateBridgeTransfer(req BridgeTransferRequest) (string, error) {
+    return c.InitiateBridgeTransferCtx(context.Background(), req)
+}
+
+func (c *Client) InitiateBridgeTransferCtx(ctx context.Context, req BridgeTransferRequest) (string, error) {
     body, err := json.Marshal(req)
     if err != nil {
         return "", err
     }
 
-    httpReq, err := http.NewRequest("POST", c.BaseURL+"/api/v1/bridge/transfer", bytes.NewReader(body))
+    httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/api/v1/bridge/transfer", bytes.NewReader(body))
     if err != nil {
         return "", err
     }
@@ -313,20 +300,20 @@ func (c *Client) InitiateBridgeTransfer(req BridgeTransferRequest) (string, erro
 
     resp, err := c.HTTP.Do(httpReq)
     if err != nil {
-        return "", err
+        return "", &NetworkError{Cause: err}
     }
     defer resp.Body.Close()
 
     var result BridgeTransferResponse
     if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-        return "", err
+        return "", &DecodeError{Cause: err}
     }
 
     if !result.Success {
         if result.Error != nil {
-            return "", errors.New(*result.Error)
+            return "", &BridgeError{Cause: errors.New(*result.Error)}
         }
-        return "", errors.New("bridge transfer failed")
+        return "", &BridgeError{Cause: errors.New("bridge transfer failed")}
     }
 
     return result.Data.BridgeTxID, nil
@@ -334,8 +321,16 @@ func (c *Client) InitiateBridgeTransfer(req BridgeTransferRequest) (string, erro
 
 // GetBridgeTransferStatus gets the status of a bridge transfer
 func (c *Client) GetBridgeTransferStatus(bridgeTxID string) (string, error) {
+    return c.GetBridgeTransferStatusCtx(context.Background(), bridgeTxID)
+}
+
+func (c *Client) GetBridgeTransferStatusCtx(ctx context.Context, bridgeTxID string) (string, error) {
     url := fmt.Sprintf("%s/api/v1/bridge/transfer/%s/status", c.BaseURL, bridgeTxID)
-    resp, err := c.HTTP.Get(url)
+    httpReq, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+    if err != nil {
+        return "", err
+    }
+    resp, err := c.HTTP.Do(httpReq)
     if err != nil {
         return "", err
     }
@@ -356,6 +351,130 @@ func (c *Client) GetBridgeTransferStatus(bridgeTxID string) (string, error) {
     return result.Data, nil
 }
 
+// BridgeFeeEstimate is the expected cost and duration of a bridge transfer
+type BridgeFeeEstimate struct {
+    BaseFee              int64 `json:"base_fee"`
+    GasFee               int64 `json:"gas_fee"`
+    EstimatedTimeSeconds int   `json:"estimated_time_seconds"`
+}
+
+// EstimateBridgeFee quotes a bridge transfer without committing to it
+func (c *Client) EstimateBridgeFee(ctx context.Context, req BridgeTransferRequest) (*BridgeFeeEstimate, error) {
+    body, err := json.Marshal(req)
+    if err != nil {
+        return nil, err
+    }
+
+    httpReq, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+"/api/v1/bridge/estimate", bytes.NewReader(body))
+    if err != nil {
+        return nil, err
+    }
+    httpReq.Header.Set("content-type", "application/json")
+
+    resp, err := c.HTTP.Do(httpReq)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    var result struct {
+        Success bool              `json:"success"`
+        Data    BridgeFeeEstimate `json:"data"`
+        Error   *string           `json:"error,omitempty"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+        return nil, err
+    }
+
+    if !result.Success {
+        if result.Error != nil {
+            return nil, errors.New(*result.Error)
+        }
+        return nil, errors.New("failed to estimate bridge fee")
+    }
+
+    return &result.Data, nil
+}
+
+// BridgeTransferRecord is a past bridge transfer
+type BridgeTransferRecord struct {
+    BridgeTxID  string `json:"bridge_tx_id"`
+    SourceChain string `json:"source_chain"`
+    TargetChain string `json:"target_chain"`
+    Amount      int64  `json:"amount"`
+    Status      string `json:"status"`
+    CreatedAt   int64  `json:"created_at"`
+    CompletedAt *int64 `json:"completed_at,omitempty"`
+}
+
+// ListBridgeTransfers lists bridge transfers for an address, starting after the given transfer ID
+func (c *Client) ListBridgeTransfers(ctx context.Context, address string, limit int, afterID string) ([]BridgeTransferRecord, error) {
+    q := url.Values{}
+    q.Set("address", address)
+    if limit > 0 { q.Set("limit", strconv.Itoa(limit)) }
+    if afterID != "" { q.Set("after", afterID) }
+
+    httpReq, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+"/api/v1/bridge/transfers?"+q.Encode(), nil)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := c.HTTP.Do(httpReq)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+
+    var result struct {
+        Success bool                   `json:"success"`
+        Data    []BridgeTransferRecord `json:"data"`
+        Error   *string                `json:"error,omitempty"`
+    }
+    if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+        return nil, err
+    }
+
+    if !result.Success {
+        if result.Error != nil {
+            return nil, errors.New(*result.Error)
+        }
+        return nil, errors.New("failed to list bridge transfers")
+    }
+
+    return result.Data, nil
+}
+
+// BridgeTransferPage iterates over an address's bridge transfers page by page
+type BridgeTransferPage struct {
+    client  *Client
+    address string
+    limit   int
+    after   string
+    done    bool
+}
+
+// BridgeTransfers returns an iterator over bridge transfers for an address
+func (c *Client) BridgeTransfers(address string, limit int) *BridgeTransferPage {
+    if limit <= 0 { limit = 50 }
+    return &BridgeTransferPage{client: c, address: address, limit: limit}
+}
+
+// Next fetches the next page. The boolean reports whether more pages may follow.
+func (p *BridgeTransferPage) Next(ctx context.Context) ([]BridgeTransferRecord, bool, error) {
+    if p.done {
+        return nil, false, nil
+    }
+    records, err := p.client.ListBridgeTransfers(ctx, p.address, p.limit, p.after)
+    if err != nil {
+        return nil, false, err
+    }
+    if len(records) < p.limit {
+        p.done = true
+    } else {
+        p.after = records[len(records)-1].BridgeTxID
+    }
+    return records, !p.done, nil
+}
+
 // AddAssetMapping adds an asset mapping between chains
 func (c *Client) AddAssetMapping(req AssetMappingRequest) (bool, error) {
     body, err := json.Marshal(req)
@@ -416,4 +535,79 @@ func (c *Client) GetAssetMapping(sourceChain, sourceAssetID, targetChain string)
     }
 
     return result.Data, nil
+}
+
+// Command is one operation inside a transaction, e.g. {"type": "transfer", "args": {...}}
+type Command struct {
+    Type string                 `json:"type"`
+    Args map[string]interface{} `json:"args,omitempty"`
+}
+
+// MultiSigTransaction is an M-of-N transaction that executes once Threshold signers have signed.
+// Signers are hex ed25519 public keys; Signatures is aligned with Signers ("" until signed)
+// and each signature covers the raw bytes of the hex Digest.
+type MultiSigTransaction struct {
+    ID         string    `json:"id"`
+    Commands   []Command `json:"commands"`
+    Threshold  int       `json:"threshold"`
+    Signers    []string  `json:"signers"`
+    Signatures []string  `json:"signatures"`
+    Digest     string    `json:"digest"`
+    Status     string    `json:"status"`
+    TxID       *string   `json:"tx_id,omitempty"`
+}
+
+// CreateMultiSigTransaction registers a transaction requiring threshold of signers to approve it
+func (c *Client) CreateMultiSigTransaction(ctx context.Context, commands []Command, threshold int, signers []string) (*MultiSigTransaction, error) {
+    if threshold < 1 || threshold > len(signers) {
+        return nil, fmt.Errorf("threshold must be between 1 and %d", len(signers))
+    }
+    req := map[string]interface{}{"commands": commands, "threshold": threshold, "signers": signers}
+    var m MultiSigTransaction
+    if err := c.restCtx(ctx, "POST", "/api/v1/transactions/multisig", req, &m); err != nil {
+        return nil, err
+    }
+    return &m, nil
+}
+
+// SignMultiSigTransaction adds signer's signature over the transaction digest.
+// The returned transaction reports Status "executed" once the threshold was reached.
+func (c *Client) SignMultiSigTransaction(ctx context.Context, id, signer, signature string) (*MultiSigTransaction, error) {
+    req := map[string]string{"signer": signer, "signature": signature}
+    var m MultiSigTransaction
+    if err := c.restCtx(ctx, "POST", "/api/v1/transactions/multisig/"+url.PathEscape(id)+"/sign", req, &m); err != nil {
+        return nil, err
+    }
+    return &m, nil
+}
+
+// restCtx calls a backend REST endpoint that returns the object itself on success
+// and {"error": "..."} otherwise.
+func (c *Client) restCtx(ctx context.Context, method, path string, in interface{}, out interface{}) error {
+    body, err := json.Marshal(in)
+    if err != nil {
+        return err
+    }
+    httpReq, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, bytes.NewReader(body))
+    if err != nil {
+        return err
+    }
+    httpReq.Header.Set("content-type", "application/json")
+
+    resp, err := c.HTTP.Do(httpReq)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+
+    if resp.StatusCode >= 300 {
+        var e struct {
+            Error string `json:"error"`
+        }
+        if json.NewDecoder(resp.Body).Decode(&e) == nil && e.Error != "" {
+            return errors.New(e.Error)
+        }
+        return fmt.Errorf("%s %s returned %d", method, path, resp.StatusCode)
+    }
+    return json.NewDecoder(resp.Body).Decode(out)
 }
\ No newline at end of file
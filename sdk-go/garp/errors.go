@@ -0,0 +1,44 @@
+package garp
+
+import "fmt"
+
+// Error classes returned by Client. Use errors.As to tell them apart:
+//
+//	var rpcErr *garp.RPCError
+//	if errors.As(err, &rpcErr) && rpcErr.Code == -32602 { ... }
+
+// RPCError is a JSON-RPC error object returned by the node.
+type RPCError struct {
+    Method  string
+    Code    int
+    Message string
+    Data    interface{}
+}
+
+func (e *RPCError) Error() string {
+    return fmt.Sprintf("RPC %s failed (%d): %s", e.Method, e.Code, e.Message)
+}
+
+// NetworkError means the request never got a response (connection refused, timeout, cancelled context).
+type NetworkError struct {
+    Cause error
+}
+
+func (e *NetworkError) Error() string { return "network error: " + e.Cause.Error() }
+func (e *NetworkError) Unwrap() error { return e.Cause }
+
+// DecodeError means a response arrived but its body could not be decoded.
+type DecodeError struct {
+    Cause error
+}
+
+func (e *DecodeError) Error() string { return "decode error: " + e.Cause.Error() }
+func (e *DecodeError) Unwrap() error { return e.Cause }
+
+// BridgeError means the bridge service rejected the request.
+type BridgeError struct {
+    Cause error
+}
+
+func (e *BridgeError) Error() string { return "bridge error: " + e.Cause.Error() }
+func (e *BridgeError) Unwrap() error { return e.Cause }
\ No newline at end of file